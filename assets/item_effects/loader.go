@@ -0,0 +1,19 @@
+package item_effects
+
+import (
+	_ "embed"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+//go:embed item_effects.json
+var itemEffectsJson []byte
+
+func Load() *proto.ItemEffectsData {
+	data := &proto.ItemEffectsData{}
+	if err := protojson.Unmarshal(itemEffectsJson, data); err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -11,6 +11,11 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+var (
+	combatLogFile string
+	printAPLStats bool
+)
+
 var simCmd = &cobra.Command{
 	Use:   "sim",
 	Short: "simulate items & settings",
@@ -21,6 +26,8 @@ func init() {
 	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
 	simCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	simCmd.Flags().StringVar(&combatLogFile, "combatlog", "", "if set, writes the first iteration to this file in WoWCombatLog.txt format")
+	simCmd.Flags().BoolVar(&printAPLStats, "apl-stats", false, "print how often each APL priority list item was evaluated, ready and executed per iteration")
 	simCmd.MarkFlagRequired("infile")
 }
 
@@ -36,6 +43,13 @@ func simMain(cmd *cobra.Command, args []string) {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
 
+	if combatLogFile != "" {
+		if input.SimOptions == nil {
+			input.SimOptions = &proto.SimOptions{}
+		}
+		input.SimOptions.CombatLog = true
+	}
+
 	var output []byte
 	reporter := make(chan *proto.ProgressMetrics, 10)
 	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
@@ -51,6 +65,16 @@ func simMain(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if combatLogFile != "" {
+		err = os.WriteFile(combatLogFile, []byte(finalResult.CombatLog), 0666)
+		if err != nil {
+			log.Fatalf("failed to write combat log file: %s", err)
+		}
+		if verbose {
+			fmt.Printf("Wrote combat log file: `%s` successfully.\n", combatLogFile)
+		}
+	}
+
 	output, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(finalResult)
 	if err != nil {
 		log.Fatalf("failed to marshal final results: %s", err)
@@ -67,4 +91,24 @@ func simMain(cmd *cobra.Command, args []string) {
 			fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
 		}
 	}
+
+	if printAPLStats {
+		printAPLExecutionStats(finalResult)
+	}
+}
+
+// Written to stderr, so it doesn't end up in the result JSON when that's printed to stdout.
+func printAPLExecutionStats(result *proto.RaidSimResult) {
+	for _, party := range result.RaidMetrics.Parties {
+		for _, player := range party.Players {
+			if player.RotationStats == nil {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s:\n", player.Name)
+			fmt.Fprintf(os.Stderr, "  %-6s %12s %12s %12s %12s\n", "Item", "Evaluated", "Ready", "Executed", "Interval")
+			for i, item := range player.RotationStats.PriorityList {
+				fmt.Fprintf(os.Stderr, "  #%-5d %12.1f %12.1f %12.1f %11.2fs\n", i+1, item.EvaluationsAvg, item.ReadyAvg, item.ExecutionsAvg, item.SecondsBetweenExecutionsAvg)
+			}
+		}
+	}
 }
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+var (
+	compareIterations int32
+	compareSeed       int64
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <baseline.json> <other.json>...",
+	Short: "compare two or more sim inputs using common random numbers",
+	Long: `compare two or more sim inputs using common random numbers
+
+Every input (RaidSimRequest in protojson format) is simmed with the same seed and
+iteration count, so each iteration of one input sees the same random numbers as
+the same iteration of the others. The deltas against the first input are then
+computed per iteration, which gives much tighter confidence intervals than
+comparing two independent sims.`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  compareMain,
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	compareCmd.Flags().StringVar(&outputFormat, "format", "table", "output format: table, csv or json")
+	compareCmd.Flags().StringVar(&metricName, "metric", "dps", "metric to compare: dps, hps, tps, dtps or tmi")
+	compareCmd.Flags().Int32Var(&compareIterations, "iterations", 0, "iterations per input, defaults to the first input's iteration count")
+	compareCmd.Flags().Int64Var(&compareSeed, "seed", 0, "random seed used for every input, defaults to the first input's seed")
+	compareCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+}
+
+type compareRow struct {
+	File       string  `json:"file"`
+	Mean       float64 `json:"mean"`
+	Delta      float64 `json:"delta"`
+	DeltaPct   float64 `json:"deltaPct"`
+	DeltaStdev float64 `json:"deltaStdev"`
+	DeltaCI    float64 `json:"deltaCI"`
+	// Whether the 95% confidence interval of the delta excludes 0.
+	Significant bool `json:"significant"`
+}
+
+func compareMain(cmd *cobra.Command, args []string) {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		log.Fatal(err)
+	}
+	metric, err := parseMetric(metricName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inputs := make([]*proto.RaidSimRequest, len(args))
+	for i, path := range args {
+		inputs[i] = loadRaidSimRequest(path)
+	}
+
+	// Only DPS and HPS are raid wide, check the others have a player to compare
+	// before spending any time simming.
+	if metric != proto.StatWeightsMetric_StatWeightsMetricDps && metric != proto.StatWeightsMetric_StatWeightsMetricHps {
+		for i, input := range inputs {
+			if player, _ := firstPlayer(input.Raid); player == nil {
+				log.Fatalf("no player found in %s", args[i])
+			}
+		}
+	}
+
+	iterations := compareIterations
+	if iterations <= 0 {
+		iterations = inputs[0].SimOptions.Iterations
+	}
+	if iterations <= 0 {
+		iterations = 3000
+	}
+	seed := compareSeed
+	if seed == 0 {
+		seed = inputs[0].SimOptions.RandomSeed
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	baseline := googleProto.Clone(inputs[0]).(*proto.RaidSimRequest)
+	baseline.SimOptions.Iterations = iterations
+	baseline.SimOptions.RandomSeed = seed
+	baseline.SimOptions.DebugFirstIteration = false
+
+	var rows []compareRow
+	failed := false
+	for i, input := range inputs[1:] {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Simming %s against %s (%d iterations, seed %d)\n", args[i+1], args[0], iterations, seed)
+		}
+		result := core.RunPairedSim(&proto.PairedSimRequest{
+			RequestA: baseline,
+			RequestB: input,
+		})
+		if result.Error != nil {
+			log.Printf("sim failed for %s: %s", args[i+1], result.Error.Message)
+			failed = true
+			continue
+		}
+
+		baselineMean, mean, delta, err := compareMetric(result, metric)
+		if err != nil {
+			log.Printf("failed to compare %s: %s", args[i+1], err)
+			failed = true
+			continue
+		}
+		if len(rows) == 0 {
+			rows = append(rows, compareRow{File: filepath.Base(args[0]), Mean: baselineMean})
+		}
+
+		row := compareRow{
+			File:        filepath.Base(args[i+1]),
+			Mean:        mean,
+			Delta:       delta.Mean,
+			DeltaStdev:  delta.Stdev,
+			DeltaCI:     delta.ConfidenceInterval,
+			Significant: math.Abs(delta.Mean) > delta.ConfidenceInterval,
+		}
+		if baselineMean != 0 {
+			row.DeltaPct = delta.Mean / baselineMean * 100
+		}
+		rows = append(rows, row)
+	}
+
+	header := []string{"File", metricName, "Delta", "Delta %", "Delta 95% CI", "Significant"}
+	writeOutput(rows, header, func(row compareRow) []string {
+		significant := ""
+		if row.Significant {
+			significant = "yes"
+		}
+		return []string{row.File, formatFloat(row.Mean), fmt.Sprintf("%+0.3f", row.Delta), fmt.Sprintf("%+0.2f%%", row.DeltaPct), "±" + formatFloat(row.DeltaCI), significant}
+	})
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Means of the compared metric for both sides, and their paired difference.
+// DPS and HPS are for the whole raid, the others for the first player.
+func compareMetric(result *proto.PairedSimResult, metric proto.StatWeightsMetric) (float64, float64, *proto.PairedDifference, error) {
+	raidA, raidB := result.ResultA.RaidMetrics, result.ResultB.RaidMetrics
+	switch metric {
+	case proto.StatWeightsMetric_StatWeightsMetricDps:
+		return raidA.Dps.Avg, raidB.Dps.Avg, result.RaidDps, nil
+	case proto.StatWeightsMetric_StatWeightsMetricHps:
+		return raidA.Hps.Avg, raidB.Hps.Avg, result.RaidHps, nil
+	}
+
+	playerA, playerB := firstPlayerMetrics(raidA), firstPlayerMetrics(raidB)
+	if len(result.Players) == 0 || playerA == nil || playerB == nil {
+		return 0, 0, nil, fmt.Errorf("no players to compare")
+	}
+	switch metric {
+	case proto.StatWeightsMetric_StatWeightsMetricTps:
+		return playerA.Threat.Avg, playerB.Threat.Avg, result.Players[0].Tps, nil
+	case proto.StatWeightsMetric_StatWeightsMetricDtps:
+		return playerA.Dtps.Avg, playerB.Dtps.Avg, result.Players[0].Dtps, nil
+	}
+	return playerA.Tmi.Avg, playerB.Tmi.Avg, result.Players[0].Tmi, nil
+}
+
+func firstPlayerMetrics(raid *proto.RaidMetrics) *proto.UnitMetrics {
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			if player.Name != "" {
+				return player
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <input.json>",
+	Short: "statically check the APLs of a sim input",
+	Long: `statically check the APLs of a sim input
+
+Reports APL items which parse fine but can't work as intended, e.g. casts which
+an earlier item always preempts, conditions which are always true or false,
+auras which are never registered and strict sequences which can never complete.
+Exits with status 1 if there are any warnings.`,
+	Args: cobra.ExactArgs(1),
+	Run:  lintMain,
+}
+
+func lintMain(cmd *cobra.Command, args []string) {
+	input := loadRaidSimRequest(args[0])
+	result := core.LintAPL(&proto.LintAPLRequest{
+		Raid:      input.Raid,
+		Encounter: input.Encounter,
+	})
+
+	numWarnings := 0
+	for _, player := range result.Players {
+		numWarnings += printLintWarnings(player.Name, "Prepull", player.Warnings.PrepullActions)
+		numWarnings += printLintWarnings(player.Name, "Priority list", player.Warnings.PriorityList)
+	}
+
+	if numWarnings > 0 {
+		fmt.Printf("%d warnings\n", numWarnings)
+		os.Exit(1)
+	}
+	fmt.Println("No warnings")
+}
+
+func printLintWarnings(playerName string, listName string, items []*proto.APLActionStats) int {
+	numWarnings := 0
+	for i, item := range items {
+		for _, warning := range item.Warnings {
+			fmt.Printf("%s: %s item #%d: %s\n", playerName, listName, i+1, warning)
+			numWarnings++
+		}
+	}
+	return numWarnings
+}
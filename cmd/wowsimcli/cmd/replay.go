@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var replaySeed int64
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay a single iteration by its seed",
+	Long:  "replay a single iteration by its seed (e.g. minSeed / maxSeed from a sim result), printing the debug log and metrics",
+	Run:   replayMain,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+	replayCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file for the full result JSON, defaults to printing a summary")
+	replayCmd.Flags().Int64Var(&replaySeed, "seed", 0, "absolute seed of the iteration to replay")
+	replayCmd.MarkFlagRequired("infile")
+	replayCmd.MarkFlagRequired("seed")
+}
+
+func replayMain(cmd *cobra.Command, args []string) {
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", infile, err)
+	}
+	input := &proto.RaidSimRequest{}
+
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+
+	result := core.ReplayIteration(input, replaySeed)
+	if result.Error != nil {
+		log.Fatalf("replay failed: %s", result.Error.Message)
+	}
+
+	fmt.Print(result.Logs)
+
+	if outfile == "" {
+		fmt.Printf("\nSeed: %d, Duration: %0.2fs, Raid DPS: %0.2f, Raid HPS: %0.2f\n", replaySeed, result.FirstIterationDuration, result.RaidMetrics.Dps.Avg, result.RaidMetrics.Hps.Avg)
+		for _, party := range result.RaidMetrics.Parties {
+			for _, player := range party.Players {
+				if player.Name == "" {
+					continue
+				}
+				fmt.Printf("  %s: %0.2f DPS, %0.2f HPS\n", player.Name, player.Dps.Avg, player.Hps.Avg)
+			}
+		}
+		return
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+	if err != nil {
+		log.Fatalf("failed to marshal final results: %s", err)
+	}
+	err = os.WriteFile(outfile, output, 0666)
+	if err != nil {
+		log.Fatalf("failed to write output file:: %s", err)
+	}
+}
@@ -18,6 +18,11 @@ func Execute(version string) {
 	rootCmd.AddCommand(simCmd)
 	rootCmd.AddCommand(bulkCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(statWeightsCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(lintCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
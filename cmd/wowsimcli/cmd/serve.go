@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/sod/cmd/wowsimcli/jobserver"
+)
+
+var (
+	serveHost    string
+	serveJobDir  string
+	serveWorkers int
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "run a headless HTTP/JSON sim server with a persistent job queue",
+	Long: `run a headless HTTP/JSON sim server with a persistent job queue
+
+Endpoints (all bodies are JSON, requests are protojson):
+  POST /jobs/raidSim         queue a RaidSimRequest
+  POST /jobs/bulkSim         queue a BulkSimRequest
+  POST /jobs/statWeights     queue a StatWeightsRequest
+  POST /jobs/gearOptimizer   queue a GearOptimizerRequest
+  POST /jobs/pairedSim       queue a PairedSimRequest
+  GET  /jobs                 list all jobs
+  GET  /jobs/{id}            get a job and its result
+  POST /jobs/{id}/cancel     cancel a queued or running job`,
+	Run: serveMain,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveHost, "host", "localhost:3334", "address to listen on")
+	serveCmd.Flags().StringVar(&serveJobDir, "jobdir", "wowsimcli-jobs", "directory where job state and results are stored")
+	serveCmd.Flags().IntVar(&serveWorkers, "workers", 1, "number of jobs to run at the same time")
+	serveCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+}
+
+func serveMain(cmd *cobra.Command, args []string) {
+	server, err := jobserver.NewServer(serveJobDir, serveWorkers)
+	if err != nil {
+		log.Fatalf("failed to start job server: %s", err)
+	}
+	server.Start()
+
+	if verbose {
+		fmt.Printf("Serving sim jobs on http://%s with %d worker(s), storing jobs in %s\n", serveHost, serveWorkers, serveJobDir)
+	}
+	if err := http.ListenAndServe(serveHost, server.Handler()); err != nil {
+		log.Fatalf("failed to serve: %s", err)
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	statsToWeigh   string
+	epRefStat      string
+	outputFormat   string
+	metricName     string
+	epCIThreshold  float64
+	maxTimeSeconds float64
+)
+
+var statWeightsCmd = &cobra.Command{
+	Use:   "statweights",
+	Short: "compute stat weights and EP values",
+	Long:  "compute stat weights and EP values for the first player of a RaidSimRequest",
+	Run:   statWeightsMain,
+}
+
+func init() {
+	statWeightsCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+	statWeightsCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	statWeightsCmd.Flags().StringVar(&statsToWeigh, "stats", "", "comma separated stats or pseudo stats to weigh, e.g. Strength,AttackPower,MeleeHit")
+	statWeightsCmd.Flags().StringVar(&epRefStat, "ref", "", "reference stat for EP values, defaults to the first stat")
+	statWeightsCmd.Flags().StringVar(&outputFormat, "format", "table", "output format: table, csv or json")
+	statWeightsCmd.Flags().StringVar(&metricName, "metric", "dps", "metric to report: dps, hps, tps, dtps or tmi")
+	statWeightsCmd.Flags().Float64Var(&epCIThreshold, "ci", 0, "if set, keeps adding iterations until every EP's 95% confidence interval is below this value")
+	statWeightsCmd.Flags().Float64Var(&maxTimeSeconds, "max-time", 0, "time budget in seconds when --ci is set, defaults to 60")
+	statWeightsCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	statWeightsCmd.MarkFlagRequired("infile")
+	statWeightsCmd.MarkFlagRequired("stats")
+}
+
+type statWeightRow struct {
+	Stat        string  `json:"stat"`
+	Weight      float64 `json:"weight"`
+	WeightStdev float64 `json:"weightStdev"`
+	Ep          float64 `json:"ep"`
+	EpStdev     float64 `json:"epStdev"`
+	EpCI        float64 `json:"epCI"`
+}
+
+func statWeightsMain(cmd *cobra.Command, args []string) {
+	if err := validateOutputFormat(outputFormat); err != nil {
+		log.Fatal(err)
+	}
+	input := loadRaidSimRequest(infile)
+
+	metric, err := parseMetric(metricName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var unitStats []stats.UnitStat
+	for _, name := range strings.Split(statsToWeigh, ",") {
+		unitStat, err := parseUnitStat(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		unitStats = append(unitStats, unitStat)
+	}
+	refStat := unitStats[0]
+	if epRefStat != "" {
+		if refStat, err = parseUnitStat(epRefStat); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if !refStat.IsStat() {
+		log.Fatalf("reference stat %s must be a stat, not a pseudo stat", unitStatName(refStat))
+	}
+
+	player, party := firstPlayer(input.Raid)
+	if player == nil {
+		log.Fatalf("no player found in %s", infile)
+	}
+	swr := &proto.StatWeightsRequest{
+		Player:          player,
+		RaidBuffs:       input.Raid.Buffs,
+		PartyBuffs:      party.Buffs,
+		Debuffs:         input.Raid.Debuffs,
+		Encounter:       input.Encounter,
+		SimOptions:      input.SimOptions,
+		Tanks:           input.Raid.Tanks,
+		EpReferenceStat: proto.Stat(refStat.StatIdx()),
+	}
+	for _, unitStat := range unitStats {
+		if unitStat.IsStat() {
+			swr.StatsToWeigh = append(swr.StatsToWeigh, proto.Stat(unitStat.StatIdx()))
+		} else {
+			swr.PseudoStatsToWeigh = append(swr.PseudoStatsToWeigh, proto.PseudoStat(unitStat.PseudoStatIdx()))
+		}
+	}
+	if epCIThreshold > 0 {
+		swr.Adaptive = &proto.AdaptiveStatWeightsOptions{
+			MaxEpConfidenceInterval: epCIThreshold,
+			MaxTimeSeconds:          maxTimeSeconds,
+			Metric:                  metric,
+		}
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	core.StatWeightsAsync(swr, reporter, "cmd-stat-weights")
+
+	var result *proto.StatWeightsResult
+	for progress := range reporter {
+		if progress.FinalWeightResult != nil {
+			result = progress.FinalWeightResult
+			break
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Stat Weights Progress: %d / %d sims, %d / %d iterations\n", progress.CompletedSims, progress.TotalSims, progress.CompletedIterations, progress.TotalIterations)
+		}
+	}
+	if result == nil {
+		log.Fatal("stat weights finished without a result")
+	}
+	if result.Error != nil {
+		log.Fatalf("stat weights failed: %s", result.Error.Message)
+	}
+	if verbose && swr.Adaptive != nil {
+		fmt.Fprintf(os.Stderr, "Ran %d iterations per sim, converged: %t\n", result.Iterations, result.Converged)
+	}
+
+	values := metricStatWeights(result, metric)
+	var rows []statWeightRow
+	for _, unitStat := range unitStats {
+		rows = append(rows, statWeightRow{
+			Stat:        unitStatName(unitStat),
+			Weight:      unitStat.GetFromStatsProto(values.Weights),
+			WeightStdev: unitStat.GetFromStatsProto(values.WeightsStdev),
+			Ep:          unitStat.GetFromStatsProto(values.EpValues),
+			EpStdev:     unitStat.GetFromStatsProto(values.EpValuesStdev),
+			EpCI:        unitStat.GetFromStatsProto(values.EpValuesConfidenceInterval),
+		})
+	}
+
+	header := []string{"Stat", "Weight", "Weight Stdev", "EP", "EP Stdev", "EP 95% CI"}
+	writeOutput(rows, header, func(row statWeightRow) []string {
+		return []string{row.Stat, formatFloat(row.Weight), formatFloat(row.WeightStdev), formatFloat(row.Ep), formatFloat(row.EpStdev), "±" + formatFloat(row.EpCI)}
+	})
+}
+
+func loadRaidSimRequest(path string) *proto.RaidSimRequest {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %v", path, err)
+	}
+	input := &proto.RaidSimRequest{}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
+	if err != nil {
+		log.Fatalf("failed to load input json file %q: %s", path, err)
+	}
+	if input.SimOptions == nil {
+		input.SimOptions = &proto.SimOptions{}
+	}
+	return input
+}
+
+// Returns the first player of the raid, along with the party it is in.
+func firstPlayer(raid *proto.Raid) (*proto.Player, *proto.Party) {
+	for _, party := range raid.GetParties() {
+		for _, player := range party.Players {
+			if player.Name != "" {
+				return player, party
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Accepts stat names with or without their enum prefix, in any case, e.g.
+// "AttackPower", "StatAttackPower" or "MainHandDps".
+func parseUnitStat(name string) (stats.UnitStat, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for enumName, value := range proto.Stat_value {
+		if name == strings.ToLower(enumName) || name == strings.ToLower(strings.TrimPrefix(enumName, "Stat")) {
+			return stats.UnitStatFromStat(stats.Stat(value)), nil
+		}
+	}
+	for enumName, value := range proto.PseudoStat_value {
+		if name == strings.ToLower(enumName) || name == strings.ToLower(strings.TrimPrefix(enumName, "PseudoStat")) {
+			return stats.UnitStatFromPseudoStat(proto.PseudoStat(value)), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stat %q", name)
+}
+
+func unitStatName(unitStat stats.UnitStat) string {
+	if unitStat.IsStat() {
+		return stats.Stat(unitStat.StatIdx()).StatName()
+	}
+	return strings.TrimPrefix(proto.PseudoStat(unitStat.PseudoStatIdx()).String(), "PseudoStat")
+}
+
+func parseMetric(name string) (proto.StatWeightsMetric, error) {
+	switch strings.ToLower(name) {
+	case "dps":
+		return proto.StatWeightsMetric_StatWeightsMetricDps, nil
+	case "hps":
+		return proto.StatWeightsMetric_StatWeightsMetricHps, nil
+	case "tps":
+		return proto.StatWeightsMetric_StatWeightsMetricTps, nil
+	case "dtps":
+		return proto.StatWeightsMetric_StatWeightsMetricDtps, nil
+	case "tmi":
+		return proto.StatWeightsMetric_StatWeightsMetricTmi, nil
+	}
+	return 0, fmt.Errorf("unknown metric %q, expected dps, hps, tps, dtps or tmi", name)
+}
+
+func metricStatWeights(result *proto.StatWeightsResult, metric proto.StatWeightsMetric) *proto.StatWeightValues {
+	switch metric {
+	case proto.StatWeightsMetric_StatWeightsMetricHps:
+		return result.Hps
+	case proto.StatWeightsMetric_StatWeightsMetricTps:
+		return result.Tps
+	case proto.StatWeightsMetric_StatWeightsMetricDtps:
+		return result.Dtps
+	case proto.StatWeightsMetric_StatWeightsMetricTmi:
+		return result.Tmi
+	}
+	return result.Dps
+}
+
+func validateOutputFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "table", "csv", "json":
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, csv or json", format)
+}
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%0.3f", v)
+}
+
+// Writes rows to the output file or stdout, as a table, CSV or JSON depending
+// on the --format flag, which must have been checked with validateOutputFormat.
+func writeOutput[T any](rows []T, header []string, toRecord func(T) []string) {
+	out := os.Stdout
+	if outfile != "" {
+		file, err := os.Create(outfile)
+		if err != nil {
+			log.Fatalf("failed to create output file: %s", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rows); err != nil {
+			log.Fatalf("failed to write json output: %s", err)
+		}
+	case "csv":
+		writer := csv.NewWriter(out)
+		writer.Write(header)
+		for _, row := range rows {
+			writer.Write(toRecord(row))
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			log.Fatalf("failed to write csv output: %s", err)
+		}
+	case "table":
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(writer, strings.Join(header, "\t")+"\t")
+		for _, row := range rows {
+			fmt.Fprintln(writer, strings.Join(toRecord(row), "\t")+"\t")
+		}
+		writer.Flush()
+	}
+}
@@ -0,0 +1,371 @@
+// Package jobserver implements a headless HTTP/JSON sim server. Requests are queued as
+// jobs, run on a fixed size worker pool, and persisted to disk along with their results.
+package jobserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Handlers to decode and launch each job type.
+var jobHandlers = map[JobType]jobHandler{
+	JobTypeRaidSim: {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, launch: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunRaidSimConcurrentAsync(msg.(*proto.RaidSimRequest), reporter, requestId)
+	}},
+	JobTypeBulkSim: {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, launch: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
+	}},
+	JobTypeStatWeights: {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, launch: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter, requestId)
+	}},
+	JobTypeGearOptimizer: {msg: func() googleProto.Message { return &proto.GearOptimizerRequest{} }, launch: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunGearOptimizerAsync(msg.(*proto.GearOptimizerRequest), reporter, requestId)
+	}},
+	JobTypePairedSim: {msg: func() googleProto.Message { return &proto.PairedSimRequest{} }, launch: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunPairedSimAsync(msg.(*proto.PairedSimRequest), reporter, requestId)
+	}},
+}
+
+type jobHandler struct {
+	msg    func() googleProto.Message
+	launch func(googleProto.Message, chan *proto.ProgressMetrics, string)
+}
+
+type Server struct {
+	store   *Store
+	workers int
+
+	queueMut  sync.Mutex
+	queueCond *sync.Cond
+	queue     []string
+}
+
+// NewServer opens the job store in dir. Any jobs which were queued or running when the
+// server last stopped are queued again; finished jobs are kept as-is.
+func NewServer(dir string, workers int) (*Server, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("worker count must be at least 1, got %d", workers)
+	}
+
+	store, err := NewStore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		store:   store,
+		workers: workers,
+	}
+	s.queueCond = sync.NewCond(&s.queueMut)
+
+	for _, id := range store.Pending() {
+		requeue := false
+		if _, err := store.Update(id, func(job *Job) {
+			if job.CancelRequested {
+				now := time.Now()
+				job.Status = JobStatusCanceled
+				job.FinishedAt = &now
+				return
+			}
+			job.Status = JobStatusQueued
+			job.StartedAt = nil
+			requeue = true
+		}); err != nil {
+			return nil, err
+		}
+		if requeue {
+			s.enqueue(id)
+		}
+	}
+
+	return s, nil
+}
+
+// Start launches the worker pool. Workers run until the process exits.
+func (s *Server) Start() {
+	for i := 0; i < s.workers; i++ {
+		go s.runWorker()
+	}
+}
+
+func (s *Server) enqueue(id string) {
+	s.queueMut.Lock()
+	s.queue = append(s.queue, id)
+	s.queueMut.Unlock()
+	s.queueCond.Signal()
+}
+
+func (s *Server) dequeue() string {
+	s.queueMut.Lock()
+	defer s.queueMut.Unlock()
+	for len(s.queue) == 0 {
+		s.queueCond.Wait()
+	}
+	id := s.queue[0]
+	s.queue = s.queue[1:]
+	return id
+}
+
+func (s *Server) runWorker() {
+	for {
+		s.runJob(s.dequeue())
+	}
+}
+
+// Submit validates a protojson encoded request and queues it as a new job.
+func (s *Server) Submit(jobType JobType, data []byte) (*Job, error) {
+	handler, ok := jobHandlers[jobType]
+	if !ok {
+		return nil, fmt.Errorf("unknown job type %q", jobType)
+	}
+
+	msg := handler.msg()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s request: %w", jobType, err)
+	}
+	request, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		Id:          uuid.NewString(),
+		Type:        jobType,
+		Status:      JobStatusQueued,
+		SubmittedAt: time.Now(),
+		Request:     request,
+	}
+	if err := s.store.Put(job); err != nil {
+		return nil, err
+	}
+	s.enqueue(job.Id)
+	return job.Summary(), nil
+}
+
+// Get returns the full job, including the request and result payloads.
+func (s *Server) Get(id string) (*Job, bool) {
+	return s.store.Get(id)
+}
+
+// List returns summaries of all known jobs.
+func (s *Server) List() []*Job {
+	return s.store.List()
+}
+
+// Cancel stops a job. Queued jobs are dropped before they start, running jobs are aborted
+// through the signals API. Returns false if the job had already finished.
+func (s *Server) Cancel(id string) (*Job, bool, error) {
+	wasRunning := false
+	triggered := false
+	job, err := s.store.Update(id, func(job *Job) {
+		switch job.Status {
+		case JobStatusQueued:
+			now := time.Now()
+			job.Status = JobStatusCanceled
+			job.FinishedAt = &now
+			triggered = true
+		case JobStatusRunning:
+			job.CancelRequested = true
+			wasRunning = true
+			triggered = true
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if wasRunning {
+		// If the worker hasn't registered the signals yet, it will see CancelRequested once it has.
+		simsignals.AbortById(id)
+	}
+	return job.Summary(), triggered, nil
+}
+
+func (s *Server) runJob(id string) {
+	started := false
+	job, err := s.store.Update(id, func(job *Job) {
+		if job.Status != JobStatusQueued {
+			return
+		}
+		now := time.Now()
+		job.Status = JobStatusRunning
+		job.StartedAt = &now
+		started = true
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to start job %s: %s", id, err.Error())
+		return
+	}
+	if !started {
+		return
+	}
+
+	handler := jobHandlers[job.Type]
+	msg := handler.msg()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(job.Request, msg); err != nil {
+		s.finishJob(id, nil, fmt.Sprintf("failed to parse stored request: %s", err.Error()), false)
+		return
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	handler.launch(msg, reporter, id)
+
+	if current, ok := s.store.Get(id); ok && current.CancelRequested {
+		simsignals.AbortById(id)
+	}
+
+	for progress := range reporter {
+		var result googleProto.Message
+		var outcome *proto.ErrorOutcome
+		if progress.FinalRaidResult != nil {
+			result, outcome = progress.FinalRaidResult, progress.FinalRaidResult.Error
+		} else if progress.FinalBulkResult != nil {
+			result, outcome = progress.FinalBulkResult, progress.FinalBulkResult.Error
+		} else if progress.FinalWeightResult != nil {
+			result, outcome = progress.FinalWeightResult, progress.FinalWeightResult.Error
+		} else if progress.FinalGearOptimizerResult != nil {
+			result, outcome = progress.FinalGearOptimizerResult, progress.FinalGearOptimizerResult.Error
+		} else if progress.FinalPairedResult != nil {
+			result, outcome = progress.FinalPairedResult, progress.FinalPairedResult.Error
+		}
+
+		if result == nil {
+			s.store.SetProgress(id, progress)
+			continue
+		}
+
+		errMessage := ""
+		aborted := false
+		if outcome != nil {
+			aborted = outcome.Type == proto.ErrorOutcomeType_ErrorOutcomeAborted
+			errMessage = outcome.Message
+			if errMessage == "" {
+				errMessage = outcome.Type.String()
+			}
+		}
+		s.finishJob(id, result, errMessage, aborted)
+		return
+	}
+
+	// The reporter closed without a final result, don't leave the job running.
+	s.finishJob(id, nil, "sim ended without a result", false)
+}
+
+func (s *Server) finishJob(id string, result googleProto.Message, errMessage string, aborted bool) {
+	var resultJson []byte
+	if result != nil {
+		var err error
+		resultJson, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(result)
+		if err != nil {
+			errMessage = fmt.Sprintf("failed to marshal result: %s", err.Error())
+		}
+	}
+
+	_, err := s.store.Update(id, func(job *Job) {
+		now := time.Now()
+		job.FinishedAt = &now
+		job.Result = resultJson
+		job.Error = errMessage
+		if aborted || job.CancelRequested {
+			job.Status = JobStatusCanceled
+		} else if errMessage != "" {
+			job.Status = JobStatusFailed
+		} else {
+			job.Status = JobStatusDone
+		}
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to save result of job %s: %s", id, err.Error())
+	}
+}
+
+// Handler returns the HTTP API:
+//
+//	POST /jobs/{raidSim,bulkSim,statWeights,gearOptimizer,pairedSim}  submit a protojson request, returns the new job
+//	GET  /jobs                                                        list all jobs without request / result payloads
+//	GET  /jobs/{id}                                                   fetch a job including its result
+//	POST /jobs/{id}/cancel                                            cancel a queued or running job
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", s.handleJobs)
+	mux.HandleFunc("/jobs/", s.handleJobs)
+	return mux
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs"), "/"), "/")
+	if len(parts) == 1 && parts[0] == "" {
+		parts = nil
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, s.List())
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		if _, ok := jobHandlers[JobType(parts[0])]; !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("unknown job type %q", parts[0]))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		job, err := s.Submit(JobType(parts[0]), body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJson(w, http.StatusAccepted, job)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		job, ok := s.Get(parts[0])
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no job with id %s", parts[0]))
+			return
+		}
+		writeJson(w, http.StatusOK, job)
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+		job, triggered, err := s.Cancel(parts[0])
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJson(w, http.StatusOK, struct {
+			Job          *Job `json:"job"`
+			WasTriggered bool `json:"wasTriggered"`
+		}{job, triggered})
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("invalid endpoint: %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	outbytes, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(outbytes)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, struct {
+		Error string `json:"error"`
+	}{message})
+}
@@ -0,0 +1,114 @@
+package jobserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestJobsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Put(&Job{Id: "done", Type: JobTypeRaidSim, Status: JobStatusDone, Result: json.RawMessage(`{"iterationsDone":10}`)})
+	store.Put(&Job{Id: "running", Type: JobTypeRaidSim, Status: JobStatusRunning, Request: json.RawMessage(`{}`)})
+	store.Put(&Job{Id: "canceling", Type: JobTypeRaidSim, Status: JobStatusRunning, CancelRequested: true})
+
+	// Workers are never started, so nothing gets picked off the queue.
+	s, err := NewServer(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job, ok := s.Get("done"); !ok || job.Status != JobStatusDone || string(job.Result) != `{"iterationsDone":10}` {
+		t.Fatalf("Finished job was not restored: %+v", job)
+	}
+	if job, _ := s.Get("running"); job.Status != JobStatusQueued {
+		t.Fatalf("Expected interrupted job to be queued again, got %s", job.Status)
+	}
+	if job, _ := s.Get("canceling"); job.Status != JobStatusCanceled {
+		t.Fatalf("Expected job with pending cancel to be canceled, got %s", job.Status)
+	}
+	if len(s.queue) != 1 || s.queue[0] != "running" {
+		t.Fatalf("Unexpected queue after restart: %v", s.queue)
+	}
+}
+
+func TestSubmitAndCancel(t *testing.T) {
+	s, err := NewServer(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := s.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/raidSim", bytes.NewBufferString(`{"simOptions":{"iterations":10}}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Submit failed with %d: %s", rec.Code, rec.Body.String())
+	}
+	submitted := &Job{}
+	json.Unmarshal(rec.Body.Bytes(), submitted)
+	if submitted.Status != JobStatusQueued {
+		t.Fatalf("Expected new job to be queued, got %s", submitted.Status)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/"+submitted.Id+"/cancel", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Cancel failed with %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/"+submitted.Id, nil))
+	job := &Job{}
+	json.Unmarshal(rec.Body.Bytes(), job)
+	if job.Status != JobStatusCanceled {
+		t.Fatalf("Expected job to be canceled, got %s", job.Status)
+	}
+	if string(job.Request) != `{"simOptions":{"iterations":10}}` {
+		t.Fatalf("Unexpected stored request: %s", job.Request)
+	}
+
+	// The worker must skip jobs canceled while queued.
+	s.runJob(s.dequeue())
+	if job, _ := s.Get(submitted.Id); job.Status != JobStatusCanceled || job.StartedAt != nil {
+		t.Fatalf("Canceled job was started: %+v", job)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/unknownType", bytes.NewBufferString(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected unknown job type to 404, got %d", rec.Code)
+	}
+}
+
+func TestJobWithoutResultFails(t *testing.T) {
+	const jobType JobType = "noResult"
+	jobHandlers[jobType] = jobHandler{msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, launch: func(_ googleProto.Message, reporter chan *proto.ProgressMetrics, _ string) {
+		reporter <- &proto.ProgressMetrics{TotalIterations: 10, CompletedIterations: 5}
+		close(reporter)
+	}}
+	defer delete(jobHandlers, jobType)
+
+	s, err := NewServer(t.TempDir(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	submitted, err := s.Submit(jobType, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.runJob(s.dequeue())
+	if job, _ := s.Get(submitted.Id); job.Status != JobStatusFailed || job.Error == "" || job.FinishedAt == nil {
+		t.Fatalf("Expected job without a result to fail, got %+v", job)
+	}
+}
@@ -0,0 +1,200 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+type JobType string
+
+const (
+	JobTypeRaidSim       JobType = "raidSim"
+	JobTypeBulkSim       JobType = "bulkSim"
+	JobTypeStatWeights   JobType = "statWeights"
+	JobTypeGearOptimizer JobType = "gearOptimizer"
+	JobTypePairedSim     JobType = "pairedSim"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued   JobStatus = "queued"
+	JobStatusRunning  JobStatus = "running"
+	JobStatusDone     JobStatus = "done"
+	JobStatusFailed   JobStatus = "failed"
+	JobStatusCanceled JobStatus = "canceled"
+)
+
+// Finished returns true if a job in this status will never be picked up by a worker again.
+func (status JobStatus) Finished() bool {
+	return status == JobStatusDone || status == JobStatusFailed || status == JobStatusCanceled
+}
+
+// Job is the persisted state of a single queued sim. Request and Result hold the
+// protojson encoding of the request / result messages for the job type.
+type Job struct {
+	Id     string    `json:"id"`
+	Type   JobType   `json:"type"`
+	Status JobStatus `json:"status"`
+
+	SubmittedAt time.Time  `json:"submittedAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+
+	CompletedIterations int32 `json:"completedIterations"`
+	TotalIterations     int32 `json:"totalIterations"`
+	CompletedSims       int32 `json:"completedSims"`
+	TotalSims           int32 `json:"totalSims"`
+
+	CancelRequested bool   `json:"cancelRequested,omitempty"`
+	Error           string `json:"error,omitempty"`
+
+	Request json.RawMessage `json:"request,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+}
+
+// Summary returns a copy of the job without the request and result payloads.
+func (job *Job) Summary() *Job {
+	summary := *job
+	summary.Request = nil
+	summary.Result = nil
+	return &summary
+}
+
+// Store keeps every job in memory and mirrors it to one JSON file per job in dir,
+// so that queued jobs and finished results survive a restart.
+type Store struct {
+	dir string
+
+	mu   sync.RWMutex
+	jobs map[string]*Job
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create job directory %q: %w", dir, err)
+	}
+
+	store := &Store{
+		dir:  dir,
+		jobs: map[string]*Job{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job directory %q: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job file %q: %w", entry.Name(), err)
+		}
+		job := &Job{}
+		if err := json.Unmarshal(data, job); err != nil {
+			return nil, fmt.Errorf("failed to parse job file %q: %w", entry.Name(), err)
+		}
+		store.jobs[job.Id] = job
+	}
+
+	return store, nil
+}
+
+// Put inserts or replaces a job and writes it to disk.
+func (store *Store) Put(job *Job) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.jobs[job.Id] = job
+	return store.write(job)
+}
+
+// Update applies fn to the stored job while holding the store lock, then writes it to disk.
+func (store *Store) Update(id string, fn func(job *Job)) (*Job, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	job, ok := store.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("no job with id %s", id)
+	}
+	fn(job)
+	jobCopy := *job
+	return &jobCopy, store.write(job)
+}
+
+// SetProgress updates the progress counters of a job in memory only; progress is not worth a disk write.
+func (store *Store) SetProgress(id string, progress *proto.ProgressMetrics) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if job, ok := store.jobs[id]; ok {
+		job.CompletedIterations = progress.CompletedIterations
+		job.TotalIterations = progress.TotalIterations
+		job.CompletedSims = progress.CompletedSims
+		job.TotalSims = progress.TotalSims
+	}
+}
+
+// Get returns a copy of the job with the given id.
+func (store *Store) Get(id string) (*Job, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	job, ok := store.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	jobCopy := *job
+	return &jobCopy, true
+}
+
+// List returns summaries of all jobs, oldest first.
+func (store *Store) List() []*Job {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	jobs := make([]*Job, 0, len(store.jobs))
+	for _, job := range store.jobs {
+		jobs = append(jobs, job.Summary())
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].SubmittedAt.Equal(jobs[j].SubmittedAt) {
+			return jobs[i].Id < jobs[j].Id
+		}
+		return jobs[i].SubmittedAt.Before(jobs[j].SubmittedAt)
+	})
+	return jobs
+}
+
+// Pending returns the ids of all unfinished jobs, oldest first. Used to re-queue work after a restart.
+func (store *Store) Pending() []string {
+	var ids []string
+	for _, job := range store.List() {
+		if !job.Status.Finished() {
+			ids = append(ids, job.Id)
+		}
+	}
+	return ids
+}
+
+// write must be called with store.mu held.
+func (store *Store) write(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash mid-write never leaves a truncated job behind.
+	path := filepath.Join(store.dir, job.Id+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"github.com/wowsims/sod/assets/item_effects"
+	"github.com/wowsims/sod/sim/common/itemhelpers"
+	"github.com/wowsims/sod/sim/core"
+)
+
+// Items with standard procs are described in assets/item_effects/item_effects.json rather than in Go.
+func init() {
+	core.AddEffectsToTest = false
+
+	itemhelpers.RegisterItemEffectsData(item_effects.Load())
+
+	core.AddEffectsToTest = true
+}
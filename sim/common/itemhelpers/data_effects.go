@@ -0,0 +1,197 @@
+package itemhelpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+// Registers the item effects described by data, so items with standard procs don't need any Go code.
+// Panics if an effect is malformed, as this runs during init.
+func RegisterItemEffectsData(data *proto.ItemEffectsData) {
+	for _, effect := range data.Procs {
+		if err := validateItemProcEffect(effect); err != nil {
+			panic(fmt.Sprintf("Invalid item effect %s: %s", effect.Name, err))
+		}
+
+		for _, itemID := range effect.ItemIds {
+			switch effectType := effect.Effect.(type) {
+			case *proto.ItemProcEffect_StatBuff:
+				registerItemProcStatBuff(itemID, effect, effectType.StatBuff)
+			case *proto.ItemProcEffect_Damage:
+				registerItemProcDamage(itemID, effect, effectType.Damage)
+			}
+		}
+	}
+}
+
+func validateItemProcEffect(effect *proto.ItemProcEffect) error {
+	if len(effect.ItemIds) == 0 {
+		return fmt.Errorf("no item IDs")
+	}
+	if (effect.ProcChance == 0) == (effect.Ppm == 0) {
+		return fmt.Errorf("exactly one of proc_chance and ppm must be set")
+	}
+	if effect.Ppm != 0 && effect.Callback == proto.ItemProcCallback_ItemProcCallbackOnCastComplete {
+		return fmt.Errorf("cast complete procs can't use ppm")
+	}
+
+	switch effectType := effect.Effect.(type) {
+	case *proto.ItemProcEffect_StatBuff:
+		if len(effectType.StatBuff.Stats) == 0 || effectType.StatBuff.DurationSeconds <= 0 {
+			return fmt.Errorf("stat buffs need stats and a duration")
+		}
+	case *proto.ItemProcEffect_Damage:
+		if effectType.Damage.MaxDamage < effectType.Damage.MinDamage {
+			return fmt.Errorf("max_damage is lower than min_damage")
+		}
+	default:
+		return fmt.Errorf("no effect")
+	}
+	return nil
+}
+
+func registerItemProcStatBuff(itemID int32, effect *proto.ItemProcEffect, statBuff *proto.ItemProcStatBuff) {
+	bonus := stats.Stats{}
+	for _, stat := range statBuff.Stats {
+		bonus[stats.Stat(stat.Stat)] += stat.Value
+	}
+
+	newProcStatBonusEffect(ProcStatBonusEffect{
+		Name:              effect.Name,
+		ID:                itemID,
+		AuraID:            effect.SpellId,
+		Bonus:             bonus,
+		Duration:          durationFromSeconds(statBuff.DurationSeconds),
+		Callback:          itemProcCallback(effect.Callback),
+		ProcMask:          itemProcMask(effect.ProcMask),
+		Outcome:           itemProcOutcome(effect.Outcome),
+		ProcChance:        effect.ProcChance,
+		PPM:               effect.Ppm,
+		ICD:               durationFromSeconds(effect.IcdSeconds),
+		UseItemProcMask:   effect.ProcMask == proto.ItemProcMask_ItemProcMaskWeaponSlot,
+		SpellFlagsExclude: itemProcSpellFlagsExclude(effect),
+		AuraLabel:         effect.AuraLabel,
+	})
+}
+
+func registerItemProcDamage(itemID int32, effect *proto.ItemProcEffect, damage *proto.ItemProcDamage) {
+	school := core.SpellSchoolFromProto(damage.School)
+	defType := itemProcDefenseType(damage.DefenseType)
+	dmgRange := damage.MaxDamage - damage.MinDamage
+
+	// Most weapon procs fit the existing helper exactly.
+	if effect.ProcMask == proto.ItemProcMask_ItemProcMaskWeaponSlot && effect.Ppm != 0 && effect.IcdSeconds == 0 &&
+		effect.Callback == proto.ItemProcCallback_ItemProcCallbackOnSpellHitDealt && effect.Outcome == proto.ItemProcOutcome_ItemProcOutcomeLanded {
+		CreateWeaponProcDamage(itemID, effect.Name, effect.Ppm, effect.SpellId, school, damage.MinDamage, dmgRange, damage.BonusCoefficient, defType, itemProcSpellFlagsExclude(effect))
+		return
+	}
+
+	core.NewItemEffect(itemID, func(agent core.Agent) {
+		character := agent.GetCharacter()
+
+		procMask := itemProcMask(effect.ProcMask)
+		if effect.ProcMask == proto.ItemProcMask_ItemProcMaskWeaponSlot {
+			procMask = character.GetProcMaskForItem(itemID)
+			if procMask == core.ProcMaskUnknown {
+				return
+			}
+		}
+
+		procSpell := registerProcDamageSpell(character, effect.Name, effect.SpellId, school, damage.MinDamage, dmgRange, damage.BonusCoefficient, defType)
+
+		core.MakeProcTriggerAura(&character.Unit, core.ProcTrigger{
+			ActionID:          core.ActionID{ItemID: itemID},
+			Name:              effect.Name,
+			Callback:          itemProcCallback(effect.Callback),
+			ProcMask:          procMask,
+			SpellFlagsExclude: itemProcSpellFlagsExclude(effect),
+			Outcome:           itemProcOutcome(effect.Outcome),
+			ProcChance:        effect.ProcChance,
+			PPM:               effect.Ppm,
+			ICD:               durationFromSeconds(effect.IcdSeconds),
+			Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+				switch {
+				case effect.Callback == proto.ItemProcCallback_ItemProcCallbackOnSpellHitTaken:
+					procSpell.Cast(sim, spell.Unit)
+				case result != nil:
+					procSpell.Cast(sim, result.Target)
+				default:
+					procSpell.Cast(sim, character.CurrentTarget)
+				}
+			},
+		})
+	})
+}
+
+func durationFromSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func itemProcSpellFlagsExclude(effect *proto.ItemProcEffect) core.SpellFlag {
+	if effect.IgnoreProcSuppression {
+		return core.SpellFlagNone
+	}
+	if effect.ChanceOnHit {
+		return core.SpellFlagSuppressWeaponProcs
+	}
+	return core.SpellFlagSuppressEquipProcs
+}
+
+// The weapon slot mask depends on where the item is equipped, so it's resolved by the caller.
+func itemProcMask(procMask proto.ItemProcMask) core.ProcMask {
+	switch procMask {
+	case proto.ItemProcMask_ItemProcMaskMelee:
+		return core.ProcMaskMelee
+	case proto.ItemProcMask_ItemProcMaskRanged:
+		return core.ProcMaskRanged
+	case proto.ItemProcMask_ItemProcMaskMeleeOrRanged:
+		return core.ProcMaskMeleeOrRanged
+	case proto.ItemProcMask_ItemProcMaskSpellDamage:
+		return core.ProcMaskSpellDamage
+	case proto.ItemProcMask_ItemProcMaskSpellHealing:
+		return core.ProcMaskSpellHealing
+	case proto.ItemProcMask_ItemProcMaskDirect:
+		return core.ProcMaskDirect
+	}
+	return core.ProcMaskUnknown
+}
+
+func itemProcCallback(callback proto.ItemProcCallback) core.AuraCallback {
+	switch callback {
+	case proto.ItemProcCallback_ItemProcCallbackOnSpellHitTaken:
+		return core.CallbackOnSpellHitTaken
+	case proto.ItemProcCallback_ItemProcCallbackOnPeriodicDamageDealt:
+		return core.CallbackOnPeriodicDamageDealt
+	case proto.ItemProcCallback_ItemProcCallbackOnHealDealt:
+		return core.CallbackOnHealDealt
+	case proto.ItemProcCallback_ItemProcCallbackOnCastComplete:
+		return core.CallbackOnCastComplete
+	}
+	return core.CallbackOnSpellHitDealt
+}
+
+func itemProcOutcome(outcome proto.ItemProcOutcome) core.HitOutcome {
+	switch outcome {
+	case proto.ItemProcOutcome_ItemProcOutcomeCrit:
+		return core.OutcomeCrit
+	case proto.ItemProcOutcome_ItemProcOutcomeAny:
+		return core.OutcomeEmpty
+	}
+	return core.OutcomeLanded
+}
+
+func itemProcDefenseType(defenseType proto.ItemProcDefenseType) core.DefenseType {
+	switch defenseType {
+	case proto.ItemProcDefenseType_ItemProcDefenseTypeMelee:
+		return core.DefenseTypeMelee
+	case proto.ItemProcDefenseType_ItemProcDefenseTypeRanged:
+		return core.DefenseTypeRanged
+	case proto.ItemProcDefenseType_ItemProcDefenseTypeNone:
+		return core.DefenseTypeNone
+	}
+	return core.DefenseTypeMagic
+}
@@ -0,0 +1,224 @@
+package itemhelpers
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/assets/item_effects"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+func testStatBuffEffect(itemIDs ...int32) *proto.ItemProcEffect {
+	return &proto.ItemProcEffect{
+		ItemIds:    itemIDs,
+		Name:       "Test Stat Buff",
+		Callback:   proto.ItemProcCallback_ItemProcCallbackOnSpellHitTaken,
+		ProcMask:   proto.ItemProcMask_ItemProcMaskMelee,
+		ProcChance: 0.02,
+		Effect: &proto.ItemProcEffect_StatBuff{
+			StatBuff: &proto.ItemProcStatBuff{
+				Stats:           []*proto.ItemProcStat{{Stat: proto.Stat_StatStrength, Value: 25}},
+				DurationSeconds: 10,
+			},
+		},
+	}
+}
+
+func testDamageEffect(itemIDs ...int32) *proto.ItemProcEffect {
+	return &proto.ItemProcEffect{
+		ItemIds:     itemIDs,
+		Name:        "Test Damage",
+		Ppm:         1,
+		ChanceOnHit: true,
+		Effect: &proto.ItemProcEffect_Damage{
+			Damage: &proto.ItemProcDamage{
+				School:    proto.SpellSchool_SpellSchoolFire,
+				MinDamage: 100,
+				MaxDamage: 150,
+			},
+		},
+	}
+}
+
+func TestValidateItemProcEffect(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(effect *proto.ItemProcEffect)
+		valid  bool
+	}{
+		{name: "valid", modify: func(effect *proto.ItemProcEffect) {}, valid: true},
+		{name: "no item IDs", modify: func(effect *proto.ItemProcEffect) { effect.ItemIds = nil }},
+		{name: "no chance", modify: func(effect *proto.ItemProcEffect) { effect.ProcChance = 0 }},
+		{name: "both chance and ppm", modify: func(effect *proto.ItemProcEffect) { effect.Ppm = 1 }},
+		{name: "ppm on cast complete", modify: func(effect *proto.ItemProcEffect) {
+			effect.ProcChance = 0
+			effect.Ppm = 1
+			effect.Callback = proto.ItemProcCallback_ItemProcCallbackOnCastComplete
+		}},
+		{name: "no stats", modify: func(effect *proto.ItemProcEffect) { effect.GetStatBuff().Stats = nil }},
+		{name: "no duration", modify: func(effect *proto.ItemProcEffect) { effect.GetStatBuff().DurationSeconds = 0 }},
+		{name: "no effect", modify: func(effect *proto.ItemProcEffect) { effect.Effect = nil }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			effect := testStatBuffEffect(1)
+			test.modify(effect)
+			if err := validateItemProcEffect(effect); (err == nil) != test.valid {
+				t.Errorf("Expected valid: %t, got error: %v", test.valid, err)
+			}
+		})
+	}
+
+	if err := validateItemProcEffect(testDamageEffect(1)); err != nil {
+		t.Errorf("Expected a valid damage effect, got error: %v", err)
+	}
+	invalidDamage := testDamageEffect(1)
+	invalidDamage.GetDamage().MaxDamage = 50
+	if err := validateItemProcEffect(invalidDamage); err == nil {
+		t.Errorf("Expected an error for max damage lower than min damage")
+	}
+}
+
+func TestItemEffectsDataIsValid(t *testing.T) {
+	for _, effect := range item_effects.Load().Procs {
+		if err := validateItemProcEffect(effect); err != nil {
+			t.Errorf("Invalid item effect %s: %v", effect.Name, err)
+		}
+	}
+}
+
+func TestRegisterItemEffectsData(t *testing.T) {
+	// IDs which no real item uses, as effects can only be registered once.
+	RegisterItemEffectsData(&proto.ItemEffectsData{
+		Procs: []*proto.ItemProcEffect{
+			testStatBuffEffect(-1001, -1002),
+			testDamageEffect(-1003),
+		},
+	})
+
+	for _, itemID := range []int32{-1001, -1002, -1003} {
+		if !core.HasItemEffect(itemID) {
+			t.Errorf("Expected an effect for item %d", itemID)
+		}
+	}
+}
+
+func TestRegisterItemEffectsDataPanicsOnInvalidEffect(t *testing.T) {
+	invalid := testStatBuffEffect(-1004)
+	invalid.ProcChance = 0
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an invalid effect")
+		}
+		if core.HasItemEffect(-1004) {
+			t.Errorf("Expected no effect to be registered for an invalid effect")
+		}
+	}()
+	RegisterItemEffectsData(&proto.ItemEffectsData{Procs: []*proto.ItemProcEffect{invalid}})
+}
+
+type procTestAgent struct {
+	core.Character
+	spell *core.Spell
+}
+
+func (agent *procTestAgent) GetCharacter() *core.Character { return &agent.Character }
+func (agent *procTestAgent) ApplyTalents()                 {}
+func (agent *procTestAgent) ApplyRunes()                   {}
+func (agent *procTestAgent) Reset(_ *core.Simulation)      {}
+func (agent *procTestAgent) OnGCDReady(_ *core.Simulation) {}
+
+func (agent *procTestAgent) Initialize() {
+	// Suppresses equip procs, to check effects which ignore proc suppression.
+	agent.spell = agent.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 42},
+		SpellSchool: core.SpellSchoolShadow,
+		ProcMask:    core.ProcMaskSpellDamage,
+		Flags:       core.SpellFlagSuppressEquipProcs,
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, target, 1, spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func init() {
+	core.RegisterAgentFactory(
+		proto.Player_ElementalShaman{},
+		proto.Spec_SpecElementalShaman,
+		func(character *core.Character, _ *proto.Player) core.Agent {
+			return &procTestAgent{Character: *character}
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_ElementalShaman)
+		},
+	)
+}
+
+func TestItemEffectsDataStatBuffProc(t *testing.T) {
+	const itemID = -1005
+
+	var wrathOfCenarius *proto.ItemProcEffect
+	for _, effect := range item_effects.Load().Procs {
+		if effect.Name == "Wrath of Cenarius" {
+			wrathOfCenarius = effect
+		}
+	}
+	if wrathOfCenarius == nil {
+		t.Fatalf("Expected a Wrath of Cenarius effect in the item effects data")
+	}
+	wrathOfCenarius.ItemIds = []int32{itemID}
+	RegisterItemEffectsData(&proto.ItemEffectsData{Procs: []*proto.ItemProcEffect{wrathOfCenarius}})
+
+	equipment := &proto.EquipmentSpec{Items: make([]*proto.ItemSpec, proto.ItemSlot_ItemSlotTrinket1+1)}
+	for i := range equipment.Items {
+		equipment.Items[i] = &proto.ItemSpec{}
+	}
+	equipment.Items[proto.ItemSlot_ItemSlotTrinket1].Id = itemID
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: equipment,
+					Database: &proto.SimDatabase{
+						Items: []*proto.SimItem{{Id: itemID, Name: "Test Trinket", Type: proto.ItemType_ItemTypeTrinket}},
+					},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 100},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	agent := sim.Raid.Parties[0].Players[0].(*procTestAgent)
+	aura := agent.GetAura("Spell Blasting")
+	if aura == nil {
+		t.Fatalf("Expected the proc aura to keep its Spell Blasting label")
+	}
+	spellDamage := agent.GetStat(stats.SpellDamage)
+
+	// The proc chance is 5%, so this procs well within the casts.
+	for i := 0; i < 500 && !aura.IsActive(); i++ {
+		agent.spell.Cast(sim, sim.GetTargetUnit(0))
+	}
+	if !aura.IsActive() {
+		t.Fatalf("Expected the stat buff to proc from spells which suppress equip procs")
+	}
+	if bonus := agent.GetStat(stats.SpellDamage) - spellDamage; bonus != 193 {
+		t.Errorf("Expected the proc to add 193 spell damage, got %0.1f", bonus)
+	}
+}
@@ -21,8 +21,15 @@ type ProcStatBonusEffect struct {
 	PPM        float64
 	ICD        time.Duration
 
+	// Restricts the proc to the weapon slots the item is equipped in, instead of ProcMask.
+	UseItemProcMask   bool
+	SpellFlagsExclude core.SpellFlag
+
 	// For ignoring a hardcoded spell.
 	IgnoreSpellID int32
+
+	// Label of the proc aura, defaults to Name + " Proc".
+	AuraLabel string
 }
 
 func newProcStatBonusEffect(config ProcStatBonusEffect) {
@@ -33,7 +40,11 @@ func newProcStatBonusEffect(config ProcStatBonusEffect) {
 		if procID.IsEmptyAction() {
 			procID = core.ActionID{ItemID: config.ID}
 		}
-		procAura := character.NewTemporaryStatsAura(config.Name+" Proc", procID, config.Bonus, config.Duration)
+		auraLabel := config.AuraLabel
+		if auraLabel == "" {
+			auraLabel = config.Name + " Proc"
+		}
+		procAura := character.NewTemporaryStatsAura(auraLabel, procID, config.Bonus, config.Duration)
 
 		handler := func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
 			procAura.Activate(sim)
@@ -47,17 +58,26 @@ func newProcStatBonusEffect(config ProcStatBonusEffect) {
 			}
 		}
 
+		procMask := config.ProcMask
+		if config.UseItemProcMask {
+			procMask = character.GetProcMaskForItem(config.ID)
+			if procMask == core.ProcMaskUnknown {
+				return
+			}
+		}
+
 		triggerAura := core.MakeProcTriggerAura(&character.Unit, core.ProcTrigger{
-			ActionID:   core.ActionID{ItemID: config.ID},
-			Name:       config.Name,
-			Callback:   config.Callback,
-			ProcMask:   config.ProcMask,
-			Outcome:    config.Outcome,
-			Harmful:    config.Harmful,
-			ProcChance: config.ProcChance,
-			PPM:        config.PPM,
-			ICD:        config.ICD,
-			Handler:    handler,
+			ActionID:          core.ActionID{ItemID: config.ID},
+			Name:              config.Name,
+			Callback:          config.Callback,
+			ProcMask:          procMask,
+			SpellFlagsExclude: config.SpellFlagsExclude,
+			Outcome:           config.Outcome,
+			Harmful:           config.Harmful,
+			ProcChance:        config.ProcChance,
+			PPM:               config.PPM,
+			ICD:               config.ICD,
+			Handler:           handler,
 		})
 		procAura.Icd = triggerAura.Icd
 	})
@@ -13,48 +13,7 @@ func CreateWeaponProcDamage(itemId int32, itemName string, ppm float64, spellId
 	core.NewItemEffect(itemId, func(agent core.Agent) {
 		character := agent.GetCharacter()
 
-		sc := core.SpellConfig{
-			ActionID:    core.ActionID{SpellID: spellId},
-			SpellSchool: school,
-			DefenseType: defType,
-			ProcMask:    core.ProcMaskEmpty,
-			Flags:       core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
-
-			DamageMultiplier: 1,
-			ThreatMultiplier: 1,
-			BonusCoefficient: bonusCoef,
-		}
-
-		switch defType {
-		case core.DefenseTypeNone:
-			sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-				dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
-				spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeAlwaysHit)
-			}
-		case core.DefenseTypeMagic:
-			sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-				dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
-				spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeMagicHitAndCrit)
-			}
-		case core.DefenseTypeMelee:
-			// "Phantom Strike Procs"
-			// Can proc itself (Only for CoH proc), can't proc equip effects (in SoD at least - Tested), Weapon Enchants (confirmed - procs fiery), can proc imbues (oils),
-			// WildStrikes/Windfury (Wound/ Phantom Strike can't proc WF/WS in SoD, Tested for both, Appear to behave like equip affects in SoD)
-			sc.ProcMask = core.ProcMaskMeleeSpecial
-			sc.Flags = core.SpellFlagSuppressEquipProcs
-
-			sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-				dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
-				spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeMeleeSpecialHitAndCrit)
-			}
-		case core.DefenseTypeRanged:
-			sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-				dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
-				spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeRangedHitAndCrit)
-			}
-		}
-
-		procSpell := character.RegisterSpell(sc)
+		procSpell := registerProcDamageSpell(character, itemName, spellId, school, dmgMin, dmgRange, bonusCoef, defType)
 		procMask := character.GetProcMaskForItem(itemId)
 		ppmm := character.AutoAttacks.NewPPMManager(ppm, procMask)
 
@@ -141,3 +100,51 @@ func CreateWeaponProcAura(itemId int32, itemName string, ppm float64, procAuraGe
 		}))
 	})
 }
+
+// Registers a spell dealing dmgMin to dmgMin+dmgRange damage, rolled and resolved according to defType.
+func registerProcDamageSpell(character *core.Character, itemName string, spellId int32, school core.SpellSchool,
+	dmgMin float64, dmgRange float64, bonusCoef float64, defType core.DefenseType) *core.Spell {
+
+	sc := core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: spellId},
+		SpellSchool: school,
+		DefenseType: defType,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		BonusCoefficient: bonusCoef,
+	}
+
+	switch defType {
+	case core.DefenseTypeNone:
+		sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
+			spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeAlwaysHit)
+		}
+	case core.DefenseTypeMagic:
+		sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
+			spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeMagicHitAndCrit)
+		}
+	case core.DefenseTypeMelee:
+		// "Phantom Strike Procs"
+		// Can proc itself (Only for CoH proc), can't proc equip effects (in SoD at least - Tested), Weapon Enchants (confirmed - procs fiery), can proc imbues (oils),
+		// WildStrikes/Windfury (Wound/ Phantom Strike can't proc WF/WS in SoD, Tested for both, Appear to behave like equip affects in SoD)
+		sc.ProcMask = core.ProcMaskMeleeSpecial
+		sc.Flags = core.SpellFlagSuppressEquipProcs
+
+		sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
+			spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeMeleeSpecialHitAndCrit)
+		}
+	case core.DefenseTypeRanged:
+		sc.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			dmg := dmgMin + core.TernaryFloat64(dmgRange > 0, sim.RandomFloat(itemName)*dmgRange, 0)
+			spell.CalcAndDealDamage(sim, target, dmg, spell.OutcomeRangedHitAndCrit)
+		}
+	}
+
+	return character.RegisterSpell(sc)
+}
@@ -419,18 +419,11 @@ func init() {
 		})
 	})
 
-	// https://www.wowhead.com/classic/item=228603/blackhand-doomsaw
-	// Chance on hit: Wounds the target for 324 to 540 damage.
-	// TODO: Proc rate based on the original item
-	itemhelpers.CreateWeaponCoHProcDamage(BlackhandDoomsaw, "Blackhand Doomsaw", 0.4, 16549, core.SpellSchoolPhysical, 324, 216, 0, core.DefenseTypeMelee)
-
 	// https://www.wowhead.com/classic/item=220569/blistering-ragehammer
 	// Chance on hit: Increases damage done by 20 and attack speed by 5% for 15 sec.
 	// TODO: Proc rate assumed and needs testing
 	itemhelpers.CreateWeaponProcAura(BlisteringRagehammer, "Blistering Ragehammer", 1.0, EnrageAura446327)
 
-	itemhelpers.CreateWeaponCoHProcDamage(BloodletterScalpel, "Bloodletter Scalpel", 1.0, 18081, core.SpellSchoolPhysical, 60, 10, 0, core.DefenseTypeMelee)
-
 	itemhelpers.CreateWeaponProcSpell(Bloodrazor, "Bloodrazor", 1.0, func(character *core.Character) *core.Spell {
 		return character.GetOrRegisterSpell(core.SpellConfig{
 			ActionID:         core.ActionID{SpellID: 17504},
@@ -482,37 +475,11 @@ func init() {
 		})
 	})
 
-	// https://www.wowhead.com/classic/item=228586/chillpike
-	// Chance on hit: Blasts a target for 160 to 250 Frost damage.
-	// TODO: Proc rate assumed and needs testing
-	itemhelpers.CreateWeaponCoHProcDamage(Chillpike, "Chillpike", 1.0, 19260, core.SpellSchoolFrost, 160, 90, 0, core.DefenseTypeMagic)
-
-	// https://www.wowhead.com/classic/item=17068/deathbringer
-	// Chance on hit: Sends a shadowy bolt at the enemy causing 110 to 140 Shadow damage.
-	itemhelpers.CreateWeaponCoHProcDamage(Deathbringer, "Deathbringer", 1.0, 18138, core.SpellSchoolShadow, 110, 30, 0, core.DefenseTypeMagic)
-
-	// https://www.wowhead.com/classic/item=230271/drake-talon-cleaver
-	// Chance on hit: Delivers a fatal wound for 300 damage.
-	// Original proc rate 1.0 increased to approximately 1.60 in SoD phase 5
-	itemhelpers.CreateWeaponCoHProcDamage(DrakeTalonCleaver, "Drake Talon Cleaver", 1.0, 467167, core.SpellSchoolPhysical, 300, 0, 0.0, core.DefenseTypeMelee) // TBD confirm 1 ppm in SoD
-	// https://www.wowhead.com/classic/item=232562/drake-talon-cleaver
-	itemhelpers.CreateWeaponCoHProcDamage(DrakeTalonCleaverShadowflame, "Drake Talon Cleaver", 1.0, 467167, core.SpellSchoolPhysical, 300, 0, 0.0, core.DefenseTypeMelee) // TBD confirm 1 ppm in SoD
-
 	// https://www.wowhead.com/classic/item=228410/dreadblade-of-the-destructor
 	// https://www.wowhead.com/classic/item=228498/dreadblade-of-the-destructor
 	// TODO: Proc rate assumed and needs testing
 	itemhelpers.CreateWeaponProcSpell(DreadbladeOfTheDestructor, "Dreadblade of the Destructor", 1.0, dreadbladeOfTheDestructorEffect)
 
-	// https://www.wowhead.com/classic/item=227842/ebon-fist
-	// Chance on hit: Sends a shadowy bolt at the enemy causing 125 to 275 Shadow damage.
-	// TODO: Proc rate assumed and needs testing
-	itemhelpers.CreateWeaponCoHProcDamage(EbonFist, "Ebon Fist", 1.0, 18211, core.SpellSchoolShadow, 125, 150, 0, core.DefenseTypeMagic)
-
-	// https://www.wowhead.com/classic/item=19170/ebon-hand
-	// Chance on hit: Sends a shadowy bolt at the enemy causing 125 to 275 Shadow damage.
-	// TODO: Proc rate assumed and needs testing
-	itemhelpers.CreateWeaponCoHProcDamage(EbonHand, "Ebon Hand", 1.0, 18211, core.SpellSchoolShadow, 125, 150, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=227993/ebon-hilt-of-marduk
 	// Chance on hit: Corrupts the target, causing 210 damage over 3 sec.
 	// TODO: Proc rate assumed and needs testing
@@ -978,8 +945,6 @@ func init() {
 		}
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(GryphonRidersStormhammer, "Gryphon Rider's Stormhammer", 1.0, 18081, core.SpellSchoolNature, 91, 34, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=228267/gutgore-ripper
 	// Chance on hit: Sends a shadowy bolt at the enemy causing 150 Shadow damage and lowering all stats by 25 for 30 sec.
 	itemhelpers.CreateWeaponProcSpell(GutgoreRipper, "Gutgore Ripper", 1.0, gutgoreRipperEffect)
@@ -1016,15 +981,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(GutRipper, "Gut Ripper", 1.0, 18107, core.SpellSchoolPhysical, 95, 26, 0, core.DefenseTypeMelee)
-
-	// https://www.wowhead.com/classic/item=230991/halberd-of-smiting
-	// Equip: Chance to decapitate the target on a melee swing, causing 452 to 676 damage.
-	itemhelpers.CreateWeaponEquipProcDamage(HalberdOfSmiting, "Halberd of Smiting", 2.1, 467819, core.SpellSchoolPhysical, 452, 224, 0.0, core.DefenseTypeMelee)         // Works as phantom strike
-	itemhelpers.CreateWeaponEquipProcDamage(HalberdOfSmitingBloodied, "Halberd of Smiting", 2.1, 467819, core.SpellSchoolPhysical, 452, 224, 0.0, core.DefenseTypeMelee) // Works as phantom strike
-
-	itemhelpers.CreateWeaponCoHProcDamage(HammerOfTheNorthernWind, "Hammer of the Northern Wind", 3.5, 13439, core.SpellSchoolFrost, 20, 10, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=2243/hand-of-edward-the-odd
 	// Chance on hit: Next spell cast within 4 sec will cast instantly.
 	itemhelpers.CreateWeaponProcAura(HandOfEdwardTheOdd, "Hand of Edward the Odd", 1.0, func(character *core.Character) *core.Aura {
@@ -1044,8 +1000,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(HanzoSword, "Hanzo Sword", 1.0, 16405, core.SpellSchoolPhysical, 75, 0, 0, core.DefenseTypeMelee)
-
 	// https://www.wowhead.com/classic/item=228022/headmasters-charge#comments
 	// Use: Gives 20 additional intellect to party members within 30 yards. (10 Min Cooldown)
 	// Originally did not stack with Arcane Intellect, but is reported to stack in SoD
@@ -1200,19 +1154,6 @@ func init() {
 		})
 	})
 
-	// https://www.wowhead.com/classic/item=230911/jekliks-crusher
-	// Chance on hit: Wounds the target for 200 to 220 damage.
-	// Original proc rate 4.0 lowered to 1.5 in SoD phase 5
-	itemhelpers.CreateWeaponCoHProcDamage(JekliksCrusher, "Jeklik's Crusher", 1.5, 467642, core.SpellSchoolPhysical, 200, 20, 0.0, core.DefenseTypeMelee)
-	itemhelpers.CreateWeaponCoHProcDamage(JekliksCrusherBloodied, "Jeklik's Crusher", 1.5, 467642, core.SpellSchoolPhysical, 200, 20, 0.0, core.DefenseTypeMelee)
-
-	itemhelpers.CreateWeaponCoHProcDamage(JoonhosMercy, "Joonho's Mercy", 1.0, 20883, core.SpellSchoolArcane, 70, 0, 0, core.DefenseTypeMagic)
-
-	itemhelpers.CreateWeaponCoHProcDamage(KalimdorsRevenge, "Kalimdor's Revenge", 12, 1213355, core.SpellSchoolNature, 339, 38, 0.15, core.DefenseTypeMagic)
-	itemhelpers.CreateWeaponCoHProcDamage(KalimdorsRevengeVoidTouched, "Kalimdor's Revenge", 12, 1213355, core.SpellSchoolNature, 339, 38, 0.15, core.DefenseTypeMagic)
-
-	itemhelpers.CreateWeaponCoHProcDamage(LinkensSwordOfMastery, "Linken's Sword of Mastery", 1.0, 18089, core.SpellSchoolNature, 45, 30, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=227940/lord-generals-sword
 	// Chance on hit: Increases attack power by 50 for 30 sec.
 	// // TODO: Proc rate assumed and needs testing
@@ -1317,16 +1258,12 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(Nightblade, "Nightblade", 1.0, 18211, core.SpellSchoolShadow, 125, 150, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=19169/nightfall
 	// Removed from SoD
 	// core.NewItemEffect(Nightfall, func(agent core.Agent) {
 	// 	makeNightfallProc(agent.GetCharacter(), "Nightfall")
 	// })
 
-	itemhelpers.CreateWeaponCoHProcDamage(PendulumOfDoom, "Pendulum of Doom", 0.5, 10373, core.SpellSchoolPhysical, 250, 100, 0, core.DefenseTypeMelee)
-
 	core.NewItemEffect(PipsSkinner, func(agent core.Agent) {
 		character := agent.GetCharacter()
 
@@ -1335,11 +1272,6 @@ func init() {
 		}
 	})
 
-	// https://www.wowhead.com/classic/item=228296/perditions-blade
-	// Chance on hit: Blasts a target for 98 to 122 Fire damage.
-	itemhelpers.CreateWeaponCoHProcDamage(PerditionsBlade, "Perdition's Blade", 2.8, 461695, core.SpellSchoolFire, 98, 24, 0, core.DefenseTypeMagic)
-	itemhelpers.CreateWeaponCoHProcDamage(PerditionsBladeMolten, "Perdition's Blade", 2.8, 461695, core.SpellSchoolFire, 98, 24, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=231277/pitchfork-of-madness
 	// +141 Attack Power when fighting Demons.
 	core.NewItemEffect(PitchforkOfMadness, func(agent core.Agent) {
@@ -1495,11 +1427,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(SatyrsLash, "Satyr's Lash", 1.0, 18205, core.SpellSchoolShadow, 55, 30, 0, core.DefenseTypeMagic)
-
-	// TODO Searing Needle adds an "Apply Aura: Mod Damage Done (Fire): 10" aura to the /target/, buffing it; not currently modelled
-	itemhelpers.CreateWeaponCoHProcDamage(SearingNeedle, "Searing Needle", 1.0, 16454, core.SpellSchoolFire, 60, 0, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=228666/seeping-willow
 	// Chance on hit: Lowers all stats by 20 and deals 20 Nature damage every 3 sec to all enemies within an 8 yard radius of the caster for 30 sec.
 	// TODO: Proc rate assumed and needs testing
@@ -1589,8 +1516,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(Shadowblade, "Shadowblade", 1.0, 18138, core.SpellSchoolShadow, 110, 30, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=228272/shadowstrike
 	// Chance on hit: Steals 180 to 220 life from target enemy.
 	// Estimated based on data from WoW Armaments Discord
@@ -1612,8 +1537,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(ShortswordOfVengeance, "Shortsword of Vengeance", 1.0, 13519, core.SpellSchoolHoly, 30, 0, 0, core.DefenseTypeMagic)
-
 	// https://www.wowhead.com/classic/item=228542/skullforge-reaver
 	// Equip: Drains target for 2 Shadow damage every 1 sec and transfers it to the caster. Lasts for 30 sec.
 	// Estimated based on data from WoW Armaments Discord
@@ -1966,8 +1889,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(TheNeedler, "The Needler", 3.0, 13060, core.SpellSchoolPhysical, 75, 0, 0, core.DefenseTypeMelee)
-
 	// https://www.wowhead.com/classic/item=230242/the-untamed-blade
 	// Chance on hit: Increases Strength by 300 for 8 sec.
 	// Estimated based on data from WoW Armaments Discord
@@ -2189,10 +2110,6 @@ func init() {
 		})
 	})
 
-	itemhelpers.CreateWeaponCoHProcDamage(VilerendSlicer, "Vilerend Slicer", 1.0, 16405, core.SpellSchoolPhysical, 75, 0, 0, core.DefenseTypeMelee)
-
-	itemhelpers.CreateWeaponCoHProcDamage(ViskagTheBloodletter, "Vis'kag the Bloodletter", 0.6, 21140, core.SpellSchoolPhysical, 240, 0, 0, core.DefenseTypeMelee)
-
 	// https://www.wowhead.com/classic/item=227941/wraith-scythe
 	// Chance on hit: Steals 45 life from target enemy.
 	itemhelpers.CreateWeaponProcSpell(WraithScythe, "Wraith Scythe", 1.0, func(character *core.Character) *core.Spell {
@@ -2214,11 +2131,6 @@ func init() {
 		})
 	})
 
-	// https://www.wowhead.com/classic/item=230930/zulian-slicer
-	// Chance on hit: Slices the enemy for 72 to 96 Nature damage.
-	itemhelpers.CreateWeaponCoHProcDamage(ZulianSlicer, "Zulian Slicer", 1.2, 467738, core.SpellSchoolNature, 72, 24, 0.35, core.DefenseTypeMelee)
-	itemhelpers.CreateWeaponCoHProcDamage(ZulianSlicerBloodied, "Zulian Slicer", 1.2, 467738, core.SpellSchoolNature, 72, 24, 0.35, core.DefenseTypeMelee)
-
 	///////////////////////////////////////////////////////////////////////////
 	//                                 Trinkets
 	///////////////////////////////////////////////////////////////////////////
@@ -2563,45 +2475,6 @@ func init() {
 	// 	}
 	// })
 
-	core.NewItemEffect(MarkOfTheChosen, func(agent core.Agent) {
-		character := agent.GetCharacter()
-		statIncrease := float64(25)
-		markProcChance := 0.02
-
-		procAura := character.GetOrRegisterAura(core.Aura{
-			Label:    "Mark of the Chosen Effect",
-			ActionID: core.ActionID{SpellID: 21970},
-			Duration: time.Minute,
-			OnGain: func(aura *core.Aura, sim *core.Simulation) {
-				character.AddStatsDynamic(sim, stats.Stats{
-					stats.Stamina:   statIncrease,
-					stats.Agility:   statIncrease,
-					stats.Strength:  statIncrease,
-					stats.Intellect: statIncrease,
-					stats.Spirit:    statIncrease,
-				})
-			},
-			OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-				character.AddStatsDynamic(sim, stats.Stats{
-					stats.Stamina:   -statIncrease,
-					stats.Agility:   -statIncrease,
-					stats.Strength:  -statIncrease,
-					stats.Intellect: -statIncrease,
-					stats.Spirit:    -statIncrease,
-				})
-			},
-		})
-
-		core.MakePermanent(character.GetOrRegisterAura(core.Aura{
-			Label: "Mark of the Chosen",
-			OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-				if result.Landed() && spell.ProcMask.Matches(core.ProcMaskMelee) && sim.RandomFloat("Mark of the Chosen") < markProcChance {
-					procAura.Activate(sim)
-				}
-			},
-		}))
-	})
-
 	// https://www.wowhead.com/classic/item=231271/nat-pagles-broken-reel
 	core.NewSimpleStatOffensiveTrinketEffect(NatPaglesBrokenReel, stats.Stats{
 		stats.SpellHit: 10 * core.SpellHitRatingPerHitChance,
@@ -2999,35 +2872,6 @@ func init() {
 		})
 	})
 
-	// https://www.wowhead.com/classic/item=234463/wrath-of-cenarius
-	// Gives a chance when your harmful spells land to increase the damage of your spells and effects by 193 for 10 sec.
-	// (Proc chance: 5%)
-	core.NewItemEffect(WrathOfCenarius, func(agent core.Agent) {
-		character := agent.GetCharacter()
-		buffAura := character.RegisterAura(core.Aura{
-			ActionID: core.ActionID{SpellID: 1214279},
-			Label:    "Spell Blasting",
-			Duration: time.Second * 10,
-			OnGain: func(aura *core.Aura, sim *core.Simulation) {
-				character.AddStatDynamic(sim, stats.SpellDamage, 193)
-			},
-			OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-				character.AddStatDynamic(sim, stats.SpellDamage, -193)
-			},
-		})
-
-		core.MakeProcTriggerAura(&agent.GetCharacter().Unit, core.ProcTrigger{
-			Name:       "Spell Blasting Trigger",
-			Callback:   core.CallbackOnSpellHitDealt,
-			Outcome:    core.OutcomeLanded,
-			ProcMask:   core.ProcMaskSpellDamage,
-			ProcChance: 0.05,
-			Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-				buffAura.Activate(sim)
-			},
-		})
-	})
-
 	core.AddEffectsToTest = true
 }
 
@@ -4,6 +4,7 @@ package core
 import (
 	"github.com/wowsims/sod/sim/core/proto"
 	"github.com/wowsims/sod/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 /**
@@ -85,6 +86,33 @@ func RunRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.Progres
 	}()
 }
 
+/**
+ * Runs a single iteration with the given absolute seed, e.g. DistributionMetrics.MinSeed
+ * from a previous result, with debug logs enabled. The iteration uses the same RNG state
+ * as it did in the original sim. Seed 0 is rejected, as it would make the sim pick a
+ * random seed instead.
+ */
+func ReplayIteration(request *proto.RaidSimRequest, seed int64) *proto.RaidSimResult {
+	if seed == 0 {
+		return &proto.RaidSimResult{
+			Error: &proto.ErrorOutcome{
+				Message: "Can't replay an iteration without its seed",
+			},
+		}
+	}
+
+	replayRequest := googleProto.Clone(request).(*proto.RaidSimRequest)
+	if replayRequest.SimOptions == nil {
+		replayRequest.SimOptions = &proto.SimOptions{}
+	}
+	replayRequest.SimOptions.Iterations = 1
+	replayRequest.SimOptions.RandomSeed = seed
+	replayRequest.SimOptions.Debug = true
+	replayRequest.SimOptions.Interactive = false
+	replayRequest.SimOptions.Timeline = nil
+	return RunSim(replayRequest, nil, simsignals.CreateSignals())
+}
+
 // Threading does not work in WASM!
 func RunRaidSimConcurrent(request *proto.RaidSimRequest) *proto.RaidSimResult {
 	return runSimConcurrent(request, nil, simsignals.CreateSignals())
@@ -131,6 +159,53 @@ func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.Progres
 	}()
 }
 
+func RunPairedSim(request *proto.PairedSimRequest) *proto.PairedSimResult {
+	return runPairedSim(request, nil, simsignals.CreateSignals())
+}
+
+func RunPairedSimAsync(request *proto.PairedSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalPairedResult: &proto.PairedSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runPairedSim(request, progress, signals)
+		progress <- &proto.ProgressMetrics{
+			FinalPairedResult: result,
+		}
+	}()
+}
+
+func RunGearOptimizer(request *proto.GearOptimizerRequest) *proto.GearOptimizerResult {
+	return OptimizeGear(simsignals.CreateSignals(), request, nil)
+}
+
+func RunGearOptimizerAsync(request *proto.GearOptimizerRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalGearOptimizerResult: &proto.GearOptimizerResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		OptimizeGear(signals, request, progress)
+	}()
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestReplayIterationReproducesMinAndMaxDps(t *testing.T) {
+	spellID := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 42}}
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation: &proto.APLRotation{
+						Type: proto.APLRotation_TypeAPL,
+						PriorityList: []*proto.APLListItem{{Action: &proto.APLAction{
+							Condition: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{
+								Val: &proto.APLValue{Value: &proto.APLValue_DotIsActive{DotIsActive: &proto.APLValueDotIsActive{SpellId: spellID}}},
+							}}},
+							Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: spellID}},
+						}}},
+					},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+			// The damage of the dot is fixed, so the fight length is what varies.
+			DurationVariation: 10,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			RandomSeed: 1234,
+			IsTest:     true,
+		},
+	}
+	result := RunRaidSim(request)
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	dps := result.RaidMetrics.Dps
+	if dps.Min == dps.Max {
+		t.Fatalf("Expected iterations to differ, all had %0.3f DPS", dps.Min)
+	}
+
+	for _, iteration := range []struct {
+		seed int64
+		dps  float64
+	}{{dps.MinSeed, dps.Min}, {dps.MaxSeed, dps.Max}} {
+		replay := ReplayIteration(request, iteration.seed)
+		if replay.Error != nil {
+			t.Fatal(replay.Error.Message)
+		}
+		if actual := replay.RaidMetrics.Dps.Avg; actual != iteration.dps {
+			t.Errorf("Replaying seed %d: expected %0.3f DPS, got %0.3f", iteration.seed, iteration.dps, actual)
+		}
+	}
+
+	if replay := ReplayIteration(request, 0); replay.Error == nil {
+		t.Errorf("Expected replaying seed 0 to fail")
+	}
+}
@@ -32,11 +32,18 @@ type APLRotation struct {
 	// Used to avoid recursive APL loops.
 	inLoop bool
 
+	// User-defined variables and named expressions, by name.
+	variables          map[string]*aplVariable
+	namedExpressions   map[string]*aplNamedExpression
+	parsingExpressions []*aplNamedExpression
+
 	// Validation warnings that occur during proto parsing.
 	// We return these back to the user for display in the UI.
-	curWarnings          []string
-	prepullWarnings      [][]string
-	priorityListWarnings [][]string
+	curWarnings             []string
+	prepullWarnings         [][]string
+	priorityListWarnings    [][]string
+	variableWarnings        [][]string
+	namedExpressionWarnings [][]string
 }
 
 func (rot *APLRotation) ValidationWarning(message string, vals ...interface{}) {
@@ -80,6 +87,10 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 		priorityListWarnings: make([][]string, len(config.PriorityList)),
 	}
 
+	// Parse definitions, which the actions below can reference.
+	rotation.parseVariables(config.Variables)
+	rotation.parseNamedExpressions(config.NamedExpressions)
+
 	// Parse prepull actions
 	for i, prepullItem := range config.PrepullActions {
 		prepullIdx := i // Save to local variable for correct lambda capture behavior
@@ -115,6 +126,7 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 			if !aplItem.Hide {
 				action := rotation.newAPLAction(aplItem.Action)
 				if action != nil {
+					action.stats = &aplActionStats{configIdx: i}
 					rotation.priorityList = append(rotation.priorityList, action)
 					configIdxs = append(configIdxs, i)
 				}
@@ -150,11 +162,27 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 }
 func (rot *APLRotation) getStats() *proto.APLStats {
 	return &proto.APLStats{
-		PrepullActions: MapSlice(rot.prepullWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
-		PriorityList:   MapSlice(rot.priorityListWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
+		PrepullActions:   MapSlice(rot.prepullWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
+		PriorityList:     MapSlice(rot.priorityListWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
+		Variables:        MapSlice(rot.variableWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
+		NamedExpressions: MapSlice(rot.namedExpressionWarnings, func(warnings []string) *proto.APLActionStats { return &proto.APLActionStats{Warnings: warnings} }),
 	}
 }
 
+// Same as getStats, with the execution statistics of the priority list items over numIterations.
+func (rot *APLRotation) getMetricsProto(numIterations int) *proto.APLStats {
+	stats := rot.getStats()
+	if numIterations > 0 {
+		for _, actionStats := range stats.PriorityList {
+			actionStats.AggregatorData = &proto.AggregatorData{}
+		}
+		for _, action := range rot.priorityList {
+			action.stats.fillProto(stats.PriorityList[action.stats.configIdx], numIterations)
+		}
+	}
+	return stats
+}
+
 // Returns all action objects as an unstructured list. Used for easily finding specific actions.
 func (rot *APLRotation) allAPLActions() []*APLAction {
 	return Flatten(MapSlice(rot.priorityList, func(action *APLAction) []*APLAction { return action.GetAllActions() }))
@@ -170,12 +198,16 @@ func (rot *APLRotation) reset(sim *Simulation) {
 	rot.inLoop = false
 	rot.interruptChannelIf = nil
 	rot.allowChannelRecastOnInterrupt = false
+	rot.resetVariables()
 	rot.allowCastWhileChanneling = slices.ContainsFunc(rot.unit.Spellbook, func(spell *Spell) bool {
 		return spell.Flags.Matches(SpellFlagCastWhileChanneling)
 	})
 	for _, action := range rot.allAPLActions() {
 		action.impl.Reset(sim)
 	}
+	for _, action := range rot.priorityList {
+		action.stats.reset()
+	}
 }
 
 // We intentionally try to mimic the behavior of simc APL to avoid confusion
@@ -202,6 +234,9 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 			panic(fmt.Sprintf("[USER_ERROR] Infinite loop detected, current action:\n%s", nextAction))
 		}
 
+		if nextAction.stats != nil {
+			nextAction.stats.recordExecution(sim)
+		}
 		nextAction.Execute(sim)
 	}
 	apl.inLoop = false
@@ -222,7 +257,9 @@ func (apl *APLRotation) getNextAction(sim *Simulation) *APLAction {
 	}
 
 	for _, action := range apl.priorityList {
+		action.stats.evaluations++
 		if action.IsReady(sim) {
+			action.stats.ready++
 			return action
 		}
 	}
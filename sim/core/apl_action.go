@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/wowsims/sod/sim/core/proto"
 )
@@ -9,6 +10,46 @@ import (
 type APLAction struct {
 	condition APLValue
 	impl      APLActionImpl
+
+	// Only set for priority list items.
+	stats *aplActionStats
+}
+
+// Execution statistics of a priority list item, summed over all iterations.
+type aplActionStats struct {
+	configIdx int
+
+	evaluations int
+	ready       int
+	executions  int
+
+	lastExecutedAt        time.Duration // Negative if not executed yet in this iteration.
+	timeBetweenExecutions time.Duration
+	numIntervals          int
+}
+
+func (stats *aplActionStats) reset() {
+	stats.lastExecutedAt = -1
+}
+
+func (stats *aplActionStats) recordExecution(sim *Simulation) {
+	stats.executions++
+	if stats.lastExecutedAt >= 0 {
+		stats.timeBetweenExecutions += sim.CurrentTime - stats.lastExecutedAt
+		stats.numIntervals++
+	}
+	stats.lastExecutedAt = sim.CurrentTime
+}
+
+func (stats *aplActionStats) fillProto(actionStats *proto.APLActionStats, numIterations int) {
+	n := float64(numIterations)
+	actionStats.EvaluationsAvg = float64(stats.evaluations) / n
+	actionStats.ReadyAvg = float64(stats.ready) / n
+	actionStats.ExecutionsAvg = float64(stats.executions) / n
+	if stats.numIntervals > 0 {
+		actionStats.SecondsBetweenExecutionsAvg = stats.timeBetweenExecutions.Seconds() / float64(stats.numIntervals)
+	}
+	actionStats.AggregatorData.N = int32(stats.numIntervals)
 }
 
 func (action *APLAction) Finalize(rot *APLRotation) {
@@ -185,6 +226,8 @@ func (rot *APLRotation) newAPLActionImpl(config *proto.APLAction) APLActionImpl
 		return rot.newActionCustomRotation(config.GetCustomRotation())
 	case *proto.APLAction_AddComboPoints:
 		return rot.newActionAddComboPoints(config.GetAddComboPoints())
+	case *proto.APLAction_SetVariable:
+		return rot.newActionSetVariable(config.GetSetVariable())
 	default:
 		return nil
 	}
@@ -0,0 +1,423 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// Statically checks each player's APL for items which parse fine but can't work as intended.
+func LintAPL(request *proto.LintAPLRequest) *proto.LintAPLResult {
+	encounter := request.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+
+	env, _, _ := NewEnvironment(request.Raid, encounter, false)
+
+	result := &proto.LintAPLResult{}
+	for partyIdx, party := range env.Raid.Parties {
+		partyProto := request.Raid.Parties[partyIdx]
+		for playerIdx, player := range party.Players {
+			if playerIdx >= len(partyProto.Players) {
+				// This happens for target dummies.
+				continue
+			}
+			character := player.GetCharacter()
+			if character.Rotation == nil {
+				continue
+			}
+			result.Players = append(result.Players, &proto.PlayerAPLLint{
+				Name:     character.Name,
+				Warnings: character.Rotation.lint(partyProto.Players[playerIdx].Rotation),
+			})
+		}
+	}
+	return result
+}
+
+type aplLinter struct {
+	rot      *APLRotation
+	warnings []string
+}
+
+// Warnings are only added once per item, as a named expression can be referenced several times.
+func (linter *aplLinter) warn(message string, vals ...interface{}) {
+	warning := fmt.Sprintf(message, vals...)
+	if !slices.Contains(linter.warnings, warning) {
+		linter.warnings = append(linter.warnings, warning)
+	}
+}
+
+// Takes the warnings for the item linted since the last call.
+func (linter *aplLinter) takeWarnings() []string {
+	warnings := linter.warnings
+	linter.warnings = nil
+	return warnings
+}
+
+// Looks up a spell like the parser does, without its warnings.
+func (linter *aplLinter) spell(spellId *proto.ActionID) *Spell {
+	var spell *Spell
+	linter.rot.doAndRecordWarnings(nil, false, func() {
+		spell = linter.rot.GetAPLSpell(spellId)
+	})
+	return spell
+}
+
+// Returns the warnings for each item of config, which must be the config this rotation was parsed from.
+func (rot *APLRotation) lint(config *proto.APLRotation) *proto.APLStats {
+	linter := &aplLinter{rot: rot}
+	stats := &proto.APLStats{}
+
+	for _, prepullItem := range config.PrepullActions {
+		if !prepullItem.Hide {
+			linter.lintAction(prepullItem.Action, "this action is never used")
+		}
+		stats.PrepullActions = append(stats.PrepullActions, &proto.APLActionStats{Warnings: linter.takeWarnings()})
+	}
+
+	// Casts of earlier items which are used whenever the spell can be cast.
+	type unconditionalCast struct {
+		configIdx int
+		castSpell *proto.APLActionCastSpell
+	}
+	var alwaysCast []unconditionalCast
+	for i, aplItem := range config.PriorityList {
+		if !aplItem.Hide && aplItem.Action != nil {
+			linter.lintAction(aplItem.Action, "this action is never used")
+
+			if castSpell := aplItem.Action.GetCastSpell(); castSpell != nil {
+				for _, earlier := range alwaysCast {
+					if isSameCast(earlier.castSpell, castSpell) {
+						linter.warn("Never used, as item #%d always casts %s first", earlier.configIdx+1, ProtoToActionID(castSpell.SpellId))
+						break
+					}
+				}
+				if isTrue, isConst := linter.constBool(aplItem.Action.Condition); aplItem.Action.Condition == nil || (isConst && isTrue) {
+					alwaysCast = append(alwaysCast, unconditionalCast{configIdx: i, castSpell: castSpell})
+				}
+			}
+		}
+		stats.PriorityList = append(stats.PriorityList, &proto.APLActionStats{Warnings: linter.takeWarnings()})
+	}
+
+	return stats
+}
+
+func isSameCast(a *proto.APLActionCastSpell, b *proto.APLActionCastSpell) bool {
+	targetOrDefault := func(target *proto.UnitReference) *proto.UnitReference {
+		if target == nil || target.Type == proto.UnitReference_Unknown {
+			return &proto.UnitReference{Type: proto.UnitReference_CurrentTarget}
+		}
+		return target
+	}
+	return googleProto.Equal(a.SpellId, b.SpellId) && googleProto.Equal(targetOrDefault(a.Target), targetOrDefault(b.Target))
+}
+
+// Lints an action and all of its inner actions. neverUsed describes what happens if the condition
+// can never be true.
+func (linter *aplLinter) lintAction(config *proto.APLAction, neverUsed string) {
+	if config == nil {
+		return
+	}
+
+	if isTrue, isConst := linter.constBool(config.Condition); isConst {
+		if isTrue {
+			linter.warn("Condition is always true and can be removed")
+		} else {
+			linter.warn("Condition is always false, so %s", neverUsed)
+		}
+	}
+	linter.lintValue(config.Condition)
+
+	switch action := config.Action.(type) {
+	case *proto.APLAction_CastSpell:
+		linter.lintActionSpell(action.CastSpell.SpellId)
+	case *proto.APLAction_ChannelSpell:
+		linter.lintActionSpell(action.ChannelSpell.SpellId)
+		linter.lintValue(action.ChannelSpell.InterruptIf)
+	case *proto.APLAction_Multidot:
+		linter.lintActionSpell(action.Multidot.SpellId)
+		linter.lintValue(action.Multidot.MaxOverlap)
+	case *proto.APLAction_Multishield:
+		linter.lintActionSpell(action.Multishield.SpellId)
+		linter.lintValue(action.Multishield.MaxOverlap)
+	case *proto.APLAction_Wait:
+		linter.lintValue(action.Wait.Duration)
+	case *proto.APLAction_WaitUntil:
+		linter.lintValue(action.WaitUntil.Condition)
+	case *proto.APLAction_SetVariable:
+		linter.lintValue(action.SetVariable.Value)
+	case *proto.APLAction_Schedule:
+		linter.lintAction(action.Schedule.InnerAction, neverUsed)
+	case *proto.APLAction_Sequence:
+		for _, subaction := range action.Sequence.Actions {
+			linter.lintAction(subaction, "the sequence gets stuck on this action")
+		}
+	case *proto.APLAction_StrictSequence:
+		for _, subaction := range action.StrictSequence.Actions {
+			linter.lintAction(subaction, "the strict sequence can never complete")
+		}
+		linter.lintStrictSequenceCooldowns(action.StrictSequence)
+	case *proto.APLAction_CancelAura:
+		linter.lintActionAura(action.CancelAura.AuraId, false)
+	case *proto.APLAction_ActivateAura:
+		linter.lintActionAura(action.ActivateAura.AuraId, false)
+	case *proto.APLAction_ActivateAuraWithStacks:
+		linter.lintActionAura(action.ActivateAuraWithStacks.AuraId, false)
+	case *proto.APLAction_TriggerIcd:
+		linter.lintActionAura(action.TriggerIcd.AuraId, true)
+	}
+}
+
+func (linter *aplLinter) lintActionSpell(spellId *proto.ActionID) {
+	if linter.spell(spellId) == nil {
+		linter.warn("%s isn't known with the current talents and runes, so this action is never used", ProtoToActionID(spellId))
+	}
+}
+
+func (linter *aplLinter) lintActionAura(auraId *proto.ActionID, isICD bool) {
+	if !linter.isAuraRegistered(&proto.UnitReference{Type: proto.UnitReference_Self}, auraId, isICD) {
+		linter.warn("Aura %s is never registered on %s, so this action does nothing", ProtoToActionID(auraId), linter.rot.unit.Label)
+	}
+}
+
+func (linter *aplLinter) isAuraRegistered(sourceUnit *proto.UnitReference, auraId *proto.ActionID, isICD bool) bool {
+	var unit UnitReference
+	linter.rot.doAndRecordWarnings(nil, false, func() {
+		unit = linter.rot.GetSourceUnit(sourceUnit)
+	})
+	if unit.Get() == nil {
+		// Already a parsing warning.
+		return true
+	}
+	if isICD {
+		aura := NewIcdAuraReference(unit, auraId)
+		return aura.Get() != nil
+	}
+	aura := NewAuraReference(unit, auraId)
+	return aura.Get() != nil
+}
+
+// The parser drops values which reference unknown spells or auras, which silently changes the meaning
+// of the conditions containing them.
+func (linter *aplLinter) lintValue(config *proto.APLValue) {
+	if config == nil {
+		return
+	}
+
+	if spellId := aplValueSpellID(config); spellId != nil && linter.spell(spellId) == nil {
+		linter.warn("Condition checks %s, which isn't known with the current talents and runes, so that part of the condition is dropped", ProtoToActionID(spellId))
+	}
+	if sourceUnit, auraId, isICD := aplValueAura(config); auraId != nil && !linter.isAuraRegistered(sourceUnit, auraId, isICD) {
+		linter.warn("Condition checks aura %s, which is never registered, so that part of the condition is dropped", ProtoToActionID(auraId))
+	}
+
+	for _, inner := range linter.innerValues(config) {
+		linter.lintValue(inner)
+	}
+}
+
+// A strict sequence only starts once every spell in it is ready, and resets whenever its next action
+// isn't ready when the GCD is, so it can't complete if one of its casts puts a later one on cooldown.
+func (linter *aplLinter) lintStrictSequenceCooldowns(config *proto.APLActionStrictSequence) {
+	var spells []*Spell
+	for _, subaction := range config.Actions {
+		var spellId *proto.ActionID
+		if castSpell := subaction.GetCastSpell(); castSpell != nil {
+			spellId = castSpell.SpellId
+		} else if channelSpell := subaction.GetChannelSpell(); channelSpell != nil {
+			spellId = channelSpell.SpellId
+		}
+		spell := linter.spell(spellId)
+		if spell == nil {
+			continue
+		}
+
+		for _, earlier := range spells {
+			if sharesCooldown(earlier, spell) {
+				linter.warn("Strict sequence can never complete, as casting %s puts %s on cooldown", earlier.ActionID, spell.ActionID)
+				break
+			}
+		}
+		spells = append(spells, spell)
+	}
+}
+
+// Whether casting first puts second on a cooldown which outlasts the GCD.
+func sharesCooldown(first *Spell, second *Spell) bool {
+	for _, cd := range []Cooldown{first.CdSpell.CD, first.CdSpell.SharedCD} {
+		if cd.Timer != nil && cd.Duration > GCDDefault && (cd.Timer == second.CdSpell.CD.Timer || cd.Timer == second.CdSpell.SharedCD.Timer) {
+			return true
+		}
+	}
+	return false
+}
+
+// Folds conditions which don't depend on the sim state, i.e. constants, comparisons and math between
+// constants, and whether spells or auras are known. Returns false for isConst otherwise.
+func (linter *aplLinter) constBool(config *proto.APLValue) (value bool, isConst bool) {
+	if config == nil {
+		return false, false
+	}
+
+	switch v := config.Value.(type) {
+	case *proto.APLValue_And:
+		// A single false value is enough, even if the others aren't constant.
+		allConst := true
+		for _, val := range v.And.Vals {
+			valTrue, valConst := linter.constBool(val)
+			if valConst && !valTrue {
+				return false, true
+			}
+			allConst = allConst && valConst
+		}
+		return true, allConst
+	case *proto.APLValue_Or:
+		allConst := true
+		for _, val := range v.Or.Vals {
+			valTrue, valConst := linter.constBool(val)
+			if valConst && valTrue {
+				return true, true
+			}
+			allConst = allConst && valConst
+		}
+		return false, allConst
+	case *proto.APLValue_Not:
+		valTrue, valConst := linter.constBool(v.Not.Val)
+		return !valTrue, valConst
+	}
+
+	if definition := linter.namedExpression(config); definition != nil {
+		return linter.constBool(definition)
+	}
+	if !linter.isStatic(config) {
+		return false, false
+	}
+
+	var parsed APLValue
+	linter.rot.doAndRecordWarnings(nil, false, func() {
+		parsed = linter.rot.coerceTo(linter.rot.newAPLValue(config), proto.APLValueType_ValueTypeBool)
+	})
+	if parsed == nil {
+		return false, false
+	}
+	return parsed.GetBool(nil), true
+}
+
+// Whether the value can be evaluated without a sim. Variables never are, as Set Variable actions
+// change them during the sim.
+func (linter *aplLinter) isStatic(config *proto.APLValue) bool {
+	switch config.Value.(type) {
+	case *proto.APLValue_Const, *proto.APLValue_SpellIsKnown, *proto.APLValue_AuraIsKnown:
+		return true
+	case *proto.APLValue_And, *proto.APLValue_Or, *proto.APLValue_Not, *proto.APLValue_Cmp, *proto.APLValue_Math, *proto.APLValue_Max, *proto.APLValue_Min, *proto.APLValue_NamedExpression:
+		inner := linter.innerValues(config)
+		for _, val := range inner {
+			if val == nil || !linter.isStatic(val) {
+				return false
+			}
+		}
+		return len(inner) > 0
+	}
+	return false
+}
+
+// Returns the definition of a named expression value, or nil if it's not a valid reference. Cyclic
+// expressions are dropped by the parser, so following definitions always ends.
+func (linter *aplLinter) namedExpression(config *proto.APLValue) *proto.APLValue {
+	ref := config.GetNamedExpression()
+	if ref == nil {
+		return nil
+	}
+	expr := linter.rot.namedExpressions[ref.Name]
+	if expr == nil || expr.cyclic {
+		return nil
+	}
+	return expr.config
+}
+
+func (linter *aplLinter) innerValues(config *proto.APLValue) []*proto.APLValue {
+	if definition := linter.namedExpression(config); definition != nil {
+		return []*proto.APLValue{definition}
+	}
+	return aplValueInnerValues(config)
+}
+
+func aplValueInnerValues(config *proto.APLValue) []*proto.APLValue {
+	switch v := config.Value.(type) {
+	case *proto.APLValue_And:
+		return v.And.Vals
+	case *proto.APLValue_Or:
+		return v.Or.Vals
+	case *proto.APLValue_Not:
+		return []*proto.APLValue{v.Not.Val}
+	case *proto.APLValue_Cmp:
+		return []*proto.APLValue{v.Cmp.Lhs, v.Cmp.Rhs}
+	case *proto.APLValue_Math:
+		return []*proto.APLValue{v.Math.Lhs, v.Math.Rhs}
+	case *proto.APLValue_Max:
+		return v.Max.Vals
+	case *proto.APLValue_Min:
+		return v.Min.Vals
+	case *proto.APLValue_AuraShouldRefresh:
+		return []*proto.APLValue{v.AuraShouldRefresh.MaxOverlap}
+	}
+	return nil
+}
+
+// Returns the spell referenced by spell values, other than Spell Is Known which is meant for unknown spells.
+func aplValueSpellID(config *proto.APLValue) *proto.ActionID {
+	switch v := config.Value.(type) {
+	case *proto.APLValue_SpellCanCast:
+		return v.SpellCanCast.SpellId
+	case *proto.APLValue_SpellIsReady:
+		return v.SpellIsReady.SpellId
+	case *proto.APLValue_SpellTimeToReady:
+		return v.SpellTimeToReady.SpellId
+	case *proto.APLValue_SpellCastTime:
+		return v.SpellCastTime.SpellId
+	case *proto.APLValue_SpellTravelTime:
+		return v.SpellTravelTime.SpellId
+	case *proto.APLValue_SpellInFlight:
+		return v.SpellInFlight.SpellId
+	case *proto.APLValue_SpellCpm:
+		return v.SpellCpm.SpellId
+	case *proto.APLValue_SpellIsChanneling:
+		return v.SpellIsChanneling.SpellId
+	case *proto.APLValue_SpellChanneledTicks:
+		return v.SpellChanneledTicks.SpellId
+	case *proto.APLValue_SpellCurrentCost:
+		return v.SpellCurrentCost.SpellId
+	case *proto.APLValue_DotIsActive:
+		return v.DotIsActive.SpellId
+	case *proto.APLValue_DotRemainingTime:
+		return v.DotRemainingTime.SpellId
+	}
+	return nil
+}
+
+// Returns the aura referenced by aura values, other than Aura Is Known which is meant for unknown auras.
+func aplValueAura(config *proto.APLValue) (sourceUnit *proto.UnitReference, auraId *proto.ActionID, isICD bool) {
+	switch v := config.Value.(type) {
+	case *proto.APLValue_AuraIsActive:
+		return v.AuraIsActive.SourceUnit, v.AuraIsActive.AuraId, false
+	case *proto.APLValue_AuraIsActiveWithReactionTime:
+		return v.AuraIsActiveWithReactionTime.SourceUnit, v.AuraIsActiveWithReactionTime.AuraId, false
+	case *proto.APLValue_AuraRemainingTime:
+		return v.AuraRemainingTime.SourceUnit, v.AuraRemainingTime.AuraId, false
+	case *proto.APLValue_AuraNumStacks:
+		return v.AuraNumStacks.SourceUnit, v.AuraNumStacks.AuraId, false
+	case *proto.APLValue_AuraShouldRefresh:
+		return v.AuraShouldRefresh.SourceUnit, v.AuraShouldRefresh.AuraId, false
+	case *proto.APLValue_AuraInternalCooldown:
+		return v.AuraInternalCooldown.SourceUnit, v.AuraInternalCooldown.AuraId, true
+	case *proto.APLValue_AuraIcdIsReadyWithReactionTime:
+		return v.AuraIcdIsReadyWithReactionTime.SourceUnit, v.AuraIcdIsReadyWithReactionTime.AuraId, true
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestLintAPL(t *testing.T) {
+	constValue := func(val string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+	}
+	moveSpell := &proto.ActionID{RawId: &proto.ActionID_OtherId{OtherId: proto.OtherAction_OtherActionMove}}
+	castMove := func(condition *proto.APLValue) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{
+			Condition: condition,
+			Action:    &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: moveSpell}},
+		}}
+	}
+	unknownSpell := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 12345}}
+
+	rotation := &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		PriorityList: []*proto.APLListItem{
+			// A condition which is always false.
+			castMove(&proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+				Op:  proto.APLValueCompare_OpGt,
+				Lhs: constValue("1"),
+				Rhs: constValue("2"),
+			}}}),
+			// An unknown spell.
+			{Action: &proto.APLAction{Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: unknownSpell}}}},
+			// An aura which is never registered.
+			castMove(&proto.APLValue{Value: &proto.APLValue_AuraIsActive{AuraIsActive: &proto.APLValueAuraIsActive{AuraId: unknownSpell}}}),
+			// Spell Is Known is constant.
+			castMove(&proto.APLValue{Value: &proto.APLValue_SpellIsKnown{SpellIsKnown: &proto.APLValueSpellIsKnown{SpellId: unknownSpell}}}),
+			// Always true, even though only one of the values is constant.
+			castMove(&proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: []*proto.APLValue{
+				constValue("true"),
+				{Value: &proto.APLValue_SpellIsReady{SpellIsReady: &proto.APLValueSpellIsReady{SpellId: moveSpell}}},
+			}}}}),
+			// Unreachable because of the previous item.
+			castMove(nil),
+		},
+	}
+
+	priorityList := lintTestRotation(t, rotation)
+
+	expected := []string{
+		"Condition is always false",
+		"isn't known with the current talents and runes",
+		"which is never registered",
+		"Condition is always false",
+		"Condition is always true",
+		"Never used, as item #5 always casts",
+	}
+	for i, substr := range expected {
+		warnings := priorityList[i].Warnings
+		if len(warnings) != 1 || !strings.Contains(warnings[0], substr) {
+			t.Errorf("Expected item %d to have a single warning containing %q, got %v", i+1, substr, warnings)
+		}
+	}
+}
+
+func lintTestRotation(t *testing.T, rotation *proto.APLRotation) []*proto.APLActionStats {
+	result := LintAPL(&proto.LintAPLRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation:  rotation,
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{{Name: "target", Level: 63}},
+		},
+	})
+
+	if len(result.Players) != 1 {
+		t.Fatalf("Expected 1 player, got %d", len(result.Players))
+	}
+	priorityList := result.Players[0].Warnings.PriorityList
+	if len(priorityList) != len(rotation.PriorityList) {
+		t.Fatalf("Expected warnings for %d items, got %d", len(rotation.PriorityList), len(priorityList))
+	}
+	return priorityList
+}
+
+func TestLintAPLVariablesAndNamedExpressions(t *testing.T) {
+	constValue := func(val string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+	}
+	expressionValue := func(name string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_NamedExpression{NamedExpression: &proto.APLValueNamedExpression{Name: name}}}
+	}
+	moveSpell := &proto.ActionID{RawId: &proto.ActionID_OtherId{OtherId: proto.OtherAction_OtherActionMove}}
+	castMove := func(condition *proto.APLValue) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{
+			Condition: condition,
+			Action:    &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: moveSpell}},
+		}}
+	}
+	unknownSpell := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 12345}}
+	unknownAuraIsActive := &proto.APLValue{Value: &proto.APLValue_AuraIsActive{AuraIsActive: &proto.APLValueAuraIsActive{AuraId: unknownSpell}}}
+
+	rotation := &proto.APLRotation{
+		Type:      proto.APLRotation_TypeAPL,
+		Variables: []*proto.APLVariable{{Name: "flag", IsBool: true}},
+		NamedExpressions: []*proto.APLNamedExpression{
+			{Name: "never", Value: &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+				Op:  proto.APLValueCompare_OpGt,
+				Lhs: constValue("1"),
+				Rhs: constValue("2"),
+			}}}},
+			{Name: "unknownAura", Value: unknownAuraIsActive},
+			{Name: "cycleA", Value: expressionValue("cycleB")},
+			{Name: "cycleB", Value: expressionValue("cycleA")},
+		},
+		PriorityList: []*proto.APLListItem{
+			// Always false through the named expression.
+			castMove(expressionValue("never")),
+			// The unknown aura is only reported once, even though the expression is used twice.
+			castMove(&proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: []*proto.APLValue{
+				expressionValue("unknownAura"),
+				expressionValue("unknownAura"),
+			}}}}),
+			// The value of a Set Variable action.
+			{Action: &proto.APLAction{Action: &proto.APLAction_SetVariable{SetVariable: &proto.APLActionSetVariable{
+				Name:  "flag",
+				Value: &proto.APLValue{Value: &proto.APLValue_SpellIsReady{SpellIsReady: &proto.APLValueSpellIsReady{SpellId: unknownSpell}}},
+			}}}},
+			// Variables can change during the sim, so they're never constant.
+			castMove(&proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: "flag"}}}),
+			// Cyclic expressions are already reported by the parser, and mustn't be followed forever.
+			castMove(expressionValue("cycleA")),
+		},
+	}
+
+	priorityList := lintTestRotation(t, rotation)
+	expected := []string{
+		"Condition is always false",
+		"which is never registered",
+		"isn't known with the current talents and runes",
+		"",
+		"",
+	}
+	for i, substr := range expected {
+		warnings := priorityList[i].Warnings
+		if substr == "" && len(warnings) != 0 {
+			t.Errorf("Expected no warnings for item %d, got %v", i+1, warnings)
+		}
+		if substr != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], substr)) {
+			t.Errorf("Expected item %d to have a single warning containing %q, got %v", i+1, substr, warnings)
+		}
+	}
+}
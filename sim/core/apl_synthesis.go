@@ -0,0 +1,393 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// A value from the observations which APL conditions can be built from.
+type aplSynthesisFeature struct {
+	values []float64 // Indexed by decision.
+	order  []int     // Decision indices sorted by value.
+	isBool bool
+
+	aplValue        func() *proto.APLValue
+	formatThreshold func(float64) string
+}
+
+type aplSynthesisCondition struct {
+	feature   *aplSynthesisFeature
+	threshold float64
+	greater   bool // Value must be > threshold if true, <= threshold otherwise.
+}
+
+func (cond aplSynthesisCondition) matches(decision int) bool {
+	return (cond.feature.values[decision] > cond.threshold) == cond.greater
+}
+
+func (cond aplSynthesisCondition) toAPLValue() *proto.APLValue {
+	if cond.feature.isBool {
+		if cond.greater {
+			return cond.feature.aplValue()
+		}
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: cond.feature.aplValue()}}}
+	}
+
+	op := proto.APLValueCompare_OpLe
+	if cond.greater {
+		op = proto.APLValueCompare_OpGt
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+		Op:  op,
+		Lhs: cond.feature.aplValue(),
+		Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: cond.feature.formatThreshold(cond.threshold)}}},
+	}}}
+}
+
+type aplSynthesisItem struct {
+	action     int32
+	conditions []aplSynthesisCondition
+}
+
+func (item *aplSynthesisItem) matches(decisions []*proto.APLTraceDecision, decision int) bool {
+	if !decisions[decision].Observation.Spells[item.action-1].CanCast {
+		return false
+	}
+	for _, cond := range item.conditions {
+		if !cond.matches(decision) {
+			return false
+		}
+	}
+	return true
+}
+
+// SynthesizeAPL builds a priority list from a decision trace with a greedy decision list search.
+// Each step picks the spell and conditions which reproduce the most remaining decisions, net of
+// the ones they would get wrong, and removes the decisions it covers. Decisions not covered by any
+// item are waits, which is what an APL does when none of its items can be used.
+func SynthesizeAPL(request *proto.APLSynthesisRequest) *proto.APLSynthesisResult {
+	if err := validateAPLSynthesisRequest(request); err != nil {
+		return &proto.APLSynthesisResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	maxItems := int(request.MaxItems)
+	if maxItems <= 0 {
+		maxItems = 12
+	}
+	maxConditions := int(request.MaxConditions)
+	if maxConditions <= 0 {
+		maxConditions = 2
+	}
+
+	decisions := request.Decisions
+	features := newAPLSynthesisFeatures(request.Spec, decisions)
+
+	remaining := make([]bool, len(decisions))
+	for i := range remaining {
+		remaining[i] = true
+	}
+
+	var items []*aplSynthesisItem
+	for len(items) < maxItems {
+		var bestItem *aplSynthesisItem
+		bestGain := 0
+		for action := int32(1); action <= int32(len(request.Spec.Actions)); action++ {
+			item, gain := findAPLSynthesisItem(decisions, features, remaining, action, maxConditions)
+			if gain > bestGain {
+				bestItem, bestGain = item, gain
+			}
+		}
+		if bestItem == nil {
+			break
+		}
+
+		items = append(items, bestItem)
+		for i := range decisions {
+			if remaining[i] && bestItem.matches(decisions, i) {
+				remaining[i] = false
+			}
+		}
+	}
+
+	return evaluateAPLSynthesis(request.Spec, decisions, items)
+}
+
+func validateAPLSynthesisRequest(request *proto.APLSynthesisRequest) error {
+	if request.Spec == nil {
+		return fmt.Errorf("APL synthesis needs the env spec")
+	}
+	if len(request.Decisions) == 0 {
+		return fmt.Errorf("APL synthesis needs at least one decision")
+	}
+	spec := request.Spec
+	for i, decision := range request.Decisions {
+		obs := decision.Observation
+		if obs == nil || obs.Resources == nil {
+			return fmt.Errorf("decision %d has no observation", i)
+		}
+		if len(obs.Spells) != len(spec.Actions) || len(obs.PlayerAuras) != len(spec.PlayerAuras) || len(obs.TargetAuras) != len(spec.TargetAuras) {
+			return fmt.Errorf("decision %d doesn't match the env spec", i)
+		}
+		if decision.Action < 0 || int(decision.Action) > len(spec.Actions) {
+			return fmt.Errorf("decision %d has invalid action %d", i, decision.Action)
+		}
+	}
+	return nil
+}
+
+// Finds the conditions which give the best gain for casting the action, adding one at a time.
+func findAPLSynthesisItem(decisions []*proto.APLTraceDecision, features []*aplSynthesisFeature, remaining []bool, action int32, maxConditions int) (*aplSynthesisItem, int) {
+	item := &aplSynthesisItem{action: action}
+
+	covered := make([]bool, len(decisions))
+	gain := 0
+	for i := range decisions {
+		if remaining[i] && item.matches(decisions, i) {
+			covered[i] = true
+			gain += aplSynthesisGain(decisions[i], action)
+		}
+	}
+
+	for len(item.conditions) < maxConditions {
+		var bestCondition aplSynthesisCondition
+		bestGain := gain
+		for _, feature := range features {
+			if condition, conditionGain := findAPLSynthesisCondition(decisions, feature, covered, action); conditionGain > bestGain {
+				bestCondition, bestGain = condition, conditionGain
+			}
+		}
+		if bestCondition.feature == nil {
+			break
+		}
+
+		item.conditions = append(item.conditions, bestCondition)
+		gain = bestGain
+		for i := range covered {
+			covered[i] = covered[i] && bestCondition.matches(i)
+		}
+	}
+
+	// Conditions picked early were chosen without knowing about the later ones, so refit their
+	// thresholds given the other conditions.
+	for j := range item.conditions {
+		others := slices.Delete(slices.Clone(item.conditions), j, j+1)
+		for i := range covered {
+			covered[i] = remaining[i] && decisions[i].Observation.Spells[action-1].CanCast &&
+				!slices.ContainsFunc(others, func(cond aplSynthesisCondition) bool { return !cond.matches(i) })
+		}
+		if condition, conditionGain := findAPLSynthesisCondition(decisions, item.conditions[j].feature, covered, action); conditionGain >= gain {
+			item.conditions[j], gain = condition, conditionGain
+		}
+	}
+
+	return item, gain
+}
+
+// Sweeps the covered decisions in order of the feature's value to find the threshold with the best gain.
+func findAPLSynthesisCondition(decisions []*proto.APLTraceDecision, feature *aplSynthesisFeature, covered []bool, action int32) (aplSynthesisCondition, int) {
+	total := 0
+	for i, isCovered := range covered {
+		if isCovered {
+			total += aplSynthesisGain(decisions[i], action)
+		}
+	}
+
+	var best aplSynthesisCondition
+	bestGain := 0
+	below := 0 // Gain of the covered decisions with values <= the current one.
+	for n, i := range feature.order {
+		if covered[i] {
+			below += aplSynthesisGain(decisions[i], action)
+		}
+
+		value := feature.values[i]
+		if n+1 < len(feature.order) && feature.values[feature.order[n+1]] == value {
+			continue
+		}
+		if below > bestGain {
+			best, bestGain = aplSynthesisCondition{feature: feature, threshold: value, greater: false}, below
+		}
+		if total-below > bestGain {
+			best, bestGain = aplSynthesisCondition{feature: feature, threshold: value, greater: true}, total-below
+		}
+	}
+	return best, bestGain
+}
+
+// Covering a decision gains 1 if the recorded action is the same, and loses 1 otherwise.
+func aplSynthesisGain(decision *proto.APLTraceDecision, action int32) int {
+	if decision.Action == action {
+		return 1
+	}
+	return -1
+}
+
+func evaluateAPLSynthesis(spec *proto.EnvSpec, decisions []*proto.APLTraceDecision, items []*aplSynthesisItem) *proto.APLSynthesisResult {
+	result := &proto.APLSynthesisResult{
+		Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+		ItemStats: make([]*proto.APLSynthesisItemStats, len(items)),
+	}
+	for i := range items {
+		result.ItemStats[i] = &proto.APLSynthesisItemStats{}
+	}
+
+	matches := 0
+	for i, decision := range decisions {
+		chosen := int32(0)
+		for j, item := range items {
+			if item.matches(decisions, i) {
+				chosen = item.action
+				result.ItemStats[j].Decisions++
+				if chosen == decision.Action {
+					result.ItemStats[j].Matches++
+				}
+				break
+			}
+		}
+		if chosen == decision.Action {
+			matches++
+		}
+	}
+	result.MatchRate = float64(matches) / float64(len(decisions))
+
+	for i, item := range items {
+		var condition *proto.APLValue
+		if len(item.conditions) == 1 {
+			condition = item.conditions[0].toAPLValue()
+		} else if len(item.conditions) > 1 {
+			and := &proto.APLValueAnd{}
+			for _, cond := range item.conditions {
+				and.Vals = append(and.Vals, cond.toAPLValue())
+			}
+			condition = &proto.APLValue{Value: &proto.APLValue_And{And: and}}
+		}
+
+		stats := result.ItemStats[i]
+		result.Rotation.PriorityList = append(result.Rotation.PriorityList, &proto.APLListItem{
+			Notes: fmt.Sprintf("Matches %d of %d decisions", stats.Matches, stats.Decisions),
+			Action: &proto.APLAction{
+				Condition: condition,
+				Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+					SpellId: spec.Actions[item.action-1],
+				}},
+			},
+		})
+	}
+	return result
+}
+
+func newAPLSynthesisFeatures(spec *proto.EnvSpec, decisions []*proto.APLTraceDecision) []*aplSynthesisFeature {
+	var features []*aplSynthesisFeature
+	add := func(feature *aplSynthesisFeature, getValue func(obs *proto.EnvObservation) float64) {
+		feature.values = make([]float64, len(decisions))
+		for i, decision := range decisions {
+			feature.values[i] = getValue(decision.Observation)
+		}
+		// Constant features can't distinguish between decisions.
+		if slices.Min(feature.values) == slices.Max(feature.values) {
+			return
+		}
+
+		feature.order = make([]int, len(decisions))
+		for i := range feature.order {
+			feature.order[i] = i
+		}
+		slices.SortStableFunc(feature.order, func(a, b int) int {
+			return cmp.Compare(feature.values[a], feature.values[b])
+		})
+		if feature.formatThreshold == nil {
+			feature.formatThreshold = formatAPLSynthesisNumber
+		}
+		features = append(features, feature)
+	}
+	constValue := func(value *proto.APLValue) func() *proto.APLValue {
+		return func() *proto.APLValue { return value }
+	}
+	self := &proto.UnitReference{Type: proto.UnitReference_Self}
+	target := &proto.UnitReference{Type: proto.UnitReference_CurrentTarget}
+
+	add(&aplSynthesisFeature{
+		aplValue:        constValue(&proto.APLValue{Value: &proto.APLValue_RemainingTime{RemainingTime: &proto.APLValueRemainingTime{}}}),
+		formatThreshold: formatAPLSynthesisDuration,
+	}, func(obs *proto.EnvObservation) float64 { return obs.RemainingDuration })
+	add(&aplSynthesisFeature{
+		aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_CurrentManaPercent{CurrentManaPercent: &proto.APLValueCurrentManaPercent{SourceUnit: self}}}),
+	}, func(obs *proto.EnvObservation) float64 { return obs.Resources.ManaPercent })
+	add(&aplSynthesisFeature{
+		aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_CurrentRage{CurrentRage: &proto.APLValueCurrentRage{}}}),
+	}, func(obs *proto.EnvObservation) float64 { return obs.Resources.Rage })
+	add(&aplSynthesisFeature{
+		aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_CurrentEnergy{CurrentEnergy: &proto.APLValueCurrentEnergy{}}}),
+	}, func(obs *proto.EnvObservation) float64 { return obs.Resources.Energy })
+	add(&aplSynthesisFeature{
+		aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_CurrentComboPoints{CurrentComboPoints: &proto.APLValueCurrentComboPoints{}}}),
+	}, func(obs *proto.EnvObservation) float64 { return float64(obs.Resources.ComboPoints) })
+
+	for i, spellID := range spec.Actions {
+		i, spellID := i, spellID
+		add(&aplSynthesisFeature{
+			isBool:   true,
+			aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_SpellCanCast{SpellCanCast: &proto.APLValueSpellCanCast{SpellId: spellID}}}),
+		}, func(obs *proto.EnvObservation) float64 { return boolToAPLSynthesisValue(obs.Spells[i].CanCast) })
+		add(&aplSynthesisFeature{
+			aplValue:        constValue(&proto.APLValue{Value: &proto.APLValue_SpellTimeToReady{SpellTimeToReady: &proto.APLValueSpellTimeToReady{SpellId: spellID}}}),
+			formatThreshold: formatAPLSynthesisDuration,
+		}, func(obs *proto.EnvObservation) float64 { return obs.Spells[i].CooldownRemaining })
+	}
+
+	addAuraFeatures := func(unit *proto.UnitReference, auraIDs []*proto.ActionID, getAuras func(obs *proto.EnvObservation) []*proto.EnvAuraState) {
+		counts := map[ActionID]int{}
+		for _, auraID := range auraIDs {
+			counts[ProtoToActionID(auraID)]++
+		}
+
+		for i, auraID := range auraIDs {
+			i, auraID := i, auraID
+			// APLs find auras by ID, so auras without a unique one can't be referenced.
+			if actionID := ProtoToActionID(auraID); actionID.IsEmptyAction() || counts[actionID] > 1 {
+				continue
+			}
+
+			add(&aplSynthesisFeature{
+				isBool:   true,
+				aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_AuraIsActive{AuraIsActive: &proto.APLValueAuraIsActive{SourceUnit: unit, AuraId: auraID}}}),
+			}, func(obs *proto.EnvObservation) float64 { return boolToAPLSynthesisValue(getAuras(obs)[i].Active) })
+			add(&aplSynthesisFeature{
+				aplValue: constValue(&proto.APLValue{Value: &proto.APLValue_AuraNumStacks{AuraNumStacks: &proto.APLValueAuraNumStacks{SourceUnit: unit, AuraId: auraID}}}),
+			}, func(obs *proto.EnvObservation) float64 { return float64(getAuras(obs)[i].Stacks) })
+
+			// Observations report 0 for active auras which never expire, which doesn't match the APL value.
+			if slices.ContainsFunc(decisions, func(decision *proto.APLTraceDecision) bool {
+				aura := getAuras(decision.Observation)[i]
+				return aura.Active && aura.Remaining == 0
+			}) {
+				continue
+			}
+			add(&aplSynthesisFeature{
+				aplValue:        constValue(&proto.APLValue{Value: &proto.APLValue_AuraRemainingTime{AuraRemainingTime: &proto.APLValueAuraRemainingTime{SourceUnit: unit, AuraId: auraID}}}),
+				formatThreshold: formatAPLSynthesisDuration,
+			}, func(obs *proto.EnvObservation) float64 { return getAuras(obs)[i].Remaining })
+		}
+	}
+	addAuraFeatures(self, spec.PlayerAuras, func(obs *proto.EnvObservation) []*proto.EnvAuraState { return obs.PlayerAuras })
+	addAuraFeatures(target, spec.TargetAuras, func(obs *proto.EnvObservation) []*proto.EnvAuraState { return obs.TargetAuras })
+
+	return features
+}
+
+func boolToAPLSynthesisValue(b bool) float64 {
+	return TernaryFloat64(b, 1, 0)
+}
+
+func formatAPLSynthesisNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatAPLSynthesisDuration(seconds float64) string {
+	return DurationFromSeconds(seconds).Round(time.Millisecond).String()
+}
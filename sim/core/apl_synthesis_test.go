@@ -0,0 +1,72 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestSynthesizeAPLReproducesPolicy(t *testing.T) {
+	buffID := ActionID{SpellID: 100}.ToProto()
+	spec := &proto.EnvSpec{
+		Actions:     []*proto.ActionID{ActionID{SpellID: 1}.ToProto(), ActionID{SpellID: 2}.ToProto()},
+		PlayerAuras: []*proto.ActionID{buffID},
+	}
+
+	// Keeps the buff from spell 1 up, then spends energy on spell 2 and otherwise waits.
+	rng := rand.New(rand.NewSource(1))
+	var decisions []*proto.APLTraceDecision
+	for i := 0; i < 500; i++ {
+		buffActive := rng.Intn(3) > 0
+		energy := float64(rng.Intn(101))
+
+		action := int32(0)
+		if !buffActive {
+			action = 1
+		} else if energy >= 40 {
+			action = 2
+		}
+
+		decisions = append(decisions, &proto.APLTraceDecision{
+			Observation: &proto.EnvObservation{
+				RemainingDuration: float64(rng.Intn(300)),
+				Resources:         &proto.EnvResources{Energy: energy},
+				Spells:            []*proto.EnvSpellState{{CanCast: true}, {CanCast: true}},
+				PlayerAuras:       []*proto.EnvAuraState{{Active: buffActive, Remaining: float64(rng.Intn(20))}},
+			},
+			Action: action,
+		})
+	}
+
+	result := SynthesizeAPL(&proto.APLSynthesisRequest{Spec: spec, Decisions: decisions})
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	if result.MatchRate != 1 {
+		t.Fatalf("Expected the APL to reproduce every decision, got a match rate of %0.3f", result.MatchRate)
+	}
+
+	items := result.Rotation.PriorityList
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 priority list items, got %d", len(items))
+	}
+	for _, item := range items {
+		switch item.Action.GetCastSpell().SpellId.GetSpellId() {
+		case 1:
+			if item.Action.Condition.GetNot().GetVal().GetAuraIsActive() == nil {
+				t.Fatalf("Expected spell 1 to be cast when the buff is missing, got %v", item.Action.Condition)
+			}
+		case 2:
+			// Spell 2 covers more decisions, so its item can come first and then has to check the buff too.
+			vals := item.Action.Condition.GetAnd().GetVals()
+			if len(vals) != 2 || vals[0].GetCmp().GetLhs().GetCurrentEnergy() == nil || vals[0].GetCmp().GetRhs().GetConst().GetVal() != "39" || vals[1].GetAuraIsActive() == nil {
+				t.Fatalf("Expected spell 2 to be cast above 39 energy with the buff active, got %v", item.Action.Condition)
+			}
+		}
+	}
+
+	if result := SynthesizeAPL(&proto.APLSynthesisRequest{Spec: spec}); result.Error == nil {
+		t.Fatalf("Expected an error for an empty trace")
+	}
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestAPLExecutionStats(t *testing.T) {
+	waitAction := func(condition string) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{
+			Condition: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: condition}}},
+			Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{
+				Duration: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1s"}}},
+			}},
+		}}
+	}
+
+	result := RunRaidSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation: &proto.APLRotation{
+						Type: proto.APLRotation_TypeAPL,
+						PriorityList: []*proto.APLListItem{
+							// Hidden items still get an entry, so the stats line up with the config.
+							{Hide: true},
+							waitAction("false"),
+							waitAction("true"),
+						},
+					},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 10,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 4,
+			IsTest:     true,
+		},
+	})
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+
+	priorityList := result.RaidMetrics.Parties[0].Players[0].RotationStats.PriorityList
+	if len(priorityList) != 3 {
+		t.Fatalf("Expected stats for 3 items, got %d", len(priorityList))
+	}
+
+	if hidden := priorityList[0]; hidden.EvaluationsAvg != 0 {
+		t.Errorf("Expected the hidden item to never be evaluated, got %f", hidden.EvaluationsAvg)
+	}
+
+	blocked := priorityList[1]
+	if blocked.EvaluationsAvg == 0 || blocked.ReadyAvg != 0 || blocked.ExecutionsAvg != 0 {
+		t.Errorf("Expected the blocked item to be evaluated but never ready, got %v", blocked)
+	}
+
+	waiting := priorityList[2]
+	if waiting.EvaluationsAvg != blocked.EvaluationsAvg || waiting.ReadyAvg != waiting.ExecutionsAvg {
+		t.Errorf("Expected the wait to be ready and executed whenever it's evaluated, got %v", waiting)
+	}
+	if waiting.ExecutionsAvg < 9 || waiting.ExecutionsAvg > 11 {
+		t.Errorf("Expected about one wait per second, got %f", waiting.ExecutionsAvg)
+	}
+	if waiting.SecondsBetweenExecutionsAvg < 1 || waiting.SecondsBetweenExecutionsAvg > 1.1 {
+		t.Errorf("Expected about 1s between waits, got %f", waiting.SecondsBetweenExecutionsAvg)
+	}
+}
+
+func runAPLTestSim(t *testing.T, rotation *proto.APLRotation, iterations int32) *proto.APLStats {
+	return runAPLTestSimWith(t, RunRaidSim, rotation, iterations)
+}
+
+func runAPLTestSimWith(t *testing.T, run func(*proto.RaidSimRequest) *proto.RaidSimResult, rotation *proto.APLRotation, iterations int32) *proto.APLStats {
+	result := run(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation:  rotation,
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 10,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: iterations,
+			IsTest:     true,
+		},
+	})
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	return result.RaidMetrics.Parties[0].Players[0].RotationStats
+}
+
+func TestAPLVariables(t *testing.T) {
+	constValue := func(val string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+	}
+	variableValue := func(name string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: name}}}
+	}
+	lessThan := func(lhs *proto.APLValue, rhs *proto.APLValue) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{Op: proto.APLValueCompare_OpLt, Lhs: lhs, Rhs: rhs}}}
+	}
+	increment := func(name string, condition *proto.APLValue) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{
+			Condition: condition,
+			Action: &proto.APLAction_SetVariable{SetVariable: &proto.APLActionSetVariable{
+				Name:  name,
+				Value: &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: proto.APLValueMath_OpAdd, Lhs: variableValue(name), Rhs: constValue("1")}}},
+			}},
+		}}
+	}
+
+	stats := runAPLTestSim(t, &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		Variables: []*proto.APLVariable{
+			{Name: "count"},
+			{Name: "total", InitialValue: 2},
+		},
+		NamedExpressions: []*proto.APLNamedExpression{
+			{Name: "countBelowLimit", Value: lessThan(variableValue("count"), constValue("3"))},
+		},
+		PriorityList: []*proto.APLListItem{
+			increment("count", &proto.APLValue{Value: &proto.APLValue_NamedExpression{NamedExpression: &proto.APLValueNamedExpression{Name: "countBelowLimit"}}}),
+			increment("total", lessThan(variableValue("total"), constValue("6"))),
+			{Action: &proto.APLAction{Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{Duration: constValue("1s")}}}},
+		},
+	}, 4)
+
+	for i, item := range append(stats.PriorityList, stats.Variables...) {
+		if len(item.Warnings) > 0 {
+			t.Errorf("Unexpected warnings for item %d: %v", i, item.Warnings)
+		}
+	}
+	if count := stats.PriorityList[0].ExecutionsAvg; count != 3 {
+		t.Errorf("Expected the per-iteration variable to be set 3 times per iteration, got %f", count)
+	}
+	if total := stats.PriorityList[1].ExecutionsAvg; total != 4 {
+		t.Errorf("Expected the variable to count from its initial value of 2 to 6 each iteration, got %f", total)
+	}
+}
+
+// Variables are reset every iteration, so results don't depend on how iterations are split across threads.
+func TestAPLVariablesResetEachIteration(t *testing.T) {
+	variableValue := &proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: "total"}}}
+	rotation := &proto.APLRotation{
+		Type:      proto.APLRotation_TypeAPL,
+		Variables: []*proto.APLVariable{{Name: "total"}},
+		PriorityList: []*proto.APLListItem{
+			{Action: &proto.APLAction{
+				Condition: &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+					Op:  proto.APLValueCompare_OpLt,
+					Lhs: variableValue,
+					Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "6"}}},
+				}}},
+				Action: &proto.APLAction_SetVariable{SetVariable: &proto.APLActionSetVariable{
+					Name: "total",
+					Value: &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+						Op:  proto.APLValueMath_OpAdd,
+						Lhs: variableValue,
+						Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1"}}},
+					}}},
+				}},
+			}},
+			{Action: &proto.APLAction{Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{Duration: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1s"}}}}}}},
+		},
+	}
+
+	if total := runAPLTestSim(t, rotation, 6).PriorityList[0].ExecutionsAvg; total != 6 {
+		t.Errorf("Expected the variable to be set 6 times per iteration by a single sim, got %f", total)
+	}
+	if total := runAPLTestSimWith(t, RunRaidSimConcurrent, rotation, 6).PriorityList[0].ExecutionsAvg; total != 6 {
+		t.Errorf("Expected the variable to be set 6 times per iteration over 3 splits, got %f", total)
+	}
+}
+
+func TestAPLNamedExpressionWarnings(t *testing.T) {
+	expressionValue := func(name string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_NamedExpression{NamedExpression: &proto.APLValueNamedExpression{Name: name}}}
+	}
+	waitIf := func(condition *proto.APLValue) *proto.APLListItem {
+		return &proto.APLListItem{Action: &proto.APLAction{
+			Condition: condition,
+			Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{
+				Duration: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1s"}}},
+			}},
+		}}
+	}
+
+	stats := runAPLTestSim(t, &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+		NamedExpressions: []*proto.APLNamedExpression{
+			{Name: "a", Value: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: expressionValue("b")}}}},
+			{Name: "b", Value: expressionValue("a")},
+			{Name: "c", Value: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "true"}}}},
+		},
+		PriorityList: []*proto.APLListItem{
+			waitIf(expressionValue("a")),
+			waitIf(expressionValue("undefined")),
+			waitIf(&proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: "undefined"}}}),
+			waitIf(expressionValue("c")),
+		},
+	}, 1)
+
+	for i, expected := range []int{1, 1, 0} {
+		if actual := len(stats.NamedExpressions[i].Warnings); actual != expected {
+			t.Errorf("Expected %d warnings for named expression %d, got %v", expected, i, stats.NamedExpressions[i].Warnings)
+		}
+	}
+	for i, expected := range []int{1, 1, 1, 0} {
+		if actual := len(stats.PriorityList[i].Warnings); actual != expected {
+			t.Errorf("Expected %d warnings for item %d, got %v", expected, i, stats.PriorityList[i].Warnings)
+		}
+	}
+}
@@ -93,6 +93,14 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 		return rot.newValueIsExecutePhase(config.GetIsExecutePhase())
 	case *proto.APLValue_NumberTargets:
 		return rot.newValueNumberTargets(config.GetNumberTargets())
+	case *proto.APLValue_TimeUntilNextDowntime:
+		return rot.newValueTimeUntilNextDowntime(config.GetTimeUntilNextDowntime())
+	case *proto.APLValue_DowntimeRemaining:
+		return rot.newValueDowntimeRemaining(config.GetDowntimeRemaining())
+	case *proto.APLValue_TargetTimeToDie:
+		return rot.newValueTargetTimeToDie(config.GetTargetTimeToDie())
+	case *proto.APLValue_TargetTimeToPercent:
+		return rot.newValueTargetTimeToPercent(config.GetTargetTimeToPercent())
 
 	// Resources
 	case *proto.APLValue_CurrentHealth:
@@ -190,6 +198,18 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 	case *proto.APLValue_ChannelClipDelay:
 		return rot.newValueChannelClipDelay(config.GetChannelClipDelay())
 
+	// Threat
+	case *proto.APLValue_IsTanking:
+		return rot.newValueIsTanking(config.GetIsTanking())
+	case *proto.APLValue_ThreatPercentOfTank:
+		return rot.newValueThreatPercentOfTank(config.GetThreatPercentOfTank())
+
+	// Variables
+	case *proto.APLValue_Variable:
+		return rot.newValueVariable(config.GetVariable())
+	case *proto.APLValue_NamedExpression:
+		return rot.newValueNamedExpression(config.GetNamedExpression())
+
 	default:
 		return nil
 	}
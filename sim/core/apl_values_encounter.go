@@ -86,7 +86,7 @@ func (value *APLValueNumberTargets) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeInt
 }
 func (value *APLValueNumberTargets) GetInt(sim *Simulation) int32 {
-	return sim.GetNumTargets()
+	return sim.GetNumActiveTargets()
 }
 func (value *APLValueNumberTargets) String() string {
 	return "Num Targets"
@@ -122,3 +122,91 @@ func (value *APLValueIsExecutePhase) GetBool(sim *Simulation) bool {
 func (value *APLValueIsExecutePhase) String() string {
 	return "Is Execute Phase"
 }
+
+type APLValueTimeUntilNextDowntime struct {
+	DefaultAPLValueImpl
+}
+
+func (rot *APLRotation) newValueTimeUntilNextDowntime(config *proto.APLValueTimeUntilNextDowntime) APLValue {
+	return &APLValueTimeUntilNextDowntime{}
+}
+func (value *APLValueTimeUntilNextDowntime) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTimeUntilNextDowntime) GetDuration(sim *Simulation) time.Duration {
+	return sim.Encounter.TimeUntilNextDowntime(sim)
+}
+func (value *APLValueTimeUntilNextDowntime) String() string {
+	return "Time Until Next Downtime"
+}
+
+type APLValueDowntimeRemaining struct {
+	DefaultAPLValueImpl
+}
+
+func (rot *APLRotation) newValueDowntimeRemaining(config *proto.APLValueDowntimeRemaining) APLValue {
+	return &APLValueDowntimeRemaining{}
+}
+func (value *APLValueDowntimeRemaining) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueDowntimeRemaining) GetDuration(sim *Simulation) time.Duration {
+	return sim.Encounter.DowntimeRemaining(sim)
+}
+func (value *APLValueDowntimeRemaining) String() string {
+	return "Downtime Remaining"
+}
+
+type APLValueTargetTimeToDie struct {
+	DefaultAPLValueImpl
+	targetUnit UnitReference
+}
+
+func (rot *APLRotation) newValueTargetTimeToDie(config *proto.APLValueTargetTimeToDie) APLValue {
+	targetUnit := rot.GetTargetUnit(config.TargetUnit)
+	if targetUnit.Get() == nil {
+		return nil
+	}
+	return &APLValueTargetTimeToDie{
+		targetUnit: targetUnit,
+	}
+}
+func (value *APLValueTargetTimeToDie) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTargetTimeToDie) GetDuration(sim *Simulation) time.Duration {
+	return value.targetUnit.Get().TimeToDie(sim)
+}
+func (value *APLValueTargetTimeToDie) String() string {
+	return fmt.Sprintf("Target Time To Die(%s)", value.targetUnit.String())
+}
+
+type APLValueTargetTimeToPercent struct {
+	DefaultAPLValueImpl
+	targetUnit UnitReference
+	percent    float64
+}
+
+func (rot *APLRotation) newValueTargetTimeToPercent(config *proto.APLValueTargetTimeToPercent) APLValue {
+	targetUnit := rot.GetTargetUnit(config.TargetUnit)
+	if targetUnit.Get() == nil {
+		return nil
+	}
+	if config.Percent < 0 || config.Percent > 100 {
+		rot.ValidationWarning("Target Time To Percent must be between 0 and 100, got %0.1f", config.Percent)
+		return nil
+	}
+	return &APLValueTargetTimeToPercent{
+		targetUnit: targetUnit,
+		percent:    config.Percent,
+	}
+}
+func (value *APLValueTargetTimeToPercent) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeDuration
+}
+func (value *APLValueTargetTimeToPercent) GetDuration(sim *Simulation) time.Duration {
+	return value.targetUnit.Get().TimeToPercent(sim, value.percent)
+}
+func (value *APLValueTargetTimeToPercent) String() string {
+	return fmt.Sprintf("Target Time To %0.1f%%(%s)", value.percent, value.targetUnit.String())
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+type APLValueIsTanking struct {
+	DefaultAPLValueImpl
+	unit *Unit
+}
+
+func (rot *APLRotation) newValueIsTanking(config *proto.APLValueIsTanking) APLValue {
+	return &APLValueIsTanking{
+		unit: rot.unit,
+	}
+}
+func (value *APLValueIsTanking) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueIsTanking) GetBool(sim *Simulation) bool {
+	for _, target := range sim.Encounter.ActiveTargetUnits {
+		if target.CurrentTarget == value.unit {
+			return true
+		}
+	}
+	return false
+}
+func (value *APLValueIsTanking) String() string {
+	return "Is Tanking()"
+}
+
+type APLValueThreatPercentOfTank struct {
+	DefaultAPLValueImpl
+	unit *Unit
+}
+
+func (rot *APLRotation) newValueThreatPercentOfTank(config *proto.APLValueThreatPercentOfTank) APLValue {
+	return &APLValueThreatPercentOfTank{
+		unit: rot.unit,
+	}
+}
+func (value *APLValueThreatPercentOfTank) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeFloat
+}
+func (value *APLValueThreatPercentOfTank) GetFloat(sim *Simulation) float64 {
+	target := value.unit.CurrentTarget
+	if target == nil || target.Type != EnemyUnit {
+		return 0
+	}
+	return sim.Encounter.Targets[target.Index].ThreatPercentOfTank(value.unit)
+}
+func (value *APLValueThreatPercentOfTank) String() string {
+	return fmt.Sprintf("Threat %% of Tank()")
+}
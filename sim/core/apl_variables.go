@@ -0,0 +1,285 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+type aplVariable struct {
+	name         string
+	valueType    proto.APLValueType
+	initialValue float64
+
+	// Booleans are stored as 1 or 0.
+	value float64
+}
+
+func (variable *aplVariable) reset() {
+	variable.value = variable.initialValue
+}
+
+type aplNamedExpression struct {
+	name   string
+	config *proto.APLValue
+
+	value    APLValue
+	parsing  bool
+	parsed   bool
+	cyclic   bool
+	warnings []string
+}
+
+func (rot *APLRotation) parseVariables(configs []*proto.APLVariable) {
+	rot.variables = make(map[string]*aplVariable, len(configs))
+	rot.variableWarnings = make([][]string, len(configs))
+	for i, config := range configs {
+		rot.doAndRecordWarnings(&rot.variableWarnings[i], false, func() {
+			if config.Name == "" {
+				rot.ValidationWarning("Variable has no name, so it can't be used")
+				return
+			}
+			if rot.variables[config.Name] != nil {
+				rot.ValidationWarning("Another variable is already named '%s', so this one is ignored", config.Name)
+				return
+			}
+			variable := &aplVariable{
+				name:         config.Name,
+				valueType:    proto.APLValueType_ValueTypeFloat,
+				initialValue: config.InitialValue,
+			}
+			if config.IsBool {
+				variable.valueType = proto.APLValueType_ValueTypeBool
+				variable.initialValue = TernaryFloat64(config.InitialValue != 0, 1, 0)
+			}
+			variable.value = variable.initialValue
+			rot.variables[config.Name] = variable
+		})
+	}
+}
+
+// Named expressions are all defined before any of them is parsed, so they can
+// reference each other in any order.
+func (rot *APLRotation) parseNamedExpressions(configs []*proto.APLNamedExpression) {
+	rot.namedExpressions = make(map[string]*aplNamedExpression, len(configs))
+	rot.namedExpressionWarnings = make([][]string, len(configs))
+	definitions := make([]*aplNamedExpression, len(configs))
+	for i, config := range configs {
+		rot.doAndRecordWarnings(&rot.namedExpressionWarnings[i], false, func() {
+			if config.Name == "" {
+				rot.ValidationWarning("Named expression has no name, so it can't be used")
+				return
+			}
+			if rot.namedExpressions[config.Name] != nil {
+				rot.ValidationWarning("Another named expression is already named '%s', so this one is ignored", config.Name)
+				return
+			}
+			if config.Value == nil {
+				rot.ValidationWarning("Named expression '%s' has no value", config.Name)
+			}
+			definitions[i] = &aplNamedExpression{
+				name:   config.Name,
+				config: config.Value,
+			}
+			rot.namedExpressions[config.Name] = definitions[i]
+		})
+	}
+
+	for i, expr := range definitions {
+		if expr == nil {
+			continue
+		}
+		if !expr.parsed {
+			rot.parseNamedExpression(expr)
+		}
+		rot.namedExpressionWarnings[i] = append(rot.namedExpressionWarnings[i], expr.warnings...)
+	}
+}
+
+// Parses the expression with its own warnings, as it may be parsed while
+// another item is being parsed.
+func (rot *APLRotation) parseNamedExpression(expr *aplNamedExpression) {
+	outerWarnings := rot.curWarnings
+	rot.curWarnings = nil
+
+	expr.parsing = true
+	rot.parsingExpressions = append(rot.parsingExpressions, expr)
+	value := rot.newAPLValue(expr.config)
+	rot.parsingExpressions = rot.parsingExpressions[:len(rot.parsingExpressions)-1]
+	expr.parsing = false
+	expr.parsed = true
+
+	if !expr.cyclic {
+		expr.value = value
+	}
+	expr.warnings = append(expr.warnings, rot.curWarnings...)
+	rot.curWarnings = outerWarnings
+}
+
+func (rot *APLRotation) getNamedExpression(name string) APLValue {
+	expr := rot.namedExpressions[name]
+	if expr == nil {
+		rot.ValidationWarning("No named expression with name: '%s'", name)
+		return nil
+	}
+
+	if expr.parsing {
+		// Every expression from the referenced one up to the current one is part of the cycle.
+		cycle := rot.parsingExpressions[slices.Index(rot.parsingExpressions, expr):]
+		names := MapSlice(cycle, func(e *aplNamedExpression) string { return e.name })
+		path := strings.Join(append(names, expr.name), " -> ")
+		for _, e := range cycle {
+			e.cyclic = true
+			e.warnings = append(e.warnings, fmt.Sprintf("Cyclic reference between named expressions (%s), so '%s' is ignored", path, e.name))
+		}
+		return nil
+	}
+
+	if !expr.parsed {
+		rot.parseNamedExpression(expr)
+	}
+	if expr.value == nil {
+		if len(rot.parsingExpressions) > 0 && rot.parsingExpressions[len(rot.parsingExpressions)-1].cyclic {
+			// Already reported as part of the cycle.
+			return nil
+		}
+		rot.ValidationWarning("Named expression '%s' is invalid, so that part of the condition is dropped", name)
+		return nil
+	}
+	return expr.value
+}
+
+func (rot *APLRotation) getVariable(name string) *aplVariable {
+	variable := rot.variables[name]
+	if variable == nil {
+		rot.ValidationWarning("No variable with name: '%s'", name)
+	}
+	return variable
+}
+
+func (rot *APLRotation) resetVariables() {
+	for _, variable := range rot.variables {
+		variable.reset()
+	}
+}
+
+type APLActionSetVariable struct {
+	defaultAPLActionImpl
+	unit     *Unit
+	variable *aplVariable
+	value    APLValue
+}
+
+func (rot *APLRotation) newActionSetVariable(config *proto.APLActionSetVariable) APLActionImpl {
+	variable := rot.getVariable(config.Name)
+	if variable == nil {
+		return nil
+	}
+	value := rot.coerceTo(rot.newAPLValue(config.Value), variable.valueType)
+	if value == nil {
+		rot.ValidationWarning("Set Variable '%s' has no value", config.Name)
+		return nil
+	}
+	return &APLActionSetVariable{
+		unit:     rot.unit,
+		variable: variable,
+		value:    value,
+	}
+}
+func (action *APLActionSetVariable) GetAPLValues() []APLValue {
+	return []APLValue{action.value}
+}
+func (action *APLActionSetVariable) newValue(sim *Simulation) float64 {
+	if action.variable.valueType == proto.APLValueType_ValueTypeBool {
+		return TernaryFloat64(action.value.GetBool(sim), 1, 0)
+	}
+	return action.value.GetFloat(sim)
+}
+
+// Only ready when the value would change, as setting a variable takes no time
+// and would otherwise be picked again right away.
+func (action *APLActionSetVariable) IsReady(sim *Simulation) bool {
+	return action.newValue(sim) != action.variable.value
+}
+func (action *APLActionSetVariable) Execute(sim *Simulation) {
+	action.variable.value = action.newValue(sim)
+	if sim.Log != nil {
+		action.unit.Log(sim, "Setting variable '%s' to %0.3f", action.variable.name, action.variable.value)
+	}
+}
+func (action *APLActionSetVariable) String() string {
+	return fmt.Sprintf("Set Variable(%s = %s)", action.variable.name, action.value)
+}
+
+type APLValueVariable struct {
+	DefaultAPLValueImpl
+	variable *aplVariable
+}
+
+func (rot *APLRotation) newValueVariable(config *proto.APLValueVariable) APLValue {
+	variable := rot.getVariable(config.Name)
+	if variable == nil {
+		return nil
+	}
+	return &APLValueVariable{
+		variable: variable,
+	}
+}
+func (value *APLValueVariable) Type() proto.APLValueType {
+	return value.variable.valueType
+}
+func (value *APLValueVariable) GetBool(sim *Simulation) bool {
+	return value.variable.value != 0
+}
+func (value *APLValueVariable) GetFloat(sim *Simulation) float64 {
+	return value.variable.value
+}
+func (value *APLValueVariable) String() string {
+	return fmt.Sprintf("Variable(%s)", value.variable.name)
+}
+
+// Refers to the shared value of a named expression. The inner value is listed
+// so that actions see everything their conditions depend on.
+type APLValueNamedExpression struct {
+	name  string
+	inner APLValue
+}
+
+func (rot *APLRotation) newValueNamedExpression(config *proto.APLValueNamedExpression) APLValue {
+	inner := rot.getNamedExpression(config.Name)
+	if inner == nil {
+		return nil
+	}
+	return &APLValueNamedExpression{
+		name:  config.Name,
+		inner: inner,
+	}
+}
+func (value *APLValueNamedExpression) GetInnerValues() []APLValue {
+	return []APLValue{value.inner}
+}
+func (value *APLValueNamedExpression) Type() proto.APLValueType {
+	return value.inner.Type()
+}
+func (value *APLValueNamedExpression) GetBool(sim *Simulation) bool {
+	return value.inner.GetBool(sim)
+}
+func (value *APLValueNamedExpression) GetInt(sim *Simulation) int32 {
+	return value.inner.GetInt(sim)
+}
+func (value *APLValueNamedExpression) GetFloat(sim *Simulation) float64 {
+	return value.inner.GetFloat(sim)
+}
+func (value *APLValueNamedExpression) GetDuration(sim *Simulation) time.Duration {
+	return value.inner.GetDuration(sim)
+}
+func (value *APLValueNamedExpression) GetString(sim *Simulation) string {
+	return value.inner.GetString(sim)
+}
+func (value *APLValueNamedExpression) Finalize(*APLRotation) {}
+func (value *APLValueNamedExpression) String() string {
+	return fmt.Sprintf("Expression(%s)", value.name)
+}
@@ -376,7 +376,7 @@ func (wa *WeaponAttack) swing(sim *Simulation) time.Duration {
 			sim.rescheduleWeaponAttack(wa.swingAt) // Required to fix extra attack procs triggered during swing
 		}
 
-		if !sim.Options.Interactive && wa.unit.Rotation != nil {
+		if !sim.isInteractive(wa.unit) && wa.unit.Rotation != nil {
 			wa.unit.Rotation.DoNextAction(sim)
 		}
 	} else {
@@ -701,6 +701,10 @@ func (aa *AutoAttacks) EnableAutoSwing(sim *Simulation) {
 		return
 	}
 
+	if target := aa.mh.unit.CurrentTarget; target != nil && target.IsUntargetable() {
+		return
+	}
+
 	if aa.enabled {
 		return
 	}
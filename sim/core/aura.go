@@ -64,6 +64,11 @@ type Aura struct {
 	// The unit this aura is attached to.
 	Unit *Unit
 
+	// The unit which applied this aura, if known. Only used for combat logs.
+	casterUnit *Unit
+	// The unit whose spell last applied this debuff, for debuffs without a casterUnit.
+	appliedBy *Unit
+
 	active                     bool
 	activeIndex                int32 // Position of this aura's index in the activeAuras array.
 	onCastCompleteIndex        int32 // Position of this aura's index in the onCastCompleteAuras array.
@@ -179,6 +184,9 @@ func (aura *Aura) Refresh(sim *Simulation) {
 	if sim.Log != nil && aura.IsActive() && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && aura.IsActive() {
+		aura.logAuraEvent(sim, CombatLogSpellAuraRefresh)
+	}
 }
 
 func (aura *Aura) GetStacks() int32 {
@@ -209,6 +217,13 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
+	if sim.CombatLog != nil && oldStacks != 0 && newStacks != 0 {
+		if newStacks > oldStacks {
+			aura.logAuraEvent(sim, CombatLogSpellAuraAppliedDose)
+		} else {
+			aura.logAuraEvent(sim, CombatLogSpellAuraRemovedDose)
+		}
+	}
 	if aura.OnStacksChange != nil {
 		aura.OnStacksChange(aura, sim, oldStacks, newStacks)
 	}
@@ -647,6 +662,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil {
+		aura.logAuraEvent(sim, CombatLogSpellAuraApplied)
+	}
 
 	// don't invoke possible callbacks until the internal state is consistent
 	if aura.OnGain != nil {
@@ -669,6 +687,9 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		if sim.Log != nil {
 			aura.Unit.Log(sim, "Aura faded: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil {
+			aura.logAuraEvent(sim, CombatLogSpellAuraRemoved)
+		}
 		sim.CurrentTime = oldTime
 	}
 
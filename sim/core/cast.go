@@ -17,6 +17,7 @@ type Hardcast struct {
 	ActionID   ActionID
 	OnComplete func(*Simulation, *Unit)
 	Target     *Unit
+	Spell      *Spell
 }
 
 // Input for constructing the CastSpell function for a spell.
@@ -266,6 +267,9 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
 					spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			}
+			if sim.CombatLog != nil {
+				spell.logCastStart(sim, target)
+			}
 
 			spell.Unit.Hardcast = Hardcast{
 				Expires:  sim.CurrentTime + spell.CurCast.CastTime,
@@ -291,11 +295,12 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 						spell.Unit.OnCastComplete(sim, spell)
 					}
 
-					if !sim.Options.Interactive {
+					if !sim.isInteractive(spell.Unit) {
 						spell.Unit.Rotation.DoNextAction(sim)
 					}
 				},
 				Target: target,
+				Spell:  spell,
 			}
 
 			if spell.Unit.Hardcast.Expires != spell.Unit.NextGCDAt() {
@@ -115,6 +115,8 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 			ChannelClipDelay:        max(0, time.Duration(player.ChannelClipDelayMs)*time.Millisecond),
 			DistanceFromTarget:      player.DistanceFromTarget,
 			StartDistanceFromTarget: player.DistanceFromTarget,
+			StartPosition:           PositionFromProto(player.Position),
+			hasStartPosition:        player.Position != nil,
 		},
 
 		Name:  player.Name,
@@ -443,7 +445,7 @@ func (character *Character) initialize(agent Agent) {
 				return
 			}
 
-			if sim.Options.Interactive {
+			if sim.isInteractive(&character.Unit) {
 				if character.GCD.IsReady(sim) {
 					sim.NeedsInput = true
 				}
@@ -650,6 +652,9 @@ func (character *Character) GetMetricsProto() *proto.UnitMetrics {
 	metrics.Name = character.Name
 	metrics.UnitIndex = character.UnitIndex
 	metrics.Auras = character.auraTracker.GetMetricsProto()
+	if character.Type == PlayerUnit && character.Rotation != nil {
+		metrics.RotationStats = character.Rotation.getMetricsProto(character.Metrics.dps.n)
+	}
 
 	metrics.Pets = make([]*proto.UnitMetrics, len(character.Pets))
 	for i, pet := range character.Pets {
@@ -0,0 +1,442 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// Subevent names, matching the in-game COMBAT_LOG_EVENT_UNFILTERED names.
+type CombatLogEventType string
+
+const (
+	CombatLogSpellCastStart       CombatLogEventType = "SPELL_CAST_START"
+	CombatLogSpellCastSuccess     CombatLogEventType = "SPELL_CAST_SUCCESS"
+	CombatLogSpellDamage          CombatLogEventType = "SPELL_DAMAGE"
+	CombatLogSpellMissed          CombatLogEventType = "SPELL_MISSED"
+	CombatLogSpellPeriodicDamage  CombatLogEventType = "SPELL_PERIODIC_DAMAGE"
+	CombatLogSpellPeriodicMissed  CombatLogEventType = "SPELL_PERIODIC_MISSED"
+	CombatLogSpellHeal            CombatLogEventType = "SPELL_HEAL"
+	CombatLogSpellPeriodicHeal    CombatLogEventType = "SPELL_PERIODIC_HEAL"
+	CombatLogSwingDamage          CombatLogEventType = "SWING_DAMAGE"
+	CombatLogSwingMissed          CombatLogEventType = "SWING_MISSED"
+	CombatLogSpellEnergize        CombatLogEventType = "SPELL_ENERGIZE"
+	CombatLogSpellAuraApplied     CombatLogEventType = "SPELL_AURA_APPLIED"
+	CombatLogSpellAuraRemoved     CombatLogEventType = "SPELL_AURA_REMOVED"
+	CombatLogSpellAuraRefresh     CombatLogEventType = "SPELL_AURA_REFRESH"
+	CombatLogSpellAuraAppliedDose CombatLogEventType = "SPELL_AURA_APPLIED_DOSE"
+	CombatLogSpellAuraRemovedDose CombatLogEventType = "SPELL_AURA_REMOVED_DOSE"
+)
+
+// A single structured combat event. Which fields are meaningful depends on the Type.
+type CombatLogEvent struct {
+	Timestamp time.Duration
+	Type      CombatLogEventType
+
+	// Source may be nil if unknown, e.g. for debuffs applied by another unit.
+	Source *Unit
+	Target *Unit
+
+	ActionID    ActionID
+	SpellSchool SpellSchool
+
+	// Name of the spell, aura or item, or empty if it has none.
+	Name string
+
+	// Damage / healing / resource amount, and the part of it that was wasted
+	// (overhealing, overenergize).
+	Amount     float64
+	OverAmount float64
+	Outcome    HitOutcome
+
+	ResourceType proto.ResourceType
+
+	IsDebuff bool
+	Stacks   int32
+}
+
+func (sim *Simulation) logCombatEvent(event CombatLogEvent) {
+	event.Timestamp = sim.CurrentTime
+	sim.CombatLog(&event)
+}
+
+func (spell *Spell) logCastStart(sim *Simulation, target *Unit) {
+	spell.logCastEvent(sim, CombatLogSpellCastStart, target)
+}
+
+func (spell *Spell) logCastSuccess(sim *Simulation, target *Unit) {
+	spell.logCastEvent(sim, CombatLogSpellCastSuccess, target)
+}
+
+func (spell *Spell) logCastEvent(sim *Simulation, eventType CombatLogEventType, target *Unit) {
+	if spell.ProcMask.Matches(ProcMaskMeleeWhiteHit) || spell.ActionID.IsEmptyAction() || spell.Flags.Matches(SpellFlagNoLogs) {
+		return
+	}
+	sim.logCombatEvent(CombatLogEvent{
+		Type:        eventType,
+		Source:      spell.Unit,
+		Target:      target,
+		ActionID:    spell.ActionID,
+		SpellSchool: spell.SpellSchool,
+		Name:        spell.combatLogName(),
+	})
+}
+
+func (spell *Spell) logDamage(sim *Simulation, isPeriodic bool, result *SpellResult) {
+	if spell.Flags.Matches(SpellFlagNoLogs) {
+		return
+	}
+	var eventType CombatLogEventType
+	landed := result.Landed()
+	switch {
+	case isPeriodic && landed:
+		eventType = CombatLogSpellPeriodicDamage
+	case isPeriodic:
+		eventType = CombatLogSpellPeriodicMissed
+	case spell.ProcMask.Matches(ProcMaskMeleeWhiteHit) && landed:
+		eventType = CombatLogSwingDamage
+	case spell.ProcMask.Matches(ProcMaskMeleeWhiteHit):
+		eventType = CombatLogSwingMissed
+	case landed:
+		eventType = CombatLogSpellDamage
+	default:
+		eventType = CombatLogSpellMissed
+	}
+	sim.logCombatEvent(CombatLogEvent{
+		Type:        eventType,
+		Source:      spell.Unit,
+		Target:      result.Target,
+		ActionID:    spell.ActionID,
+		SpellSchool: spell.SpellSchool,
+		Name:        spell.combatLogName(),
+		Amount:      result.Damage,
+		Outcome:     result.Outcome,
+	})
+}
+
+func (spell *Spell) logHealing(sim *Simulation, isPeriodic bool, result *SpellResult, overHealing float64) {
+	if spell.Flags.Matches(SpellFlagNoLogs) {
+		return
+	}
+	eventType := CombatLogSpellHeal
+	if isPeriodic {
+		eventType = CombatLogSpellPeriodicHeal
+	}
+	sim.logCombatEvent(CombatLogEvent{
+		Type:        eventType,
+		Source:      spell.Unit,
+		Target:      result.Target,
+		ActionID:    spell.ActionID,
+		SpellSchool: spell.SpellSchool,
+		Name:        spell.combatLogName(),
+		Amount:      result.Damage,
+		OverAmount:  overHealing,
+		Outcome:     result.Outcome,
+	})
+}
+
+func (unit *Unit) logEnergize(sim *Simulation, amount float64, actualGain float64, metrics *ResourceMetrics) {
+	if amount == 0 {
+		return
+	}
+	sim.logCombatEvent(CombatLogEvent{
+		Type:         CombatLogSpellEnergize,
+		Source:       unit,
+		Target:       unit,
+		ActionID:     metrics.ActionID,
+		Name:         unit.combatLogActionName(metrics.ActionID),
+		Amount:       amount,
+		OverAmount:   amount - actualGain,
+		ResourceType: metrics.Type,
+	})
+}
+
+func (aura *Aura) logAuraEvent(sim *Simulation, eventType CombatLogEventType) {
+	if aura.ActionID.IsEmptyAction() {
+		return
+	}
+	isDebuff := aura.Unit.Type == EnemyUnit
+	if spell := sim.combatLogSpell; isDebuff && spell != nil && spell.Unit.Type != EnemyUnit && eventType != CombatLogSpellAuraRemoved {
+		aura.appliedBy = spell.Unit
+	}
+
+	source := aura.casterUnit
+	if source == nil && isDebuff {
+		source = aura.appliedBy
+	} else if source == nil {
+		source = aura.Unit
+	}
+	sim.logCombatEvent(CombatLogEvent{
+		Type:     eventType,
+		Source:   source,
+		Target:   aura.Unit,
+		ActionID: aura.ActionID,
+		Name:     aura.combatLogName(),
+		IsDebuff: isDebuff,
+		Stacks:   aura.stacks,
+	})
+}
+
+// Spells have no names of their own, so they're named after the auras they apply,
+// or the item they belong to.
+func (spell *Spell) combatLogName() string {
+	if spell.aoeDot != nil {
+		return spell.aoeDot.Aura.combatLogName()
+	}
+	if spell.selfShield != nil {
+		return spell.selfShield.Aura.combatLogName()
+	}
+	for _, dot := range spell.dots {
+		if dot != nil {
+			return dot.Aura.combatLogName()
+		}
+	}
+	for _, shield := range spell.shields {
+		if shield != nil {
+			return shield.Aura.combatLogName()
+		}
+	}
+	return spell.Unit.combatLogActionName(spell.ActionID)
+}
+
+func (unit *Unit) combatLogActionName(actionID ActionID) string {
+	if item, ok := ItemsByID[actionID.ItemID]; ok && actionID.ItemID != 0 {
+		return item.Name
+	}
+	if aura := unit.GetAuraByID(actionID); aura != nil {
+		return aura.combatLogName()
+	}
+	return ""
+}
+
+// Per-target dot and shield auras have their caster's index appended to the label.
+func (aura *Aura) combatLogName() string {
+	if aura.casterUnit == nil {
+		return aura.Label
+	}
+	return strings.TrimSuffix(aura.Label, "-"+strconv.Itoa(int(aura.casterUnit.UnitIndex)))
+}
+
+// Formats combat events in the same layout as the game client's WoWCombatLog.txt,
+// so the output can be loaded by existing log analysis tools.
+type CombatLogWriter struct {
+	sb strings.Builder
+
+	// Wall clock time corresponding to sim time 0. Prepull events have negative sim
+	// times, so this shouldn't be too close to midnight.
+	startTime time.Time
+}
+
+func NewCombatLogWriter() *CombatLogWriter {
+	writer := &CombatLogWriter{
+		startTime: time.Date(2024, time.January, 1, 20, 0, 0, 0, time.UTC),
+	}
+	writer.writeLine(writer.startTime.Add(-time.Minute), "COMBAT_LOG_VERSION,9,ADVANCED_LOG_ENABLED,0,BUILD_VERSION,1.15.0,PROJECT_ID,2")
+	return writer
+}
+
+func (writer *CombatLogWriter) String() string {
+	return writer.sb.String()
+}
+
+func (writer *CombatLogWriter) writeLine(t time.Time, line string) {
+	writer.sb.WriteString(t.Format("1/2 15:04:05.000"))
+	writer.sb.WriteString("  ")
+	writer.sb.WriteString(line)
+	writer.sb.WriteString("\n")
+}
+
+func (writer *CombatLogWriter) AddEvent(event *CombatLogEvent) {
+	params := []string{
+		string(event.Type),
+		combatLogGUID(event.Source), combatLogName(event.Source), combatLogFlags(event.Source), "0x0",
+		combatLogGUID(event.Target), combatLogName(event.Target), combatLogFlags(event.Target), "0x0",
+	}
+
+	isSwing := event.Type == CombatLogSwingDamage || event.Type == CombatLogSwingMissed
+	if !isSwing {
+		params = append(params, combatLogSpellID(event.ActionID), combatLogSpellName(event), combatLogSchool(event.SpellSchool))
+	}
+
+	switch event.Type {
+	case CombatLogSpellDamage, CombatLogSpellPeriodicDamage, CombatLogSwingDamage:
+		params = append(params,
+			combatLogAmount(event.Amount),
+			"-1", // overkill
+			combatLogSchool(event.SpellSchool),
+			combatLogAmount(combatLogResisted(event.Amount, event.Outcome)),
+			"0", // blocked
+			"0", // absorbed
+			combatLogBool(event.Outcome.Matches(OutcomeCrit)),
+			combatLogBool(event.Outcome.Matches(OutcomeGlance)),
+			combatLogBool(event.Outcome.Matches(OutcomeCrush)),
+		)
+	case CombatLogSpellMissed, CombatLogSpellPeriodicMissed, CombatLogSwingMissed:
+		params = append(params, combatLogMissType(event.Outcome, event.SpellSchool))
+	case CombatLogSpellHeal, CombatLogSpellPeriodicHeal:
+		params = append(params,
+			combatLogAmount(event.Amount),
+			combatLogAmount(event.OverAmount),
+			"0", // absorbed
+			combatLogBool(event.Outcome.Matches(OutcomeCrit)),
+		)
+	case CombatLogSpellEnergize:
+		params = append(params,
+			combatLogAmount(event.Amount),
+			combatLogAmount(event.OverAmount),
+			combatLogPowerType(event.ResourceType),
+		)
+	case CombatLogSpellAuraApplied, CombatLogSpellAuraRemoved, CombatLogSpellAuraRefresh:
+		params = append(params, combatLogAuraType(event.IsDebuff))
+	case CombatLogSpellAuraAppliedDose, CombatLogSpellAuraRemovedDose:
+		params = append(params, combatLogAuraType(event.IsDebuff), strconv.Itoa(int(event.Stacks)))
+	}
+
+	writer.writeLine(writer.startTime.Add(event.Timestamp), strings.Join(params, ","))
+}
+
+// Falls back to the ActionID for unnamed spells, so they can still be told apart.
+func combatLogSpellName(event *CombatLogEvent) string {
+	if event.Name == "" {
+		return strconv.Quote(event.ActionID.String())
+	}
+	return strconv.Quote(event.Name)
+}
+
+func combatLogGUID(unit *Unit) string {
+	if unit == nil {
+		return "0000000000000000"
+	}
+	switch unit.Type {
+	case PlayerUnit:
+		return fmt.Sprintf("Player-0-%08X", unit.UnitIndex)
+	case PetUnit:
+		return fmt.Sprintf("Pet-0-0-0-0-0-%010X", unit.UnitIndex)
+	default:
+		return fmt.Sprintf("Creature-0-0-0-0-0-%010X", unit.UnitIndex)
+	}
+}
+
+func combatLogName(unit *Unit) string {
+	if unit == nil {
+		return "nil"
+	}
+	return strconv.Quote(unit.Label)
+}
+
+func combatLogFlags(unit *Unit) string {
+	if unit == nil {
+		return "0x0"
+	}
+	switch unit.Type {
+	case PlayerUnit:
+		return "0x514" // Player, controlled by player, friendly, in raid.
+	case PetUnit:
+		return "0x1114" // Pet, controlled by player, friendly, in raid.
+	default:
+		return "0xa48" // NPC, controlled by NPC, hostile, outsider.
+	}
+}
+
+// Item actions, e.g. on-use trinkets, are written with their item ID.
+func combatLogSpellID(actionID ActionID) string {
+	if actionID.SpellID != 0 {
+		return strconv.Itoa(int(actionID.SpellID))
+	}
+	if actionID.ItemID != 0 {
+		return strconv.Itoa(int(actionID.ItemID))
+	}
+	return "0"
+}
+
+// Converts the sim's school mask into the game's school mask.
+func combatLogSchool(school SpellSchool) string {
+	mask := 0
+	for _, pair := range []struct {
+		school SpellSchool
+		mask   int
+	}{
+		{SpellSchoolPhysical, 0x1},
+		{SpellSchoolHoly, 0x2},
+		{SpellSchoolFire, 0x4},
+		{SpellSchoolNature, 0x8},
+		{SpellSchoolFrost, 0x10},
+		{SpellSchoolShadow, 0x20},
+		{SpellSchoolArcane, 0x40},
+	} {
+		if school.Matches(pair.school) {
+			mask |= pair.mask
+		}
+	}
+	return fmt.Sprintf("0x%x", mask)
+}
+
+// Partial resists remove a fixed fraction of the pre-resist damage, so the resisted
+// part can be recovered from the damage that landed.
+func combatLogResisted(damage float64, outcome HitOutcome) float64 {
+	switch {
+	case outcome.Matches(OutcomePartial1_4):
+		return damage / 3
+	case outcome.Matches(OutcomePartial2_4):
+		return damage
+	case outcome.Matches(OutcomePartial3_4):
+		return damage * 3
+	default:
+		return 0
+	}
+}
+
+func combatLogMissType(outcome HitOutcome, school SpellSchool) string {
+	switch {
+	case outcome.Matches(OutcomeDodge):
+		return "DODGE"
+	case outcome.Matches(OutcomeParry):
+		return "PARRY"
+	case outcome.Matches(OutcomeBlock):
+		return "BLOCK"
+	case school.Matches(SpellSchoolPhysical):
+		return "MISS"
+	default:
+		return "RESIST"
+	}
+}
+
+func combatLogPowerType(resourceType proto.ResourceType) string {
+	switch resourceType {
+	case proto.ResourceType_ResourceTypeMana:
+		return "0"
+	case proto.ResourceType_ResourceTypeRage:
+		return "1"
+	case proto.ResourceType_ResourceTypeFocus:
+		return "2"
+	case proto.ResourceType_ResourceTypeEnergy:
+		return "3"
+	case proto.ResourceType_ResourceTypeComboPoints:
+		return "4"
+	case proto.ResourceType_ResourceTypeHealth:
+		return "-2"
+	default:
+		return "-1"
+	}
+}
+
+func combatLogAuraType(isDebuff bool) string {
+	if isDebuff {
+		return "DEBUFF"
+	}
+	return "BUFF"
+}
+
+func combatLogAmount(amount float64) string {
+	return strconv.Itoa(int(amount + 0.5))
+}
+
+func combatLogBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "nil"
+}
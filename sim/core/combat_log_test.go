@@ -0,0 +1,268 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+func TestCombatLogWriterFormat(t *testing.T) {
+	player := &Unit{Type: PlayerUnit, UnitIndex: 1, Label: "Mage (#1)"}
+	target := &Unit{Type: EnemyUnit, UnitIndex: 2, Label: "Target 1"}
+
+	writer := NewCombatLogWriter()
+	writer.AddEvent(&CombatLogEvent{
+		Timestamp:   time.Millisecond * 1500,
+		Type:        CombatLogSpellDamage,
+		Source:      player,
+		Target:      target,
+		ActionID:    ActionID{SpellID: 133},
+		SpellSchool: SpellSchoolFire,
+		Name:        "Fireball",
+		Amount:      120.4,
+		Outcome:     OutcomeCrit | OutcomePartial1_4,
+	})
+	writer.AddEvent(&CombatLogEvent{
+		Timestamp: 0,
+		Type:      CombatLogSwingMissed,
+		Source:    player,
+		Target:    target,
+		ActionID:  ActionID{OtherID: proto.OtherAction_OtherActionAttack},
+		Outcome:   OutcomeDodge,
+	})
+	writer.AddEvent(&CombatLogEvent{
+		Timestamp:    -time.Second,
+		Type:         CombatLogSpellEnergize,
+		Source:       player,
+		Target:       player,
+		ActionID:     ActionID{SpellID: 12051},
+		Amount:       300,
+		OverAmount:   50,
+		ResourceType: proto.ResourceType_ResourceTypeMana,
+	})
+	writer.AddEvent(&CombatLogEvent{
+		Timestamp:   time.Second,
+		Type:        CombatLogSpellCastSuccess,
+		Source:      player,
+		Target:      player,
+		ActionID:    ActionID{ItemID: 19339},
+		SpellSchool: SpellSchoolPhysical,
+	})
+	writer.AddEvent(&CombatLogEvent{
+		Timestamp: time.Second * 2,
+		Type:      CombatLogSpellAuraAppliedDose,
+		Target:    target,
+		ActionID:  ActionID{SpellID: 12873},
+		IsDebuff:  true,
+		Stacks:    3,
+	})
+
+	expected := []string{
+		`1/1 19:59:00.000  COMBAT_LOG_VERSION,9,ADVANCED_LOG_ENABLED,0,BUILD_VERSION,1.15.0,PROJECT_ID,2`,
+		`1/1 20:00:01.500  SPELL_DAMAGE,Player-0-00000001,"Mage (#1)",0x514,0x0,Creature-0-0-0-0-0-0000000002,"Target 1",0xa48,0x0,133,"Fireball",0x4,120,-1,0x4,40,0,0,1,nil,nil`,
+		`1/1 20:00:00.000  SWING_MISSED,Player-0-00000001,"Mage (#1)",0x514,0x0,Creature-0-0-0-0-0-0000000002,"Target 1",0xa48,0x0,DODGE`,
+		`1/1 19:59:59.000  SPELL_ENERGIZE,Player-0-00000001,"Mage (#1)",0x514,0x0,Player-0-00000001,"Mage (#1)",0x514,0x0,12051,"{SpellID: 12051}",0x0,300,50,0`,
+		`1/1 20:00:01.000  SPELL_CAST_SUCCESS,Player-0-00000001,"Mage (#1)",0x514,0x0,Player-0-00000001,"Mage (#1)",0x514,0x0,19339,"{ItemID: 19339}",0x1`,
+		`1/1 20:00:02.000  SPELL_AURA_APPLIED_DOSE,0000000000000000,nil,0x0,0x0,Creature-0-0-0-0-0-0000000002,"Target 1",0xa48,0x0,12873,"{SpellID: 12873}",0x0,DEBUFF,3`,
+	}
+	lines := strings.Split(strings.TrimSuffix(writer.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), writer.String())
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d:\nexpected %s\n     got %s", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestCombatLogDebuffSource(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Level:     60,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Debuffs: &proto.Debuffs{FaerieFire: true},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	player := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	target := sim.GetTargetUnit(0)
+	faerieFire := target.GetAura("Faerie Fire")
+	faerieFire.Deactivate(sim)
+
+	var events []*CombatLogEvent
+	sim.CombatLog = func(event *CombatLogEvent) {
+		events = append(events, event)
+	}
+
+	// Raid debuffs aren't dots, so their source comes from the spell applying them.
+	spell := &Spell{
+		Unit:         player,
+		ActionID:     ActionID{SpellID: 9907},
+		SpellMetrics: make([]SpellMetrics, len(sim.Environment.AllUnits)),
+		ApplyEffects: func(sim *Simulation, _ *Unit, _ *Spell) {
+			faerieFire.Activate(sim)
+		},
+	}
+	spell.applyEffects(sim, target)
+	faerieFire.Deactivate(sim)
+
+	var auraEvents int
+	for _, event := range events {
+		if event.Type != CombatLogSpellAuraApplied && event.Type != CombatLogSpellAuraRemoved {
+			continue
+		}
+		auraEvents++
+		if event.Source != player {
+			t.Errorf("Expected %s to be logged with the player as source, got %v", event.Type, event.Source)
+		}
+	}
+	if auraEvents != 2 {
+		t.Errorf("Expected the debuff to be applied and removed, got %d aura events", auraEvents)
+	}
+}
+
+func TestCombatLogFromSim(t *testing.T) {
+	spellValue := func(spellID int32) *proto.ActionID {
+		return &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: spellID}}
+	}
+	result := RunRaidSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Level:     60,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation: &proto.APLRotation{
+						Type: proto.APLRotation_TypeAPL,
+						PriorityList: []*proto.APLListItem{
+							{Action: &proto.APLAction{
+								Condition: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{
+									Val: &proto.APLValue{Value: &proto.APLValue_DotIsActive{DotIsActive: &proto.APLValueDotIsActive{SpellId: spellValue(42)}}},
+								}}},
+								Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: spellValue(42)}},
+							}},
+							{Action: &proto.APLAction{Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{
+								Duration: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: "1s"}}},
+							}}}},
+						},
+					},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Debuffs: &proto.Debuffs{FaerieFire: true},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 30,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			IsTest:     true,
+			RandomSeed: 101,
+			CombatLog:  true,
+		},
+	})
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+
+	// Number of comma separated fields for each event type, including the event type itself.
+	expectedFields := map[string]int{
+		"SPELL_CAST_SUCCESS":    12,
+		"SPELL_DAMAGE":          21,
+		"SPELL_MISSED":          13,
+		"SPELL_PERIODIC_DAMAGE": 21,
+		"SPELL_AURA_APPLIED":    13,
+		"SPELL_AURA_REMOVED":    13,
+	}
+	lines := strings.Split(strings.TrimSuffix(result.CombatLog, "\n"), "\n")
+	if !strings.HasSuffix(lines[0], "COMBAT_LOG_VERSION,9,ADVANCED_LOG_ENABLED,0,BUILD_VERSION,1.15.0,PROJECT_ID,2") {
+		t.Fatalf("Expected the log to start with the version header, got %s", lines[0])
+	}
+
+	counts := make(map[string]int)
+	var damage float64
+	for _, line := range lines[1:] {
+		timestamp, event, ok := strings.Cut(line, "  ")
+		if !ok {
+			t.Fatalf("Expected a timestamp and event separated by 2 spaces, got %s", line)
+		}
+		if _, err := time.Parse("1/2 15:04:05.000", timestamp); err != nil {
+			t.Errorf("Expected a valid timestamp, got %s: %s", timestamp, err)
+		}
+
+		fields := strings.Split(event, ",")
+		if expected, ok := expectedFields[fields[0]]; !ok || len(fields) != expected {
+			t.Fatalf("Expected a known event type with %d fields, got %d: %s", expected, len(fields), line)
+		}
+		name, err := strconv.Unquote(fields[10])
+		if err != nil {
+			t.Fatalf("Expected a quoted spell name, got %s: %s", fields[10], err)
+		}
+		switch fields[9] {
+		case "42":
+			if name != "fakedot" || fields[2] != `"Caster (#1)"` {
+				t.Errorf("Expected spell 42 to be named after its dot and cast by the player, got %s", line)
+			}
+		case "9907":
+			if name != "Faerie Fire" {
+				t.Errorf("Expected spell 9907 to be named after its debuff, got %s", line)
+			}
+		default:
+			t.Errorf("Unexpected spell in %s", line)
+		}
+
+		counts[fields[0]+" "+name]++
+		if fields[0] == "SPELL_DAMAGE" || fields[0] == "SPELL_PERIODIC_DAMAGE" {
+			amount, err := strconv.ParseFloat(fields[12], 64)
+			if err != nil {
+				t.Fatalf("Expected a damage amount, got %s: %s", fields[12], err)
+			}
+			if fields[0] == "SPELL_PERIODIC_DAMAGE" && amount != 150 {
+				t.Errorf("Expected dot ticks of 150 damage, got %s", line)
+			}
+			damage += amount
+		}
+	}
+
+	// The dot is only applied by casts which hit, and is recast once it has faded.
+	hits := counts["SPELL_DAMAGE fakedot"]
+	if hits == 0 || counts["SPELL_CAST_SUCCESS fakedot"] != hits+counts["SPELL_MISSED fakedot"] {
+		t.Errorf("Expected every cast of the dot to hit or miss, got %v", counts)
+	}
+	if counts["SPELL_AURA_APPLIED fakedot"] != hits || counts["SPELL_AURA_REMOVED fakedot"] != hits {
+		t.Errorf("Expected an uptime of the dot for each hit, got %v", counts)
+	}
+	if counts["SPELL_AURA_APPLIED Faerie Fire"] != 1 || counts["SPELL_AURA_REMOVED Faerie Fire"] != 1 {
+		t.Errorf("Expected Faerie Fire to be up for the whole fight, got %v", counts)
+	}
+	if simDamage := result.RaidMetrics.Dps.Avg * 30; math.Abs(simDamage-damage) > 0.01 {
+		t.Errorf("Expected the logged damage to match the sim's %0.1f damage, got %0.1f", simDamage, damage)
+	}
+}
@@ -266,6 +266,7 @@ func newDot(config Dot) *Dot {
 
 	dot.tickPeriod = dot.TickLength
 	dot.Aura.Duration = dot.TickLength * time.Duration(dot.NumberOfTicks)
+	dot.Aura.casterUnit = dot.Spell.Unit
 
 	dot.Aura.ApplyOnGain(func(aura *Aura, sim *Simulation) {
 		dot.lastTickTime = sim.CurrentTime
@@ -177,3 +177,28 @@ func TestDotSnapshotSpellMultiplier(t *testing.T) {
 	fa.Dot.Rollover(sim)
 	expectDotTickDamage(t, sim, fa.Dot, 300) // (100) * 1.5 * 2
 }
+
+func TestHotSnapshotUsesHealingModifiers(t *testing.T) {
+	sim := SetupFakeSim()
+	fa := sim.Raid.Parties[0].Players[0].(*FakeAgent)
+	character := fa.GetCharacter()
+
+	// Damage modifiers must not affect healing over time, just like they don't
+	// affect direct heals.
+	character.PseudoStats.DamageDealtMultiplier = 4
+	character.PseudoStats.HealingDealtMultiplier = 2
+
+	fa.Dot.SnapshotHeal(&character.Unit, 100, false)
+	if expected := fa.Spell.CasterHealingMultiplier(); fa.Dot.SnapshotAttackerMultiplier != expected {
+		t.Errorf("Expected a snapshot multiplier of %0.3f, got %0.3f", expected, fa.Dot.SnapshotAttackerMultiplier)
+	}
+	if expected := fa.Spell.HealingCritChance(); fa.Dot.SnapshotCritChance != expected {
+		t.Errorf("Expected a snapshot crit chance of %0.3f, got %0.3f", expected, fa.Dot.SnapshotCritChance)
+	}
+
+	tick := fa.Dot.CalcSnapshotHealing(sim, &character.Unit, fa.Dot.OutcomeTick)
+	direct := fa.Spell.CalcHealing(sim, &character.Unit, 100, fa.Spell.OutcomeHealing)
+	if !WithinToleranceFloat64(direct.Damage, tick.Damage, 0.01) {
+		t.Errorf("Expected a tick to heal as much as a direct heal with the same base: %0.3f, got %0.3f", direct.Damage, tick.Damage)
+	}
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// A scripted window in which players can't act normally, possibly repeating.
+type DowntimeEvent struct {
+	Type     proto.DowntimeEvent_Type
+	Start    time.Duration
+	Duration time.Duration
+	Interval time.Duration
+
+	// The target which becomes untargetable, for untargetable downtime.
+	Target *Unit
+}
+
+func newDowntimeEvents(configs []*proto.DowntimeEvent, targets []*Unit) []*DowntimeEvent {
+	var events []*DowntimeEvent
+	for _, config := range configs {
+		if config.Duration <= 0 {
+			continue
+		}
+		event := &DowntimeEvent{
+			Type:     config.Type,
+			Start:    DurationFromSeconds(config.Start),
+			Duration: DurationFromSeconds(config.Duration),
+			Interval: DurationFromSeconds(config.Interval),
+		}
+		if event.Interval > 0 {
+			// Overlapping repeats would just be one long downtime.
+			event.Interval = max(event.Interval, event.Duration)
+		}
+		if event.Type == proto.DowntimeEvent_TypeUntargetable {
+			if config.TargetIndex < 0 || int(config.TargetIndex) >= len(targets) {
+				continue
+			}
+			event.Target = targets[config.TargetIndex]
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// Returns the start of the first occurrence which hasn't ended by the given
+// time, or NeverExpires if there are no more.
+func (event *DowntimeEvent) nextOccurrence(at time.Duration) time.Duration {
+	if at < event.Start+event.Duration {
+		return event.Start
+	}
+	if event.Interval <= 0 {
+		return NeverExpires
+	}
+	return event.Start + ((at-event.Start-event.Duration)/event.Interval+1)*event.Interval
+}
+
+// Returns how long until the next downtime starts, 0 during downtime, or the
+// remaining fight duration if there's no more downtime.
+func (encounter *Encounter) TimeUntilNextDowntime(sim *Simulation) time.Duration {
+	timeUntil := sim.GetRemainingDuration()
+	for _, event := range encounter.DowntimeEvents {
+		if start := event.nextOccurrence(sim.CurrentTime); start != NeverExpires {
+			timeUntil = min(timeUntil, max(0, start-sim.CurrentTime))
+		}
+	}
+	return timeUntil
+}
+
+// Returns how long the current downtime lasts, or 0 outside of downtime.
+func (encounter *Encounter) DowntimeRemaining(sim *Simulation) time.Duration {
+	remaining := time.Duration(0)
+	for _, event := range encounter.DowntimeEvents {
+		if start := event.nextOccurrence(sim.CurrentTime); start <= sim.CurrentTime {
+			remaining = max(remaining, start+event.Duration-sim.CurrentTime)
+		}
+	}
+	return remaining
+}
+
+func (env *Environment) scheduleDowntimeEvents(sim *Simulation) {
+	for _, event := range env.Encounter.DowntimeEvents {
+		env.scheduleDowntime(sim, event, event.Start)
+	}
+}
+
+func (env *Environment) scheduleDowntime(sim *Simulation, event *DowntimeEvent, startAt time.Duration) {
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: startAt,
+		OnAction: func(sim *Simulation) {
+			env.startDowntime(sim, event)
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt: sim.CurrentTime + event.Duration,
+				OnAction: func(sim *Simulation) {
+					env.endDowntime(sim, event)
+				},
+			})
+			if event.Interval > 0 {
+				env.scheduleDowntime(sim, event, startAt+event.Interval)
+			}
+		},
+	})
+}
+
+func (env *Environment) startDowntime(sim *Simulation, event *DowntimeEvent) {
+	if sim.Log != nil {
+		sim.Log("[Encounter] Downtime started: %s for %s", event.Type, event.Duration)
+	}
+
+	for _, unit := range env.Raid.AllPlayerUnits {
+		switch event.Type {
+		case proto.DowntimeEvent_TypeUntargetable:
+			if unit.CurrentTarget != event.Target {
+				continue
+			}
+			if unit.IsCasting(sim) && unit.Hardcast.Target == event.Target {
+				unit.InterruptCast(sim)
+			}
+			if unit.IsChanneling(sim) && unit.ChanneledDot.Unit == event.Target {
+				unit.InterruptCast(sim)
+			}
+			unit.AutoAttacks.CancelAutoSwing(sim)
+		case proto.DowntimeEvent_TypeMovement:
+			unit.MovementHandler.forced++
+			if unit.IsCasting(sim) {
+				unit.InterruptCast(sim)
+			}
+			unit.MovementHandler.moveAura.Activate(sim)
+		case proto.DowntimeEvent_TypeSilence:
+			unit.silenced++
+			unit.InterruptCast(sim)
+		}
+	}
+
+	if event.Type == proto.DowntimeEvent_TypeUntargetable {
+		event.Target.untargetable++
+	}
+}
+
+func (env *Environment) endDowntime(sim *Simulation, event *DowntimeEvent) {
+	if sim.Log != nil {
+		sim.Log("[Encounter] Downtime ended: %s", event.Type)
+	}
+
+	if event.Type == proto.DowntimeEvent_TypeUntargetable {
+		event.Target.untargetable--
+	}
+
+	for _, unit := range env.Raid.AllPlayerUnits {
+		switch event.Type {
+		case proto.DowntimeEvent_TypeUntargetable:
+			if unit.CurrentTarget != event.Target {
+				continue
+			}
+			if !unit.IsMoving() {
+				unit.AutoAttacks.EnableAutoSwing(sim)
+			}
+		case proto.DowntimeEvent_TypeMovement:
+			unit.MovementHandler.forced--
+			if unit.MovementHandler.forced == 0 && unit.MovementHandler.moveAction == nil {
+				unit.MovementHandler.moveAura.Deactivate(sim)
+			}
+		case proto.DowntimeEvent_TypeSilence:
+			unit.silenced--
+		}
+		unit.resumeRotation(sim)
+	}
+}
+
+// Stops the unit's current cast or channel. Hardcast costs are only paid when
+// the cast completes, so nothing is spent.
+func (unit *Unit) InterruptCast(sim *Simulation) {
+	if unit.IsCasting(sim) {
+		if sim.Log != nil {
+			unit.Log(sim, "Cast of %s interrupted", unit.Hardcast.ActionID)
+		}
+		// Cooldowns are started when the cast begins, but an interrupted cast
+		// never happened.
+		if spell := unit.Hardcast.Spell; spell != nil {
+			if spell.CD.Timer != nil {
+				spell.CD.Reset()
+			}
+			if spell.SharedCD.Timer != nil {
+				spell.SharedCD.Reset()
+			}
+		}
+		unit.Hardcast = Hardcast{Expires: startingCDTime}
+		if unit.hardcastAction != nil {
+			unit.hardcastAction.Cancel(sim)
+			unit.hardcastAction = nil
+		}
+	}
+	if unit.IsChanneling(sim) {
+		unit.ChanneledDot.Cancel(sim)
+	}
+}
+
+// Whether the unit is prevented from casting spells, e.g. by a silence.
+func (unit *Unit) IsSilenced() bool {
+	return unit.silenced > 0
+}
+
+// Whether the unit can't be attacked or targeted by spells.
+func (unit *Unit) IsUntargetable() bool {
+	return unit.untargetable > 0
+}
+
+// Makes the rotation pick its next action right away, as nothing else might
+// wake it up after it had nothing to do.
+func (unit *Unit) resumeRotation(sim *Simulation) {
+	if unit.gcdAction == nil || unit.IsCasting(sim) || unit.IsChanneling(sim) {
+		return
+	}
+	unit.SetGCDTimer(sim, max(sim.CurrentTime, unit.GCD.ReadyAt()))
+}
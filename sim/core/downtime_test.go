@@ -0,0 +1,166 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+func TestDowntimeEvents(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 30,
+			DowntimeEvents: []*proto.DowntimeEvent{
+				{Type: proto.DowntimeEvent_TypeSilence, Start: 2, Duration: 3, Interval: 10},
+				{Type: proto.DowntimeEvent_TypeUntargetable, Start: 21, Duration: 4},
+			},
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	spell := sim.Raid.Parties[0].Players[0].(*FakeAgent).Spell
+	expect := func(at time.Duration, canCast bool, timeUntil time.Duration, remaining time.Duration) {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: at,
+			OnAction: func(sim *Simulation) {
+				if spell.CanCast(sim, nil) != canCast {
+					t.Errorf("At %s: expected CanCast to be %t", at, canCast)
+				}
+				if actual := sim.Encounter.TimeUntilNextDowntime(sim); actual != timeUntil {
+					t.Errorf("At %s: expected %s until the next downtime, got %s", at, timeUntil, actual)
+				}
+				if actual := sim.Encounter.DowntimeRemaining(sim); actual != remaining {
+					t.Errorf("At %s: expected %s of downtime remaining, got %s", at, remaining, actual)
+				}
+			},
+		})
+	}
+
+	expect(time.Second*1, true, time.Second*1, 0)
+	expect(time.Second*3, false, 0, time.Second*2)
+	expect(time.Second*6, true, time.Second*6, 0)
+	expect(time.Second*13, false, 0, time.Second*2)
+	expect(time.Millisecond*20500, true, time.Millisecond*500, 0)
+	expect(time.Second*23, false, 0, time.Second*2)
+	expect(time.Second*26, true, time.Second*4, 0)
+
+	for sim.CurrentTime < time.Second*27 && !sim.Step() {
+	}
+}
+
+func TestDowntimeSpellRestrictions(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 30,
+			DowntimeEvents: []*proto.DowntimeEvent{
+				{Type: proto.DowntimeEvent_TypeSilence, Start: 2, Duration: 3},
+				{Type: proto.DowntimeEvent_TypeUntargetable, Start: 10, Duration: 3},
+			},
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+
+	character := sim.Raid.Parties[0].Players[0].GetCharacter()
+	register := func(config SpellConfig) *Spell {
+		config.ApplyEffects = func(_ *Simulation, _ *Unit, _ *Spell) {}
+		spell := character.RegisterSpell(config)
+		spell.finalize()
+		return spell
+	}
+	nuke := sim.Raid.Parties[0].Players[0].(*FakeAgent).Spell
+	strike := register(SpellConfig{
+		ActionID:    ActionID{SpellID: 43},
+		SpellSchool: SpellSchoolPhysical,
+		ProcMask:    ProcMaskMeleeMHSpecial,
+	})
+	selfBuff := register(SpellConfig{
+		ActionID:    ActionID{SpellID: 44},
+		SpellSchool: SpellSchoolArcane,
+	})
+	slowCast := register(SpellConfig{
+		ActionID:    ActionID{SpellID: 45},
+		SpellSchool: SpellSchoolFire,
+		ProcMask:    ProcMaskSpellDamage,
+		Cast: CastConfig{
+			DefaultCast: Cast{CastTime: time.Second * 2},
+			CD: Cooldown{
+				Timer:    character.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+	})
+	sim.reset()
+
+	expect := func(at time.Duration, spell *Spell, canCast bool) {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: at,
+			OnAction: func(sim *Simulation) {
+				if spell.CanCast(sim, nil) != canCast {
+					t.Errorf("At %s: expected CanCast of %s to be %t", at, spell.ActionID, canCast)
+				}
+			},
+		})
+	}
+
+	// Silences only stop non-physical spells.
+	expect(time.Second*3, nuke, false)
+	expect(time.Second*3, strike, true)
+
+	// Untargetable enemies can't be attacked, but self-cast spells still work.
+	expect(time.Second*11, nuke, false)
+	expect(time.Second*11, strike, false)
+	expect(time.Second*11, selfBuff, true)
+
+	// The cooldown of an interrupted cast doesn't start.
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Second * 1,
+		OnAction: func(sim *Simulation) {
+			slowCast.Cast(sim, character.CurrentTarget)
+		},
+	})
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Millisecond * 2500,
+		OnAction: func(sim *Simulation) {
+			if character.IsCasting(sim) {
+				t.Errorf("Expected the cast to be interrupted by the silence")
+			}
+			if !slowCast.CD.IsReady(sim) {
+				t.Errorf("Expected the cooldown of the interrupted cast to be ready, but it is ready in %s", slowCast.CD.TimeToReady(sim))
+			}
+		},
+	})
+
+	for sim.CurrentTime < time.Second*12 && !sim.Step() {
+	}
+}
@@ -147,7 +147,7 @@ func (eb *energyBar) onEnergyGain(sim *Simulation, crossedThreshold bool) {
 		return
 	}
 
-	if !sim.Options.Interactive && crossedThreshold {
+	if !sim.isInteractive(eb.unit) && crossedThreshold {
 		eb.unit.Rotation.DoNextAction(sim)
 	}
 }
@@ -163,6 +163,9 @@ func (eb *energyBar) addEnergyInternal(sim *Simulation, amount float64, metrics
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, eb.currentEnergy, newEnergy)
 	}
+	if sim.CombatLog != nil {
+		eb.unit.logEnergize(sim, amount, newEnergy-eb.currentEnergy, metrics)
+	}
 
 	crossedThreshold := eb.cumulativeEnergyDecisionThresholds == nil || eb.cumulativeEnergyDecisionThresholds[int(eb.currentEnergy)] != eb.cumulativeEnergyDecisionThresholds[int(newEnergy)]
 	eb.currentEnergy = newEnergy
@@ -109,6 +109,8 @@ func (env *Environment) construct(raidProto *proto.Raid, encounterProto *proto.E
 		}
 	}
 
+	env.initPositions()
+
 	env.State = Constructed
 }
 
@@ -264,8 +266,12 @@ func (env *Environment) reset(sim *Simulation) {
 	for _, target := range env.Encounter.Targets {
 		target.Reset(sim)
 	}
+	env.Encounter.updateActiveTargets()
 
 	env.Raid.reset(sim)
+
+	env.scheduleMovementEvents(sim)
+	env.scheduleDowntimeEvents(sim)
 }
 
 // The maximum possible duration for any iteration.
@@ -277,6 +283,12 @@ func (env *Environment) GetNumTargets() int32 {
 	return int32(len(env.Encounter.Targets))
 }
 
+// The number of targets currently in the fight. Unlike GetNumTargets, this
+// changes during an iteration as targets spawn, despawn and die.
+func (env *Environment) GetNumActiveTargets() int32 {
+	return int32(len(env.Encounter.ActiveTargetUnits))
+}
+
 func (env *Environment) GetTarget(index int32) *Target {
 	return env.Encounter.Targets[index]
 }
@@ -79,6 +79,9 @@ func (fb *focusBar) AddFocus(sim *Simulation, amount float64, metrics *ResourceM
 	if sim.Log != nil {
 		fb.unit.Log(sim, "Gained %0.3f focus from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, fb.currentFocus, newFocus)
 	}
+	if sim.CombatLog != nil {
+		fb.unit.logEnergize(sim, amount, newFocus-fb.currentFocus, metrics)
+	}
 
 	fb.currentFocus = newFocus
 
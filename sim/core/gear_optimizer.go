@@ -0,0 +1,629 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goproto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+const (
+	defaultOptimizerCandidatesPerSlot = 4
+	defaultOptimizerIterations        = 1000
+	defaultOptimizerMaxSteps          = 10
+
+	// Fewest iterations used for the first round of successive halving.
+	minOptimizerIterations = 50
+)
+
+// gearOptimizer searches a pool of candidate items for the best set of gear.
+//
+// Candidates are first pre-filtered per slot by their stat weight value, so
+// only the most promising ones are ever simmed. Set pieces are always kept,
+// since stat weights can't see set bonuses. Then a local search starts from
+// the best set by stat weights, and at each step sims every set that differs
+// by one slot, one rune or one item set, picking the best with successive
+// halving. The search stops once no neighbor beats the current set.
+type gearOptimizer struct {
+	request  *proto.GearOptimizerRequest
+	settings *proto.GearOptimizerSettings
+	signals  simsignals.Signals
+	progress chan *proto.ProgressMetrics
+
+	baseItems []*proto.ItemSpec
+	weights   stats.Stats
+	slots     [proto.ItemSlot_ItemSlotRanged + 1]*optimizerSlot
+
+	simsRun   int32
+	simsTotal int32
+}
+
+// optimizerSlot holds the candidates remaining for one slot after pre-filtering.
+type optimizerSlot struct {
+	candidates []*optimizerCandidate
+	runes      []int32
+}
+
+type optimizerCandidate struct {
+	Spec  *proto.ItemSpec
+	Item  Item
+	Score float64
+}
+
+// optimizerGear is one set of gear, with the rune engraved in each slot kept
+// separately from the items so swapping an item keeps the slot's rune.
+type optimizerGear struct {
+	items [proto.ItemSlot_ItemSlotRanged + 1]*optimizerCandidate
+	runes [proto.ItemSlot_ItemSlotRanged + 1]int32
+}
+
+type optimizerSimResult struct {
+	gear   *optimizerGear
+	result *proto.RaidSimResult
+	score  float64
+}
+
+func OptimizeGear(signals simsignals.Signals, request *proto.GearOptimizerRequest, progress chan *proto.ProgressMetrics) *proto.GearOptimizerResult {
+	optimizer := &gearOptimizer{
+		request:  request,
+		settings: request.Settings,
+		signals:  signals,
+		progress: progress,
+	}
+	if optimizer.settings == nil {
+		optimizer.settings = &proto.GearOptimizerSettings{}
+	}
+
+	result := optimizer.Run()
+
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalGearOptimizerResult: result,
+		}
+		close(progress)
+	}
+
+	return result
+}
+
+func (o *gearOptimizer) Run() (result *proto.GearOptimizerResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.GearOptimizerResult{
+				Error: &proto.ErrorOutcome{Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack()))},
+			}
+		}
+	}()
+
+	// Like bulk sims, the optimizer only supports a single player.
+	parties := o.request.GetBaseSettings().GetRaid().GetParties()
+	if len(parties) == 0 || len(parties[0].Players) != 1 || parties[0].Players[0].Name == "" {
+		return &proto.GearOptimizerResult{
+			Error: &proto.ErrorOutcome{Message: "gear optimizer: expected exactly 1 player"},
+		}
+	}
+	o.request.BaseSettings.Raid.Parties = parties[:1]
+	player := parties[0].Players[0]
+	if player.GetDatabase() != nil {
+		addToDatabase(player.GetDatabase())
+	}
+	player.Database = nil
+
+	if player.Equipment == nil {
+		player.Equipment = &proto.EquipmentSpec{}
+	}
+	for len(player.Equipment.Items) < len(o.slots) {
+		player.Equipment.Items = append(player.Equipment.Items, &proto.ItemSpec{})
+	}
+	o.baseItems = player.Equipment.Items
+
+	if o.request.BaseSettings.SimOptions == nil {
+		o.request.BaseSettings.SimOptions = &proto.SimOptions{}
+	}
+	// Every set is simmed with the same seed, so differences come from the gear
+	// rather than from RNG.
+	if o.request.BaseSettings.SimOptions.RandomSeed == 0 {
+		o.request.BaseSettings.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+
+	if errorOutcome := o.buildSlots(); errorOutcome != nil {
+		return &proto.GearOptimizerResult{Error: errorOutcome}
+	}
+	if errorOutcome := o.computeWeights(); errorOutcome != nil {
+		return &proto.GearOptimizerResult{Error: errorOutcome}
+	}
+	o.prefilter()
+
+	equipped := o.equippedGear()
+	current := o.startingGear()
+
+	maxSteps := int(o.settings.MaxSteps)
+	if maxSteps <= 0 {
+		maxSteps = defaultOptimizerMaxSteps
+	}
+	for step := 0; step < maxSteps; step++ {
+		best, errorOutcome := o.successiveHalving(o.neighbors(current))
+		if errorOutcome != nil {
+			return &proto.GearOptimizerResult{Error: errorOutcome}
+		}
+		if best.gear.key() == current.key() {
+			break
+		}
+		current = best.gear
+	}
+
+	// Compare the result against the equipped gear at full accuracy.
+	final, errorOutcome := o.simAll([]*optimizerGear{current, equipped}, o.iterations())
+	if errorOutcome != nil {
+		return &proto.GearOptimizerResult{Error: errorOutcome}
+	}
+	best, equippedResult := final[0], final[1]
+	if equippedResult.score > best.score {
+		best = equippedResult
+	}
+
+	result = &proto.GearOptimizerResult{
+		Equipment:           best.gear.equipmentSpec(),
+		UnitMetrics:         optimizerUnitMetrics(best.result),
+		EquippedGearMetrics: optimizerUnitMetrics(equippedResult.result),
+		SimsRun:             o.simsRun,
+	}
+	for slot, item := range result.Equipment.Items {
+		if !goproto.Equal(item, o.baseItems[slot]) {
+			result.ItemsChanged = append(result.ItemsChanged, &proto.ItemSpecWithSlot{
+				Item: item,
+				Slot: proto.ItemSlot(slot),
+			})
+		}
+	}
+	return result
+}
+
+func (o *gearOptimizer) iterations() int32 {
+	if o.settings.Iterations > 0 {
+		return o.settings.Iterations
+	}
+	return defaultOptimizerIterations
+}
+
+// Expands the candidates of each slot into every item and enchant pairing,
+// always including the equipped item.
+func (o *gearOptimizer) buildSlots() *proto.ErrorOutcome {
+	for slot := range o.slots {
+		o.slots[slot] = &optimizerSlot{}
+		if o.baseItems[slot].Id != 0 {
+			o.slots[slot].candidates = append(o.slots[slot].candidates, o.newCandidate(withoutRune(o.baseItems[slot])))
+		}
+	}
+	// An empty off hand is always allowed, so two-handers can be equipped.
+	o.slots[proto.ItemSlot_ItemSlotOffHand].candidates = append(o.slots[proto.ItemSlot_ItemSlotOffHand].candidates, &optimizerCandidate{Spec: &proto.ItemSpec{}})
+
+	for _, slotSettings := range o.settings.Slots {
+		slot := o.slots[slotSettings.Slot]
+		slot.runes = slotSettings.Runes
+
+		for _, spec := range slotSettings.Items {
+			item, ok := ItemsByID[spec.Id]
+			if !ok {
+				return &proto.ErrorOutcome{Message: fmt.Sprintf("unknown item with id %d in gear optimizer settings", spec.Id)}
+			}
+			if !slices.Contains(eligibleSlotsForItem(item), slotSettings.Slot) {
+				return &proto.ErrorOutcome{Message: fmt.Sprintf("item %s (%d) can't be equipped in slot %s", item.Name, item.ID, slotSettings.Slot)}
+			}
+
+			enchants := slotSettings.Enchants
+			if len(enchants) == 0 {
+				enchants = []int32{spec.Enchant}
+			}
+			for _, enchant := range enchants {
+				candidateSpec := withoutRune(spec)
+				candidateSpec.Enchant = enchant
+				if !slices.ContainsFunc(slot.candidates, func(c *optimizerCandidate) bool { return goproto.Equal(c.Spec, candidateSpec) }) {
+					slot.candidates = append(slot.candidates, o.newCandidate(candidateSpec))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Candidates don't carry runes, since those stay with the slot.
+func withoutRune(spec *proto.ItemSpec) *proto.ItemSpec {
+	spec = goproto.Clone(spec).(*proto.ItemSpec)
+	spec.Rune = 0
+	return spec
+}
+
+func (o *gearOptimizer) newCandidate(spec *proto.ItemSpec) *optimizerCandidate {
+	return &optimizerCandidate{
+		Spec: spec,
+		Item: NewItem(ItemSpec{ID: spec.Id, RandomSuffix: spec.RandomSuffix, Enchant: spec.Enchant}),
+	}
+}
+
+// Uses the weights from the settings, or sims stat weights for the equipped
+// gear for every stat found on the candidates.
+func (o *gearOptimizer) computeWeights() *proto.ErrorOutcome {
+	if o.settings.StatWeights != nil && len(o.settings.StatWeights.Stats) > 0 {
+		o.weights = stats.FromFloatArray(o.settings.StatWeights.Stats)
+	} else {
+		var statsToWeigh []proto.Stat
+		for _, slot := range o.slots {
+			for _, candidate := range slot.candidates {
+				candidateStats := candidate.stats()
+				for stat := range candidateStats {
+					if candidateStats[stat] != 0 && !slices.Contains(statsToWeigh, proto.Stat(stat)) {
+						statsToWeigh = append(statsToWeigh, proto.Stat(stat))
+					}
+				}
+			}
+		}
+		if len(statsToWeigh) == 0 {
+			return nil
+		}
+
+		baseSettings := o.request.BaseSettings
+		simOptions := goproto.Clone(baseSettings.SimOptions).(*proto.SimOptions)
+		simOptions.Iterations = o.iterations()
+		swr := &proto.StatWeightsRequest{
+			Player:          goproto.Clone(baseSettings.Raid.Parties[0].Players[0]).(*proto.Player),
+			RaidBuffs:       baseSettings.Raid.Buffs,
+			PartyBuffs:      baseSettings.Raid.Parties[0].Buffs,
+			Debuffs:         baseSettings.Raid.Debuffs,
+			Encounter:       baseSettings.Encounter,
+			SimOptions:      simOptions,
+			Tanks:           baseSettings.Raid.Tanks,
+			StatsToWeigh:    statsToWeigh,
+			EpReferenceStat: statsToWeigh[0],
+		}
+
+		swResult := runStatWeights(swr, nil, o.signals)
+		if swResult.Error != nil {
+			return swResult.Error
+		}
+		o.weights = stats.FromFloatArray(optimizerMetricStatWeights(swResult, o.settings.Metric).Weights.Stats)
+	}
+
+	// For DTPS and TMI lower is better, so flip the weights to keep higher scores better.
+	if optimizerLowerIsBetter(o.settings.Metric) {
+		o.weights = o.weights.Invert()
+	}
+
+	for _, slot := range o.slots {
+		for _, candidate := range slot.candidates {
+			candidateStats := candidate.stats()
+			for stat := range candidateStats {
+				candidate.Score += candidateStats[stat] * o.weights[stat]
+			}
+		}
+	}
+	return nil
+}
+
+func (candidate *optimizerCandidate) stats() stats.Stats {
+	return candidate.Item.Stats.Add(candidate.Item.Enchant.Stats).Add(candidate.Item.RandomSuffix.Stats)
+}
+
+// Keeps the best candidates of each slot by stat weight value, plus the
+// equipped item, the empty off hand and the best version of each set piece.
+func (o *gearOptimizer) prefilter() {
+	candidatesPerSlot := int(o.settings.CandidatesPerSlot)
+	if candidatesPerSlot <= 0 {
+		candidatesPerSlot = defaultOptimizerCandidatesPerSlot
+	}
+
+	for slotIdx, slot := range o.slots {
+		sort.SliceStable(slot.candidates, func(i, j int) bool {
+			return slot.candidates[i].Score > slot.candidates[j].Score
+		})
+
+		var kept []*optimizerCandidate
+		keptSetPieces := map[int32]bool{}
+		for i, candidate := range slot.candidates {
+			isEquipped := goproto.Equal(candidate.Spec, withoutRune(o.baseItems[slotIdx]))
+			isEmpty := candidate.Spec.Id == 0
+			isNewSetPiece := candidate.Item.SetName != "" && !keptSetPieces[candidate.Spec.Id]
+			if i < candidatesPerSlot || isEquipped || isEmpty || isNewSetPiece {
+				kept = append(kept, candidate)
+				if candidate.Item.SetName != "" {
+					keptSetPieces[candidate.Spec.Id] = true
+				}
+			}
+		}
+		slot.candidates = kept
+	}
+}
+
+func (o *gearOptimizer) equippedGear() *optimizerGear {
+	gear := &optimizerGear{}
+	for slot, spec := range o.baseItems {
+		gear.runes[slot] = spec.Rune
+		for _, candidate := range o.slots[slot].candidates {
+			if goproto.Equal(candidate.Spec, withoutRune(spec)) {
+				gear.items[slot] = candidate
+				break
+			}
+		}
+		if gear.items[slot] == nil {
+			gear.items[slot] = &optimizerCandidate{Spec: &proto.ItemSpec{}}
+		}
+	}
+	return gear
+}
+
+// The best valid set by stat weights, filling slots in order.
+func (o *gearOptimizer) startingGear() *optimizerGear {
+	gear := o.equippedGear()
+	for slot := range o.slots {
+		for _, candidate := range o.slots[slot].candidates {
+			next := gear.with(proto.ItemSlot(slot), candidate)
+			if next.isValid() {
+				gear = next
+				break
+			}
+		}
+	}
+	return gear
+}
+
+// Returns the given gear followed by every valid set that differs from it by
+// a single item, a single rune, or by equipping the best pieces of an item set.
+func (o *gearOptimizer) neighbors(gear *optimizerGear) []*optimizerGear {
+	seen := map[string]bool{gear.key(): true}
+	neighbors := []*optimizerGear{gear}
+	add := func(next *optimizerGear) {
+		if next.isValid() && !seen[next.key()] {
+			seen[next.key()] = true
+			neighbors = append(neighbors, next)
+		}
+	}
+
+	for slotIdx, slot := range o.slots {
+		for _, candidate := range slot.candidates {
+			next := gear.with(proto.ItemSlot(slotIdx), candidate)
+			if proto.ItemSlot(slotIdx) == proto.ItemSlot_ItemSlotMainHand {
+				next = o.fixOffHand(next)
+			}
+			add(next)
+		}
+		for _, rune := range slot.runes {
+			next := gear.clone()
+			next.runes[slotIdx] = rune
+			add(next)
+		}
+	}
+
+	for _, setName := range o.setNames() {
+		next := gear.clone()
+		for slotIdx, slot := range o.slots {
+			for _, candidate := range slot.candidates {
+				if candidate.Item.SetName != setName {
+					continue
+				}
+				if withPiece := next.with(proto.ItemSlot(slotIdx), candidate); withPiece.isValid() {
+					next = withPiece
+					break
+				}
+			}
+		}
+		add(o.fixOffHand(next))
+	}
+
+	return neighbors
+}
+
+// Empties the off hand when a two-hander is equipped, and refills it with the
+// best valid candidate when a one-hander replaces a two-hander.
+func (o *gearOptimizer) fixOffHand(gear *optimizerGear) *optimizerGear {
+	mainHand := gear.items[proto.ItemSlot_ItemSlotMainHand]
+	offHand := gear.items[proto.ItemSlot_ItemSlotOffHand]
+	empty := &optimizerCandidate{Spec: &proto.ItemSpec{}}
+
+	if mainHand.Item.HandType == proto.HandType_HandTypeTwoHand {
+		if offHand.Spec.Id != 0 {
+			return gear.with(proto.ItemSlot_ItemSlotOffHand, empty)
+		}
+		return gear
+	}
+	if offHand.Spec.Id == 0 {
+		for _, candidate := range o.slots[proto.ItemSlot_ItemSlotOffHand].candidates {
+			if next := gear.with(proto.ItemSlot_ItemSlotOffHand, candidate); candidate.Spec.Id != 0 && next.isValid() {
+				return next
+			}
+		}
+	}
+	return gear
+}
+
+func (o *gearOptimizer) setNames() []string {
+	var names []string
+	for _, slot := range o.slots {
+		for _, candidate := range slot.candidates {
+			if candidate.Item.SetName != "" && !slices.Contains(names, candidate.Item.SetName) {
+				names = append(names, candidate.Item.SetName)
+			}
+		}
+	}
+	return names
+}
+
+// Sims every set with a few iterations, then repeatedly drops the worse half
+// and doubles the iterations, until one set is left or the full iteration
+// count is reached.
+func (o *gearOptimizer) successiveHalving(gears []*optimizerGear) (*optimizerSimResult, *proto.ErrorOutcome) {
+	iterations := o.iterations()
+	roundIterations := iterations
+	for numRounds := len(gears); numRounds > 2; numRounds /= 2 {
+		roundIterations /= 2
+	}
+	roundIterations = min(max(roundIterations, minOptimizerIterations), iterations)
+
+	for {
+		results, errorOutcome := o.simAll(gears, roundIterations)
+		if errorOutcome != nil {
+			return nil, errorOutcome
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].score > results[j].score
+		})
+
+		if len(results) == 1 || roundIterations >= iterations {
+			return results[0], nil
+		}
+
+		results = results[:(len(results)+1)/2]
+		gears = make([]*optimizerGear, len(results))
+		for i, result := range results {
+			gears[i] = result.gear
+		}
+		roundIterations = min(roundIterations*2, iterations)
+	}
+}
+
+// Sims each set concurrently, returning results in the same order.
+func (o *gearOptimizer) simAll(gears []*optimizerGear, iterations int32) ([]*optimizerSimResult, *proto.ErrorOutcome) {
+	results := make([]*optimizerSimResult, len(gears))
+	o.simsTotal += int32(len(gears))
+
+	concurrency := runtime.NumCPU()
+	if IsRunningInWasm() || o.request.BaseSettings.SimOptions.IsTest {
+		concurrency = 1
+	}
+	tickets := make(chan struct{}, concurrency)
+
+	var mut sync.Mutex
+	var wg sync.WaitGroup
+	for i, gear := range gears {
+		tickets <- struct{}{}
+		wg.Add(1)
+		go func(i int, gear *optimizerGear) {
+			defer func() {
+				<-tickets
+				wg.Done()
+			}()
+
+			request := goproto.Clone(o.request.BaseSettings).(*proto.RaidSimRequest)
+			request.Raid.Parties[0].Players[0].Equipment = gear.equipmentSpec()
+			request.SimOptions.Iterations = iterations
+			result := runSim(request, nil, false, o.signals)
+
+			results[i] = &optimizerSimResult{gear: gear, result: result}
+			if result.Error == nil {
+				results[i].score = optimizerScore(result, o.settings.Metric)
+			}
+
+			mut.Lock()
+			o.simsRun++
+			if o.progress != nil {
+				o.progress <- &proto.ProgressMetrics{
+					CompletedSims: o.simsRun,
+					TotalSims:     o.simsTotal,
+				}
+			}
+			mut.Unlock()
+		}(i, gear)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result.result.Error != nil {
+			return nil, result.result.Error
+		}
+	}
+	return results, nil
+}
+
+func optimizerLowerIsBetter(metric proto.StatWeightsMetric) bool {
+	return metric == proto.StatWeightsMetric_StatWeightsMetricDtps || metric == proto.StatWeightsMetric_StatWeightsMetricTmi
+}
+
+// The value being optimized for, where higher is always better.
+func optimizerScore(result *proto.RaidSimResult, metric proto.StatWeightsMetric) float64 {
+	player := result.RaidMetrics.Parties[0].Players[0]
+	switch metric {
+	case proto.StatWeightsMetric_StatWeightsMetricHps:
+		return player.Hps.Avg
+	case proto.StatWeightsMetric_StatWeightsMetricTps:
+		return player.Threat.Avg
+	case proto.StatWeightsMetric_StatWeightsMetricDtps:
+		return -player.Dtps.Avg
+	case proto.StatWeightsMetric_StatWeightsMetricTmi:
+		return -player.Tmi.Avg
+	default:
+		return player.Dps.Avg
+	}
+}
+
+func optimizerMetricStatWeights(result *proto.StatWeightsResult, metric proto.StatWeightsMetric) *proto.StatWeightValues {
+	switch metric {
+	case proto.StatWeightsMetric_StatWeightsMetricHps:
+		return result.Hps
+	case proto.StatWeightsMetric_StatWeightsMetricTps:
+		return result.Tps
+	case proto.StatWeightsMetric_StatWeightsMetricDtps:
+		return result.Dtps
+	case proto.StatWeightsMetric_StatWeightsMetricTmi:
+		return result.Tmi
+	default:
+		return result.Dps
+	}
+}
+
+func optimizerUnitMetrics(result *proto.RaidSimResult) *proto.UnitMetrics {
+	um := result.GetRaidMetrics().GetParties()[0].GetPlayers()[0]
+	um.Actions = nil
+	um.Auras = nil
+	um.Resources = nil
+	um.Pets = nil
+	return um
+}
+
+func (gear *optimizerGear) clone() *optimizerGear {
+	newGear := *gear
+	return &newGear
+}
+
+func (gear *optimizerGear) with(slot proto.ItemSlot, candidate *optimizerCandidate) *optimizerGear {
+	newGear := gear.clone()
+	newGear.items[slot] = candidate
+	return newGear
+}
+
+func (gear *optimizerGear) equipmentSpec() *proto.EquipmentSpec {
+	equipment := &proto.EquipmentSpec{}
+	for slot, candidate := range gear.items {
+		spec := goproto.Clone(candidate.Spec).(*proto.ItemSpec)
+		if spec.Id != 0 {
+			spec.Rune = gear.runes[slot]
+		}
+		equipment.Items = append(equipment.Items, spec)
+	}
+	return equipment
+}
+
+func (gear *optimizerGear) key() string {
+	parts := make([]string, len(gear.items))
+	for slot, candidate := range gear.items {
+		parts[slot] = fmt.Sprintf("%d/%d/%d/%d", candidate.Spec.Id, candidate.Spec.RandomSuffix, candidate.Spec.Enchant, gear.runes[slot])
+	}
+	return strings.Join(parts, ":")
+}
+
+// Rejects two-handers with an off hand, and the same ring or trinket twice,
+// which is how unique-equipped items are handled in bulk sims as well.
+func (gear *optimizerGear) isValid() bool {
+	if gear.items[proto.ItemSlot_ItemSlotMainHand].Item.HandType == proto.HandType_HandTypeTwoHand && gear.items[proto.ItemSlot_ItemSlotOffHand].Spec.Id != 0 {
+		return false
+	}
+	return isValidEquipment(gear.equipmentSpec())
+}
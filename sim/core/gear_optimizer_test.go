@@ -0,0 +1,134 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+const (
+	itemOptimizerRing        = 990001
+	itemOptimizerRingCopy    = 990002
+	itemOptimizerWeakRing    = 990003
+	itemOptimizerOneHander   = 990004
+	itemOptimizerOffHand     = 990005
+	itemOptimizerTwoHander   = 990006
+	itemOptimizerSetHead     = 990007
+	itemOptimizerSetChest    = 990008
+	itemOptimizerStrongChest = 990009
+	itemOptimizerGoodChest   = 990010
+)
+
+func optimizerTestItemStats(strength float64) []float64 {
+	itemStats := stats.Stats{}
+	itemStats[stats.Strength] = strength
+	return itemStats[:]
+}
+
+var optimizerItemDatabase = &proto.SimDatabase{
+	Items: []*proto.SimItem{
+		{Id: itemOptimizerRing, Name: "Ring", Type: proto.ItemType_ItemTypeFinger, Stats: optimizerTestItemStats(10)},
+		{Id: itemOptimizerRingCopy, Name: "Ring", Type: proto.ItemType_ItemTypeFinger, Stats: optimizerTestItemStats(9)},
+		{Id: itemOptimizerWeakRing, Name: "Weak Ring", Type: proto.ItemType_ItemTypeFinger, Stats: optimizerTestItemStats(1)},
+		{Id: itemOptimizerOneHander, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOneHand, Stats: optimizerTestItemStats(5)},
+		{Id: itemOptimizerOffHand, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOffHand, Stats: optimizerTestItemStats(5)},
+		{Id: itemOptimizerTwoHander, Type: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeTwoHand, Stats: optimizerTestItemStats(8)},
+		{Id: itemOptimizerSetHead, Type: proto.ItemType_ItemTypeHead, SetName: "Optimizer Set", Stats: optimizerTestItemStats(1)},
+		{Id: itemOptimizerSetChest, Type: proto.ItemType_ItemTypeChest, SetName: "Optimizer Set", Stats: optimizerTestItemStats(1)},
+		{Id: itemOptimizerStrongChest, Type: proto.ItemType_ItemTypeChest, Stats: optimizerTestItemStats(20)},
+		{Id: itemOptimizerGoodChest, Type: proto.ItemType_ItemTypeChest, Stats: optimizerTestItemStats(15)},
+	},
+}
+
+func newTestGearOptimizer(t *testing.T) *gearOptimizer {
+	addToDatabase(optimizerItemDatabase)
+
+	weights := stats.Stats{}
+	weights[stats.Strength] = 1
+
+	equipment := &proto.EquipmentSpec{}
+	for i := 0; i <= int(proto.ItemSlot_ItemSlotRanged); i++ {
+		equipment.Items = append(equipment.Items, &proto.ItemSpec{})
+	}
+	equipment.Items[proto.ItemSlot_ItemSlotMainHand] = &proto.ItemSpec{Id: itemOptimizerTwoHander, Rune: 123}
+
+	o := &gearOptimizer{
+		settings: &proto.GearOptimizerSettings{
+			Slots: []*proto.GearOptimizerSlot{
+				{Slot: proto.ItemSlot_ItemSlotHead, Items: []*proto.ItemSpec{{Id: itemOptimizerSetHead}}},
+				{Slot: proto.ItemSlot_ItemSlotChest, Items: []*proto.ItemSpec{{Id: itemOptimizerSetChest}, {Id: itemOptimizerStrongChest}, {Id: itemOptimizerGoodChest}}},
+				{Slot: proto.ItemSlot_ItemSlotFinger1, Items: []*proto.ItemSpec{{Id: itemOptimizerRing}, {Id: itemOptimizerWeakRing}}},
+				{Slot: proto.ItemSlot_ItemSlotFinger2, Items: []*proto.ItemSpec{{Id: itemOptimizerRingCopy}, {Id: itemOptimizerWeakRing}}},
+				{Slot: proto.ItemSlot_ItemSlotMainHand, Items: []*proto.ItemSpec{{Id: itemOptimizerOneHander}}},
+				{Slot: proto.ItemSlot_ItemSlotOffHand, Items: []*proto.ItemSpec{{Id: itemOptimizerOffHand}}},
+			},
+			StatWeights:       &proto.UnitStats{Stats: weights[:]},
+			CandidatesPerSlot: 2,
+		},
+		baseItems: equipment.Items,
+	}
+
+	if errorOutcome := o.buildSlots(); errorOutcome != nil {
+		t.Fatalf("Failed to build slots: %s", errorOutcome.Message)
+	}
+	if errorOutcome := o.computeWeights(); errorOutcome != nil {
+		t.Fatalf("Failed to compute weights: %s", errorOutcome.Message)
+	}
+	o.prefilter()
+	return o
+}
+
+func TestGearOptimizerStartingGear(t *testing.T) {
+	o := newTestGearOptimizer(t)
+	gear := o.startingGear()
+
+	// Both rings share a name, so only one of them may be equipped.
+	if id := gear.items[proto.ItemSlot_ItemSlotFinger1].Spec.Id; id != itemOptimizerRing {
+		t.Fatalf("Expected the best ring in finger 1, got %d", id)
+	}
+	if id := gear.items[proto.ItemSlot_ItemSlotFinger2].Spec.Id; id != itemOptimizerWeakRing {
+		t.Fatalf("Expected the weak ring in finger 2, got %d", id)
+	}
+
+	// The equipped two-hander is worth more than the one-hander alone, and
+	// keeps its rune.
+	if id := gear.items[proto.ItemSlot_ItemSlotMainHand].Spec.Id; id != itemOptimizerTwoHander {
+		t.Fatalf("Expected the two-hander to stay equipped, got %d", id)
+	}
+	if gear.equipmentSpec().Items[proto.ItemSlot_ItemSlotMainHand].Rune != 123 {
+		t.Fatalf("Expected the main hand to keep its rune")
+	}
+	if id := gear.items[proto.ItemSlot_ItemSlotOffHand].Spec.Id; id != 0 {
+		t.Fatalf("Expected an empty off hand with a two-hander, got %d", id)
+	}
+}
+
+func TestGearOptimizerNeighbors(t *testing.T) {
+	o := newTestGearOptimizer(t)
+	gear := o.startingGear()
+
+	foundDualWield := false
+	foundSet := false
+	for _, neighbor := range o.neighbors(gear)[1:] {
+		if !neighbor.isValid() {
+			t.Fatalf("Invalid neighbor %s", neighbor.key())
+		}
+		mainHand := neighbor.items[proto.ItemSlot_ItemSlotMainHand].Spec.Id
+		offHand := neighbor.items[proto.ItemSlot_ItemSlotOffHand].Spec.Id
+		if mainHand == itemOptimizerOneHander && offHand == itemOptimizerOffHand {
+			foundDualWield = true
+		}
+		if neighbor.items[proto.ItemSlot_ItemSlotHead].Spec.Id == itemOptimizerSetHead && neighbor.items[proto.ItemSlot_ItemSlotChest].Spec.Id == itemOptimizerSetChest {
+			foundSet = true
+		}
+	}
+
+	if !foundDualWield {
+		t.Fatalf("Swapping the two-hander for a one-hander should also equip an off hand")
+	}
+	// Set pieces are kept through pre-filtering even though they score poorly.
+	if !foundSet {
+		t.Fatalf("Expected a neighbor equipping the whole item set")
+	}
+}
@@ -91,20 +91,26 @@ var ChanceOfDeathAuraLabel = "Chance of Death"
 
 func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel) {
 	character.Unit.Metrics.isTanking = false
+	targetsAttack := false
 	for _, target := range character.Env.Encounter.TargetUnits {
 		if target.CurrentTarget == &character.Unit {
 			character.Unit.Metrics.isTanking = true
 		}
-	}
-	if !character.Unit.Metrics.isTanking {
-		return
+		if target.CurrentTarget != nil {
+			targetsAttack = true
+		}
 	}
 
-	if healingModel == nil {
+	if character.Unit.Metrics.isTanking {
+		if healingModel == nil {
+			return
+		}
+		character.Unit.Metrics.tmiBin = healingModel.BurstWindow
+	} else if !targetsAttack {
 		return
 	}
-
-	character.Unit.Metrics.tmiBin = healingModel.BurstWindow
+	// Players who aren't tanking can still pull aggro, so their health is
+	// tracked whenever a target attacks someone.
 
 	character.RegisterAura(Aura{
 		Label:    ChanceOfDeathAuraLabel,
@@ -138,7 +144,7 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 		},
 	})
 
-	if healingModel.Hps != 0 {
+	if healingModel != nil && healingModel.Hps != 0 {
 		character.applyHealingModel(healingModel)
 	}
 }
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+// An input from an external agent, e.g. casting a spell when the sim needs input.
+type SimInput func(sim *Simulation) bool
+
+// A single iteration driven one pending action at a time by an external agent, see sim/lib.
+type InteractiveSim struct {
+	*Simulation
+
+	playerIndex int32
+}
+
+// The state of an interactive sim at some point of its iteration, which can be restored any
+// number of times, but only into the sim it was taken from. See stateCapture for what's captured.
+type SimSnapshot struct {
+	sim   *Simulation
+	state *stateCapture
+}
+
+// Only the player at playerIndex, counting players of all parties in order, waits for input. Other
+// players follow their rotations. If playerIndex is negative, every player waits for input.
+func NewInteractiveSim(request *proto.RaidSimRequest, seed int64, playerIndex int32) (*InteractiveSim, error) {
+	isim := &InteractiveSim{
+		Simulation:  NewSim(googleProto.Clone(request).(*proto.RaidSimRequest), simsignals.Signals{}),
+		playerIndex: playerIndex,
+	}
+	if playerIndex >= 0 {
+		player := isim.Player()
+		if player == nil {
+			return nil, fmt.Errorf("no player with index %d", playerIndex)
+		}
+		isim.interactiveUnit = &player.GetCharacter().Unit
+	}
+	isim.Reseed(seed)
+	isim.Reset()
+	isim.PrePull()
+	return isim, nil
+}
+
+// Returns the player waiting for input, or the first player if every player does.
+func (isim *InteractiveSim) Player() Agent {
+	index := max(isim.playerIndex, 0)
+	for _, party := range isim.Raid.Parties {
+		if int(index) < len(party.Players) {
+			return party.Players[index]
+		}
+		index -= int32(len(party.Players))
+	}
+	return nil
+}
+
+// Runs the next pending action, returning true once the iteration is finished.
+func (isim *InteractiveSim) Step() bool {
+	return isim.Simulation.Step()
+}
+
+func (isim *InteractiveSim) ApplyInput(input SimInput) bool {
+	return input(isim.Simulation)
+}
+
+func (isim *InteractiveSim) Snapshot() *SimSnapshot {
+	return &SimSnapshot{
+		sim:   isim.Simulation,
+		state: captureState(isim.Simulation),
+	}
+}
+
+// Puts the sim back into the state it was in when the snapshot was taken, metrics included. The
+// sim's objects are restored in place, so pointers to units, spells and auras stay valid.
+//
+// Returns an error if the snapshot is from another sim.
+func (isim *InteractiveSim) Restore(snapshot *SimSnapshot) error {
+	if snapshot.sim != isim.Simulation {
+		return fmt.Errorf("the snapshot was taken from a different sim")
+	}
+	snapshot.state.restore()
+	return nil
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestInteractiveSimRestoreSnapshot(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:           []*proto.Target{{Name: "target", Level: 63}},
+			Duration:          60,
+			DurationVariation: 10,
+		},
+		SimOptions: &proto.SimOptions{
+			IsTest: true,
+		},
+	}
+
+	isim, err := NewInteractiveSim(request, 1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		isim.Step()
+	}
+	isim.ApplyInput(func(sim *Simulation) bool {
+		sim.NeedsInput = true
+		return true
+	})
+	isim.Step()
+
+	snapshot := isim.Snapshot()
+	snapshotTime := isim.CurrentTime
+
+	stepsToEnd := func() int {
+		steps := 0
+		for !isim.Step() {
+			steps++
+		}
+		return steps
+	}
+	expectedSteps := stepsToEnd()
+	expectedDuration := isim.Duration
+
+	for i := 0; i < 2; i++ {
+		if err := isim.Restore(snapshot); err != nil {
+			t.Fatal(err)
+		}
+		if isim.CurrentTime != snapshotTime || !isim.NeedsInput {
+			t.Fatalf("Expected the restored sim to be at %s with input needed, got %s", snapshotTime, isim.CurrentTime)
+		}
+		if isim.Duration != expectedDuration {
+			t.Fatalf("Expected the restored sim to keep its rolled duration of %s, got %s", expectedDuration, isim.Duration)
+		}
+		if steps := stepsToEnd(); steps != expectedSteps {
+			t.Fatalf("Expected %d steps after restoring, got %d", expectedSteps, steps)
+		}
+	}
+}
+
+func TestInteractiveSimRestoreErrors(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{
+			IsTest: true,
+		},
+	}
+
+	isim, err := NewInteractiveSim(request, 1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewInteractiveSim(request, 1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Restore(isim.Snapshot()); err == nil {
+		t.Fatalf("Expected an error when restoring a snapshot from another sim")
+	}
+
+}
+
+func TestInteractiveSimRestoresStateInPlace(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Level:     60,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Debuffs: &proto.Debuffs{FaerieFire: true},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{},
+	}
+
+	isim, err := NewInteractiveSim(request, 1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := isim.Simulation
+	target := sim.GetTargetUnit(0)
+	faerieFire := target.GetAura("Faerie Fire")
+	fa := sim.Raid.Parties[0].Players[0].(*FakeAgent)
+
+	snapshot := isim.Snapshot()
+	numPendingActions := len(sim.pendingActions)
+	rolls := []float64{sim.RandomFloat("test"), sim.RandomFloat("test")}
+
+	faerieFire.Deactivate(sim)
+	fa.Spell.CalcAndDealDamage(sim, target, 100, fa.Spell.OutcomeAlwaysHit)
+	StartDelayedAction(sim, DelayedActionOptions{DoAt: time.Second, OnAction: func(_ *Simulation) {}})
+
+	if err := isim.Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if isim.Simulation != sim || target.GetAura("Faerie Fire") != faerieFire {
+		t.Fatalf("Expected the sim and its auras to be restored in place")
+	}
+	if !faerieFire.IsActive() {
+		t.Errorf("Expected Faerie Fire to be active again after restoring")
+	}
+	if damage := fa.Spell.SpellMetrics[target.UnitIndex].TotalDamage; damage != 0 {
+		t.Errorf("Expected the damage dealt after the snapshot to be undone, got %0.3f", damage)
+	}
+	if len(sim.pendingActions) != numPendingActions {
+		t.Errorf("Expected actions added after the snapshot to be dropped")
+	}
+	for i, expected := range rolls {
+		if roll := sim.RandomFloat("test"); roll != expected {
+			t.Errorf("Expected roll %d to be %f after restoring, got %f", i, expected, roll)
+		}
+	}
+}
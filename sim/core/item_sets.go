@@ -69,6 +69,11 @@ func NewItemSet(set ItemSet) *ItemSet {
 	return &set
 }
 
+// Returns all registered item sets.
+func GetItemSets() []*ItemSet {
+	return sets
+}
+
 func (character *Character) HasSetBonus(set *ItemSet, numItems int32) bool {
 	if character.Env != nil && character.Env.IsFinalized() {
 		panic("HasSetBonus is very slow and should never be called after finalization. Try caching the value during construction instead!")
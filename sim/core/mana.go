@@ -27,6 +27,9 @@ type manaBar struct {
 
 	ReplenishmentAura *Aura
 
+	// Not a real spell, just holds metrics from mana gain threat.
+	manaGainSpell *Spell
+
 	// For keeping track of OOM status.
 	waitingForMana          float64
 	waitingForManaStartTime time.Duration
@@ -48,8 +51,7 @@ func (character *Character) EnableManaBarWithModifier(modifier float64) {
 	character.AddStat(stats.Mana, 20-15*20*modifier)
 	character.AddStatDependency(stats.Intellect, stats.Mana, 15*modifier)
 
-	// Not a real spell, just holds metrics from mana gain threat.
-	character.RegisterSpell(SpellConfig{
+	character.manaGainSpell = character.RegisterSpell(SpellConfig{
 		ActionID: ActionID{OtherID: proto.OtherAction_OtherActionManaGain},
 	})
 
@@ -83,10 +85,14 @@ func (unit *Unit) AddMana(sim *Simulation, amount float64, metrics *ResourceMetr
 	oldMana := unit.CurrentMana()
 	newMana := min(oldMana+amount, unit.MaxMana())
 	metrics.AddEvent(amount, newMana-oldMana)
+	unit.manaBar.addManaGainThreat(sim, newMana-oldMana, metrics)
 
 	if sim.Log != nil {
 		unit.Log(sim, "Gained %0.3f mana from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, oldMana, newMana)
 	}
+	if sim.CombatLog != nil {
+		unit.logEnergize(sim, amount, newMana-oldMana, metrics)
+	}
 
 	unit.currentMana = newMana
 	unit.Metrics.ManaGained += newMana - oldMana
@@ -116,27 +122,23 @@ func (mb *manaBar) doneIteration(sim *Simulation) {
 	if mb.waitingForMana != 0 {
 		mb.unit.Metrics.AddOOMTime(sim, sim.CurrentTime-mb.waitingForManaStartTime)
 	}
+}
 
-	manaGainSpell := mb.unit.GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionManaGain})
-
-	for _, resourceMetrics := range mb.unit.Metrics.resources {
-		if resourceMetrics.Type != proto.ResourceType_ResourceTypeMana {
-			continue
-		}
-		if resourceMetrics.ActionID.SameActionIgnoreTag(ActionID{OtherID: proto.OtherAction_OtherActionManaRegen}) {
-			continue
-		}
-		if resourceMetrics.ActionID.SameActionIgnoreTag(ActionID{SpellID: 34917}) {
-			// Vampiric Touch mana threat goes to the priest, so it's handled in the priest code.
-			continue
-		}
-		if resourceMetrics.ActualGainForCurrentIteration() <= 0 {
-			continue
-		}
-
-		manaGainSpell.SpellMetrics[0].Casts += resourceMetrics.EventsForCurrentIteration()
-		manaGainSpell.ApplyAOEThreatIgnoreMultipliers(resourceMetrics.ActualGainForCurrentIteration() * ThreatPerManaGained * mb.unit.PseudoStats.ThreatMultiplier)
+// Mana gained from anything but regen threatens every enemy in the fight.
+func (mb *manaBar) addManaGainThreat(sim *Simulation, actualGain float64, metrics *ResourceMetrics) {
+	if mb.manaGainSpell == nil || actualGain <= 0 {
+		return
+	}
+	if metrics.ActionID.SameActionIgnoreTag(ActionID{OtherID: proto.OtherAction_OtherActionManaRegen}) {
+		return
+	}
+	if metrics.ActionID.SameActionIgnoreTag(ActionID{SpellID: 34917}) {
+		// Vampiric Touch mana threat goes to the priest, so it's handled in the priest code.
+		return
 	}
+
+	mb.manaGainSpell.SpellMetrics[0].Casts++
+	mb.manaGainSpell.ApplyAOEThreatIgnoreMultipliers(sim, actualGain*ThreatPerManaGained*mb.unit.PseudoStats.ThreatMultiplier)
 }
 
 // Returns the rate of mana regen per second from mp5.
@@ -183,6 +183,7 @@ type SpellMetrics struct {
 	TotalHealing                float64 // Healing done by all casts of this spell.
 	TotalCritHealing            float64 // Healing done by all critical casts of this spell.
 	TotalShielding              float64 // Shielding done by all casts of this spell.
+	TotalOverhealing            float64 // Healing done by all casts of this spell which exceeded the target's max health.
 	TotalCastTime               time.Duration
 }
 
@@ -220,6 +221,7 @@ type TargetedActionMetrics struct {
 	Healing                float64
 	CritHealing            float64
 	Shielding              float64
+	Overhealing            float64
 	CastTime               time.Duration
 }
 
@@ -259,6 +261,7 @@ func (tam *TargetedActionMetrics) ToProto(unitIndex int32) *proto.TargetedAction
 		Healing:                tam.Healing,
 		CritHealing:            tam.CritHealing,
 		Shielding:              tam.Shielding,
+		Overhealing:            tam.Overhealing,
 		CastTimeMs:             float64(tam.CastTime.Milliseconds()),
 	}
 }
@@ -419,6 +422,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.CritHealing += spellTargetMetrics.TotalCritHealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
 		if !spell.Flags.Matches(SpellFlagPassiveSpell) {
 			tam.CastTime += spellTargetMetrics.TotalCastTime
 		}
@@ -78,6 +78,8 @@ type MovementHandler struct {
 	baseSpeed          float64
 	moveAura           *Aura
 	moveSpell          *Spell
+	moveAction         *PendingAction
+	forced             int32 // Number of active downtime events forcing the unit to move.
 	moveSpeedBonuses   *MoveHeap
 	moveSpeedPenalties *MoveHeap
 }
@@ -104,6 +106,10 @@ func (unit *Unit) initMovement() {
 		Duration:  NeverExpires,
 		MaxStacks: 30,
 
+		OnReset: func(aura *Aura, sim *Simulation) {
+			unit.MovementHandler.moveAction = nil
+			unit.MovementHandler.forced = 0
+		},
 		OnGain: func(aura *Aura, sim *Simulation) {
 			if unit.IsChanneling(sim) {
 				unit.ChanneledDot.Cancel(sim)
@@ -126,11 +132,17 @@ func (unit *Unit) initMovement() {
 
 		ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
 			unit.MovementHandler.moveAura.Activate(sim)
-			unit.MovementHandler.moveAura.SetStacks(sim, int32(unit.DistanceFromTarget))
+			unit.MovementHandler.moveAura.SetStacks(sim, unit.movementStacks())
 		},
 	})
 }
 
+// The movement aura's stacks show the distance from the target, but it needs
+// at least one stack to stay active while passing close to the target.
+func (unit *Unit) movementStacks() int32 {
+	return max(1, int32(unit.DistanceFromTarget))
+}
+
 func (unit *Unit) IsMoving() bool {
 	return unit.MovementHandler.Moving
 }
@@ -140,26 +152,70 @@ func (unit *Unit) MoveTo(moveRange float64, sim *Simulation) {
 		return
 	}
 
-	moveDistance := moveRange - unit.DistanceFromTarget
-	moveTicks := math.Abs(moveDistance)
-	moveInterval := moveDistance / float64(moveTicks)
+	// Move along the line between the unit and its target.
+	target := unit.distanceTarget()
+	unit.MoveToPosition(sim, target.Position.Towards(unit.Position, moveRange))
+}
+
+// Walks to the destination one yard per tick, interrupting any movement
+// already in progress.
+func (unit *Unit) MoveToPosition(sim *Simulation, destination Position) {
+	if unit.MovementHandler.moveAction != nil {
+		unit.MovementHandler.moveAction.Cancel(sim)
+		unit.MovementHandler.moveAction = nil
+	}
+
+	moveTicks := int(math.Ceil(unit.Position.DistanceTo(destination) - 1e-9))
+	if moveTicks <= 0 {
+		if unit.MovementHandler.forced == 0 {
+			unit.MovementHandler.moveAura.Deactivate(sim)
+		}
+		return
+	}
+	stepX := (destination.X - unit.Position.X) / float64(moveTicks)
+	stepY := (destination.Y - unit.Position.Y) / float64(moveTicks)
 
 	unit.MovementHandler.moveSpell.Cast(sim, unit.CurrentTarget)
 
-	sim.AddPendingAction(NewPeriodicAction(sim, PeriodicActionOptions{
+	tick := 0
+	unit.MovementHandler.moveAction = NewPeriodicAction(sim, PeriodicActionOptions{
 		Period:          time.Millisecond * time.Duration(1000/(unit.MovementHandler.MoveSpeed)),
-		NumTicks:        int(moveTicks),
+		NumTicks:        moveTicks,
 		TickImmediately: false,
 
 		OnAction: func(sim *Simulation) {
-			unit.DistanceFromTarget += moveInterval
-			unit.MovementHandler.moveAura.SetStacks(sim, int32(unit.DistanceFromTarget))
-
-			if unit.DistanceFromTarget == moveRange {
-				unit.MovementHandler.moveAura.Deactivate(sim)
+			tick++
+			if tick == moveTicks {
+				unit.Position = destination
+			} else {
+				unit.Position.X += stepX
+				unit.Position.Y += stepY
+			}
+			unit.updateDistanceFromTarget()
+			unit.MovementHandler.moveAura.SetStacks(sim, unit.movementStacks())
+
+			if tick == moveTicks {
+				unit.MovementHandler.moveAction = nil
+				if unit.MovementHandler.forced == 0 {
+					unit.MovementHandler.moveAura.Deactivate(sim)
+				}
 			}
 		},
-	}))
+	})
+	sim.AddPendingAction(unit.MovementHandler.moveAction)
+}
+
+// Instantly moves the unit, e.g. when it's knocked back.
+func (unit *Unit) SetPosition(sim *Simulation, position Position) {
+	if unit.MovementHandler.moveAction != nil {
+		unit.MovementHandler.moveAction.Cancel(sim)
+		unit.MovementHandler.moveAction = nil
+		if unit.MovementHandler.forced == 0 {
+			unit.MovementHandler.moveAura.Deactivate(sim)
+		}
+	}
+	unit.Position = position
+	unit.updateDistanceFromTarget()
 }
 
 // A move speed increase of 30% should be represented as 1.30 and a move speed slow of 70% should be respresented as 0.70
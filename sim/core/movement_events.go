@@ -0,0 +1,77 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func (env *Environment) scheduleMovementEvents(sim *Simulation) {
+	for _, event := range env.Encounter.MovementEvents {
+		if event.TargetIndex < 0 || event.TargetIndex >= env.GetNumTargets() {
+			continue
+		}
+		event := event
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: DurationFromSeconds(event.Time),
+			OnAction: func(sim *Simulation) {
+				env.applyMovementEvent(sim, event)
+			},
+		})
+	}
+}
+
+// Moves every player as the event requires. Players who are casting finish
+// their cast before moving.
+func (env *Environment) applyMovementEvent(sim *Simulation, event *proto.MovementEvent) {
+	target := env.GetTargetUnit(event.TargetIndex)
+	if sim.Log != nil {
+		target.Log(sim, "Movement event %s (%0.1f yards)", event.Type, event.Distance)
+	}
+
+	center := target.Position
+	players := env.Raid.AllPlayerUnits
+	for i, unit := range players {
+		var destination Position
+		switch event.Type {
+		case proto.MovementEvent_TypeMoveAway:
+			if unit.Position.DistanceTo(center) >= event.Distance {
+				continue
+			}
+			destination = center.Towards(unit.Position, event.Distance)
+		case proto.MovementEvent_TypeStack:
+			destination = Position{X: center.X + event.Distance, Y: center.Y}
+		case proto.MovementEvent_TypeSpread:
+			angle := 2 * math.Pi * float64(i) / float64(len(players))
+			destination = Position{X: center.X + event.Distance*math.Cos(angle), Y: center.Y + event.Distance*math.Sin(angle)}
+		case proto.MovementEvent_TypeKnockback:
+			destination = center.Towards(unit.Position, unit.Position.DistanceTo(center)+event.Distance)
+		default:
+			continue
+		}
+
+		unit := unit
+		previous := unit.Position
+		moveAt := sim.CurrentTime
+		if event.Type == proto.MovementEvent_TypeKnockback {
+			unit.SetPosition(sim, destination)
+		} else {
+			moveAt = max(moveAt, unit.Hardcast.Expires)
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt: moveAt,
+				OnAction: func(sim *Simulation) {
+					unit.MoveToPosition(sim, destination)
+				},
+			})
+		}
+
+		if event.ReturnAfter > 0 {
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt: moveAt + DurationFromSeconds(event.ReturnAfter),
+				OnAction: func(sim *Simulation) {
+					unit.MoveToPosition(sim, previous)
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+func TestMovementEventSpreadAndReturn(t *testing.T) {
+	player := func(name string) *proto.Player {
+		return &proto.Player{
+			Name:               name,
+			Class:              proto.Class_ClassShaman,
+			Consumes:           &proto.Consumes{},
+			Buffs:              &proto.IndividualBuffs{},
+			Spec:               &proto.Player_ElementalShaman{},
+			Equipment:          &proto.EquipmentSpec{},
+			DistanceFromTarget: 5,
+		}
+	}
+	positioned := player("Positioned")
+	positioned.Position = &proto.Position{X: 3, Y: 4}
+
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{player("First"), player("Second"), positioned},
+				Buffs:   &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63, Position: &proto.Position{X: 1, Y: 1}}},
+			Duration: 20,
+			MovementEvents: []*proto.MovementEvent{{
+				Type:        proto.MovementEvent_TypeSpread,
+				Time:        1,
+				Distance:    10,
+				ReturnAfter: 3,
+			}},
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	units := sim.Raid.AllPlayerUnits
+	target := sim.Encounter.TargetUnits[0]
+	if units[0].Position != (Position{X: 6, Y: 1}) {
+		t.Errorf("Expected players without a position to start 5 yards from the target, got %v", units[0].Position)
+	}
+	if math.Abs(units[2].DistanceFromTarget-math.Sqrt(13)) > 1e-9 {
+		t.Errorf("Expected the distance to be worked out from the position, got %f", units[2].DistanceFromTarget)
+	}
+
+	// Check the positions from delayed actions, as stepping the sim can jump past a given time.
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Millisecond * 3500,
+		OnAction: func(sim *Simulation) {
+			for _, unit := range units {
+				if math.Abs(unit.DistanceTo(target)-10) > 1e-9 || unit.IsMoving() {
+					t.Errorf("Expected %s to have spread to 10 yards, got %f", unit.Label, unit.DistanceTo(target))
+				}
+			}
+			if units[0].DistanceTo(units[1]) < 10 {
+				t.Errorf("Expected players to spread apart, got %f yards between them", units[0].DistanceTo(units[1]))
+			}
+		},
+	})
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Second * 8,
+		OnAction: func(sim *Simulation) {
+			for _, unit := range units {
+				if unit.Position != unit.StartPosition || unit.IsMoving() {
+					t.Errorf("Expected %s to be back at %v, got %v", unit.Label, unit.StartPosition, unit.Position)
+				}
+			}
+		},
+	})
+
+	for sim.CurrentTime < time.Second*8 && !sim.Step() {
+	}
+}
+
+func TestTargetsInRadiusSkipsInactiveTargets(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "near", Level: 63},
+				{Name: "far", Level: 63, Position: &proto.Position{X: 20}},
+				{Name: "despawned", Level: 63, Position: &proto.Position{X: 1}},
+				{Name: "untargetable", Level: 63, Position: &proto.Position{Y: 1}},
+			},
+			Duration: 20,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	targets := sim.Encounter.Targets
+	targets[2].Despawn(sim)
+	targets[3].untargetable++
+
+	spell := &Spell{Unit: &sim.Raid.Parties[0].Players[0].GetCharacter().Unit, Radius: 8}
+	if hits := spell.TargetsInRadius(Position{}); len(hits) != 1 || hits[0] != &targets[0].Unit {
+		t.Errorf("Expected only the near target in the radius, got %d targets", len(hits))
+	}
+
+	spell.Radius = 0
+	hits := spell.TargetsInRadius(Position{})
+	if len(hits) != 2 || hits[0] != &targets[0].Unit || hits[1] != &targets[1].Unit {
+		t.Errorf("Expected spells without a radius to hit both active targets, got %d targets", len(hits))
+	}
+	hits[0] = nil
+	if sim.Encounter.TargetUnits[0] == nil || sim.Encounter.ActiveTargetUnits[0] == nil {
+		t.Errorf("Expected the returned targets not to alias the encounter's target lists")
+	}
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+// Runs both sides of a paired sim with the same iterations and seed, then
+// computes the per-iteration differences.
+//
+// Each iteration reseeds the sim from the starting seed plus the iteration
+// index, and labeled rands give every source of randomness its own stream, so
+// iteration i of A and iteration i of B roll the same numbers for anything
+// both configurations have in common.
+func runPairedSim(request *proto.PairedSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.PairedSimResult {
+	simFunc := runSimConcurrent
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() || request.GetRequestA().GetSimOptions().GetIsTest() {
+		simFunc = RunSim
+	}
+	return runPairedSimWith(request, simFunc, progress, signals)
+}
+
+func runPairedSimWith(request *proto.PairedSimRequest, simFunc statWeightsSimFunc, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.PairedSimResult {
+	if request.RequestA == nil || request.RequestB == nil {
+		return &proto.PairedSimResult{Error: &proto.ErrorOutcome{Message: "Paired sims need both request_a and request_b"}}
+	}
+
+	requestA := googleProto.Clone(request.RequestA).(*proto.RaidSimRequest)
+	requestB := googleProto.Clone(request.RequestB).(*proto.RaidSimRequest)
+	if requestA.SimOptions == nil {
+		requestA.SimOptions = &proto.SimOptions{}
+	}
+	saveAllValues := requestA.SimOptions.SaveAllValues
+
+	requestA.SimOptions.SaveAllValues = true
+	requestA.SimOptions.UseLabeledRands = true
+	if requestA.SimOptions.RandomSeed == 0 {
+		requestA.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	requestB.SimOptions = googleProto.Clone(requestA.SimOptions).(*proto.SimOptions)
+
+	iterationsTotal := requestA.SimOptions.Iterations * 2
+	var iterationsDone int32
+	var simsCompleted int32
+	runSide := func(sideRequest *proto.RaidSimRequest) *proto.RaidSimResult {
+		simProgress := make(chan *proto.ProgressMetrics, 100)
+		go simFunc(sideRequest, simProgress, signals)
+
+		var lastCompleted int32
+		for metrics := range simProgress {
+			iterationsDone += metrics.CompletedIterations - lastCompleted
+			lastCompleted = metrics.CompletedIterations
+
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     iterationsTotal,
+					CompletedIterations: iterationsDone,
+					CompletedSims:       simsCompleted,
+					TotalSims:           2,
+				}
+			}
+
+			if metrics.FinalRaidResult != nil {
+				simsCompleted++
+				return metrics.FinalRaidResult
+			}
+		}
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: "Sim ended without a result"}}
+	}
+
+	resultA := runSide(requestA)
+	if resultA.Error != nil {
+		return &proto.PairedSimResult{Error: resultA.Error}
+	}
+	resultB := runSide(requestB)
+	if resultB.Error != nil {
+		return &proto.PairedSimResult{Error: resultB.Error}
+	}
+
+	result, err := computePairedSimResult(resultA, resultB)
+	if err != nil {
+		return &proto.PairedSimResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	if !saveAllValues {
+		clearAllValues(resultA)
+		clearAllValues(resultB)
+	}
+	result.ResultA = resultA
+	result.ResultB = resultB
+	return result
+}
+
+func computePairedSimResult(resultA *proto.RaidSimResult, resultB *proto.RaidSimResult) (*proto.PairedSimResult, error) {
+	result := &proto.PairedSimResult{
+		RaidDps: ComputePairedDifference(resultA.RaidMetrics.Dps, resultB.RaidMetrics.Dps),
+		RaidHps: ComputePairedDifference(resultA.RaidMetrics.Hps, resultB.RaidMetrics.Hps),
+	}
+
+	if len(resultA.RaidMetrics.Parties) != len(resultB.RaidMetrics.Parties) {
+		return nil, fmt.Errorf("Paired sims need the same raid layout, got %d and %d parties", len(resultA.RaidMetrics.Parties), len(resultB.RaidMetrics.Parties))
+	}
+	for i, partyA := range resultA.RaidMetrics.Parties {
+		partyB := resultB.RaidMetrics.Parties[i]
+		if len(partyA.Players) != len(partyB.Players) {
+			return nil, fmt.Errorf("Paired sims need the same raid layout, party %d has %d and %d players", i+1, len(partyA.Players), len(partyB.Players))
+		}
+		for j, playerA := range partyA.Players {
+			playerB := partyB.Players[j]
+			if playerA.Name == "" && playerB.Name == "" {
+				continue
+			}
+			result.Players = append(result.Players, &proto.PairedUnitDifference{
+				Name: playerA.Name,
+				Dps:  ComputePairedDifference(playerA.Dps, playerB.Dps),
+				Hps:  ComputePairedDifference(playerA.Hps, playerB.Hps),
+				Tps:  ComputePairedDifference(playerA.Threat, playerB.Threat),
+				Dtps: ComputePairedDifference(playerA.Dtps, playerB.Dtps),
+				Tmi:  ComputePairedDifference(playerA.Tmi, playerB.Tmi),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// Computes the per-iteration difference b - a of two distributions simmed
+// with SaveAllValues and the same seeds.
+func ComputePairedDifference(a *proto.DistributionMetrics, b *proto.DistributionMetrics) *proto.PairedDifference {
+	n := min(len(a.GetAllValues()), len(b.GetAllValues()))
+	if n == 0 {
+		return &proto.PairedDifference{}
+	}
+
+	var diffs aggregator
+	for i := 0; i < n; i++ {
+		diffs.add(b.AllValues[i] - a.AllValues[i])
+	}
+	mean, stdev := diffs.meanAndStdDev()
+	if math.IsNaN(stdev) {
+		// Rounding can make the variance very slightly negative when all differences are equal.
+		stdev = 0
+	}
+
+	return &proto.PairedDifference{
+		Mean:               mean,
+		Stdev:              stdev,
+		ConfidenceInterval: confidenceIntervalZ * stdev / math.Sqrt(float64(n)),
+	}
+}
+
+func clearAllValues(result *proto.RaidSimResult) {
+	clearDistribution := func(dist *proto.DistributionMetrics) {
+		if dist != nil {
+			dist.AllValues = nil
+		}
+	}
+	var clearUnit func(unit *proto.UnitMetrics)
+	clearUnit = func(unit *proto.UnitMetrics) {
+		clearDistribution(unit.Dps)
+		clearDistribution(unit.Dpasp)
+		clearDistribution(unit.Threat)
+		clearDistribution(unit.Dtps)
+		clearDistribution(unit.Tmi)
+		clearDistribution(unit.Hps)
+		clearDistribution(unit.Tto)
+		for _, pet := range unit.Pets {
+			clearUnit(pet)
+		}
+	}
+
+	clearDistribution(result.RaidMetrics.Dps)
+	clearDistribution(result.RaidMetrics.Hps)
+	for _, party := range result.RaidMetrics.Parties {
+		clearDistribution(party.Dps)
+		clearDistribution(party.Hps)
+		for _, player := range party.Players {
+			clearUnit(player)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+func TestComputePairedDifference(t *testing.T) {
+	a := &proto.DistributionMetrics{AllValues: []float64{100, 200, 300, 400}}
+	b := &proto.DistributionMetrics{AllValues: []float64{102, 200, 304, 402}}
+
+	// Differences are 2, 0, 4 and 2.
+	diff := ComputePairedDifference(a, b)
+	if diff.Mean != 2 {
+		t.Fatalf("Expected a mean difference of 2, got %0.3f", diff.Mean)
+	}
+	expectedStdev := math.Sqrt(2)
+	if math.Abs(diff.Stdev-expectedStdev) > 1e-9 {
+		t.Fatalf("Expected a stdev of %0.3f, got %0.3f", expectedStdev, diff.Stdev)
+	}
+	if expectedCI := confidenceIntervalZ * expectedStdev / 2; math.Abs(diff.ConfidenceInterval-expectedCI) > 1e-9 {
+		t.Fatalf("Expected a confidence interval of %0.3f, got %0.3f", expectedCI, diff.ConfidenceInterval)
+	}
+}
+
+func TestPairedSimIdenticalRequests(t *testing.T) {
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			IsTest:     true,
+		},
+	}
+
+	result := RunPairedSim(&proto.PairedSimRequest{RequestA: request, RequestB: request})
+	if result.Error != nil {
+		t.Fatalf("Paired sim failed: %s", result.Error.Message)
+	}
+
+	// With the same seeds, identical requests must produce identical iterations.
+	if len(result.Players) != 1 || result.Players[0].Dps.Mean != 0 || result.Players[0].Dps.Stdev != 0 {
+		t.Fatalf("Expected no difference between identical requests, got %v", result.Players)
+	}
+	if len(result.ResultA.RaidMetrics.Dps.AllValues) != 0 {
+		t.Fatalf("Per-iteration values should only be returned when requested")
+	}
+}
+
+func TestPairedSimWithoutFinalResult(t *testing.T) {
+	request := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 20}}
+
+	// Stands in for a sim that stops reporting without sending its result.
+	simFunc := func(_ *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, _ simsignals.Signals) *proto.RaidSimResult {
+		progress <- &proto.ProgressMetrics{TotalIterations: 20, CompletedIterations: 10}
+		close(progress)
+		return nil
+	}
+
+	result := runPairedSimWith(&proto.PairedSimRequest{RequestA: request, RequestB: request}, simFunc, nil, simsignals.CreateSignals())
+	if result.Error == nil || result.Error.Message == "" {
+		t.Fatalf("Expected an error when a side ends without a result, got %v", result)
+	}
+}
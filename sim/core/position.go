@@ -0,0 +1,86 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// A point on the encounter floor, in yards.
+type Position struct {
+	X float64
+	Y float64
+}
+
+func PositionFromProto(position *proto.Position) Position {
+	if position == nil {
+		return Position{}
+	}
+	return Position{X: position.X, Y: position.Y}
+}
+
+func (p Position) DistanceTo(other Position) float64 {
+	return math.Hypot(other.X-p.X, other.Y-p.Y)
+}
+
+// Returns the point distance yards from p, in the direction of other. If the
+// points are the same, the direction defaults to the x axis.
+func (p Position) Towards(other Position, distance float64) Position {
+	length := p.DistanceTo(other)
+	if length == 0 {
+		return Position{X: p.X + distance, Y: p.Y}
+	}
+	return Position{
+		X: p.X + (other.X-p.X)/length*distance,
+		Y: p.Y + (other.Y-p.Y)/length*distance,
+	}
+}
+
+// Returns the distance between this unit and another, in yards.
+func (unit *Unit) DistanceTo(other *Unit) float64 {
+	return unit.Position.DistanceTo(other.Position)
+}
+
+// The enemy which DistanceFromTarget is measured from. Healers target their
+// friends, so they keep measuring from the main enemy.
+func (unit *Unit) distanceTarget() *Unit {
+	if unit.CurrentTarget != nil && unit.CurrentTarget.Type == EnemyUnit {
+		return unit.CurrentTarget
+	}
+	return unit.Env.Encounter.TargetUnits[0]
+}
+
+func (unit *Unit) updateDistanceFromTarget() {
+	unit.DistanceFromTarget = unit.DistanceTo(unit.distanceTarget())
+}
+
+// Places units which weren't given a position relative to their target, and
+// works out how far the others start from theirs.
+func (env *Environment) initPositions() {
+	for _, unit := range env.Raid.AllUnits {
+		targetPosition := unit.distanceTarget().StartPosition
+		if unit.hasStartPosition {
+			unit.StartDistanceFromTarget = unit.StartPosition.DistanceTo(targetPosition)
+		} else {
+			unit.StartPosition = Position{X: targetPosition.X + unit.StartDistanceFromTarget, Y: targetPosition.Y}
+		}
+		unit.Position = unit.StartPosition
+		unit.DistanceFromTarget = unit.StartDistanceFromTarget
+	}
+}
+
+// Returns the active, targetable enemies within the spell's Radius of the
+// center. Spells without a Radius hit every one of them. The returned slice is
+// reused by the next call.
+func (spell *Spell) TargetsInRadius(center Position) []*Unit {
+	spell.targetsInRadius = spell.targetsInRadius[:0]
+	for _, target := range spell.Unit.Env.Encounter.ActiveTargetUnits {
+		if target.IsUntargetable() {
+			continue
+		}
+		if spell.Radius == 0 || target.Position.DistanceTo(center) <= spell.Radius {
+			spell.targetsInRadius = append(spell.targetsInRadius, target)
+		}
+	}
+	return spell.targetsInRadius
+}
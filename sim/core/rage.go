@@ -26,6 +26,9 @@ type rageBar struct {
 	currentRage  float64
 
 	RageRefundMetrics *ResourceMetrics
+
+	// Not a real spell, just holds metrics from rage gain threat.
+	rageGainSpell *Spell
 }
 
 type RageBarOptions struct {
@@ -103,7 +106,7 @@ func (unit *Unit) EnableRageBar(options RageBarOptions) {
 				}
 				metrics = spell.ResourceMetrics
 			}
-			unit.AddRage(sim, generatedRage, metrics)
+			unit.rageBar.addRage(sim, generatedRage, metrics, false)
 		},
 		OnSpellHitTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
 			if unit.GetCurrentPowerBar() != RageBar {
@@ -113,12 +116,11 @@ func (unit *Unit) EnableRageBar(options RageBarOptions) {
 			generatedRage := result.Damage * 2.5 / rageConversionDamageTaken
 			generatedRage *= unit.rageBar.damageTakenMultiplier
 			generatedRage += unit.rageBar.flatDamageTakenBonusRage
-			unit.AddRage(sim, generatedRage, rageFromDamageTakenMetrics)
+			unit.rageBar.addRage(sim, generatedRage, rageFromDamageTakenMetrics, false)
 		},
 	})
 
-	// Not a real spell, just holds metrics from rage gain threat.
-	unit.RegisterSpell(SpellConfig{
+	rageGainSpell := unit.RegisterSpell(SpellConfig{
 		ActionID: ActionID{OtherID: proto.OtherAction_OtherActionRageGain},
 	})
 
@@ -128,6 +130,7 @@ func (unit *Unit) EnableRageBar(options RageBarOptions) {
 		damageTakenMultiplier: options.DamageTakenMultiplier,
 		startingRage:          max(0, min(options.StartingRage, MaxRage)),
 		RageRefundMetrics:     unit.NewRageMetrics(ActionID{OtherID: proto.OtherAction_OtherActionRefund}),
+		rageGainSpell:         rageGainSpell,
 	}
 }
 
@@ -156,19 +159,31 @@ func (rb *rageBar) CurrentRage() float64 {
 }
 
 func (rb *rageBar) AddRage(sim *Simulation, amount float64, metrics *ResourceMetrics) {
+	rb.addRage(sim, amount, metrics, true)
+}
+
+// Rage from dealing or taking damage doesn't generate threat, so the rage bar
+// adds it with generatesThreat false.
+func (rb *rageBar) addRage(sim *Simulation, amount float64, metrics *ResourceMetrics, generatesThreat bool) {
 	if amount < 0 {
 		panic("Trying to add negative rage!")
 	}
 
 	newRage := min(rb.currentRage+amount, MaxRage)
 	metrics.AddEvent(amount, newRage-rb.currentRage)
+	if generatesThreat {
+		rb.addRageGainThreat(sim, newRage-rb.currentRage, metrics)
+	}
 
 	if sim.Log != nil {
 		rb.unit.Log(sim, "Gained %0.3f rage from %s (%0.3f --> %0.3f).", amount, metrics.ActionID, rb.currentRage, newRage)
 	}
+	if sim.CombatLog != nil {
+		rb.unit.logEnergize(sim, amount, newRage-rb.currentRage, metrics)
+	}
 
 	rb.currentRage = newRage
-	if !sim.Options.Interactive {
+	if !sim.isInteractive(rb.unit) {
 		rb.unit.Rotation.DoNextAction(sim)
 	}
 	StartDelayedAction(sim, DelayedActionOptions{
@@ -205,37 +220,18 @@ func (rb *rageBar) reset(_ *Simulation) {
 	rb.currentRage = rb.startingRage
 }
 
-func (rb *rageBar) doneIteration() {
-	if rb.unit == nil {
+// Rage gained from anything but dealing or taking damage threatens every enemy
+// in the fight.
+func (rb *rageBar) addRageGainThreat(sim *Simulation, actualGain float64, metrics *ResourceMetrics) {
+	if actualGain <= 0 {
 		return
 	}
-
-	rageGainSpell := rb.unit.GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionRageGain})
-
-	for _, resourceMetrics := range rb.unit.Metrics.resources {
-		if resourceMetrics.Type != proto.ResourceType_ResourceTypeRage {
-			continue
-		}
-		if resourceMetrics.ActionID.SameActionIgnoreTag(ActionID{OtherID: proto.OtherAction_OtherActionDamageTaken}) {
-			continue
-		}
-		if resourceMetrics.ActionID.SameActionIgnoreTag(ActionID{OtherID: proto.OtherAction_OtherActionRefund}) {
-			continue
-		}
-		if resourceMetrics.ActualGainForCurrentIteration() <= 0 {
-			continue
-		}
-
-		// Need to exclude rage gained from white hits. Rather than have a manual list of all IDs that would
-		// apply here (autos, WF attack, sword spec procs, etc), just check if the effect caused any damage.
-		sourceSpell := rb.unit.GetSpell(resourceMetrics.ActionID)
-		if sourceSpell != nil && sourceSpell.SpellMetrics[0].TotalDamage > 0 {
-			continue
-		}
-
-		rageGainSpell.SpellMetrics[0].Casts += resourceMetrics.EventsForCurrentIteration()
-		rageGainSpell.ApplyAOEThreatIgnoreMultipliers(resourceMetrics.ActualGainForCurrentIteration() * ThreatPerRageGained)
+	if metrics.ActionID.SameActionIgnoreTag(ActionID{OtherID: proto.OtherAction_OtherActionRefund}) {
+		return
 	}
+
+	rb.rageGainSpell.SpellMetrics[0].Casts++
+	rb.rageGainSpell.ApplyAOEThreatIgnoreMultipliers(sim, actualGain*ThreatPerRageGained)
 }
 
 type RageCostOptions struct {
@@ -44,6 +44,8 @@ func newShield(config Shield) *Shield {
 	shield := &Shield{}
 	*shield = config
 
+	shield.Aura.casterUnit = shield.Spell.Unit
+
 	return shield
 }
 
@@ -38,11 +38,20 @@ type Simulation struct {
 	Duration       time.Duration // Duration of current iteration
 	NeedsInput     bool          // Sim is in interactive mode and needs input
 
+	// In interactive mode, only this unit waits for input and everyone else follows their rotation.
+	// If nil, every unit waits for input.
+	interactiveUnit *Unit
+
 	ProgressReport func(*proto.ProgressMetrics)
 	Signals simsignals.Signals
 
 	Log func(string, ...interface{})
 
+	// Receives structured combat events when a combat log was requested, nil otherwise.
+	CombatLog func(*CombatLogEvent)
+	// The spell whose effects are being applied, so debuffs can be logged with a source.
+	combatLogSpell *Spell
+
 	executePhase int32 // 20, 25, or 35 for the respective execute range, 100 otherwise
 
 	executePhaseCallbacks []func(*Simulation, int32) // 2nd parameter is 35 for 35%, 25 for 25% and 20 for 20%
@@ -233,7 +242,11 @@ func (sim *Simulation) labelRand(label string) Rand {
 }
 
 func (sim *Simulation) reseedRands(i int64) {
-	rseed := sim.Options.RandomSeed + i
+	sim.seedRands(sim.Options.RandomSeed + i)
+}
+
+// Seeds all rands with the absolute seed of an iteration, as reported by e.g. DistributionMetrics.MinSeed.
+func (sim *Simulation) seedRands(rseed int64) {
 	sim.rand.Seed(rseed)
 
 	if sim.isTest {
@@ -271,6 +284,11 @@ func (sim *Simulation) Proc(p float64, label string) bool {
 	}
 }
 
+// Returns whether the unit waits for input instead of following its rotation.
+func (sim *Simulation) isInteractive(unit *Unit) bool {
+	return sim.Options.Interactive && (sim.interactiveUnit == nil || sim.interactiveUnit == unit)
+}
+
 func (sim *Simulation) Reset() {
 	sim.reset()
 }
@@ -291,6 +309,12 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		}
 	}
 
+	var combatLogWriter *CombatLogWriter
+	if sim.Options.CombatLog {
+		combatLogWriter = NewCombatLogWriter()
+		sim.CombatLog = combatLogWriter.AddEvent
+	}
+
 	// Uncomment this to print logs directly to console.
 	// sim.Options.Debug = true
 	// sim.Log = func(message string, vals ...interface{}) {
@@ -307,6 +331,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	if !sim.Options.Debug {
 		sim.Log = nil
 	}
+	sim.CombatLog = nil
 
 	var st time.Time
 	for i := int32(1); i < sim.Options.Iterations; i++ {
@@ -349,6 +374,10 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
 		IterationsDone: sim.Options.Iterations,
 	}
+	if combatLogWriter != nil {
+		result.CombatLog = combatLogWriter.String()
+	}
+	result.Timelines = sim.recordTimelines(result.RaidMetrics.Dps)
 
 	// Final progress report
 	if sim.ProgressReport != nil {
@@ -42,6 +42,11 @@ func SplitSimRequestForConcurrency(request *proto.RaidSimRequest, splitCount int
 		split[i] = googleProto.Clone(request).(*proto.RaidSimRequest)
 		split[i].SimOptions.Iterations = iterPerSplit
 		split[i].SimOptions.DebugFirstIteration = false // No logs
+		split[i].SimOptions.CombatLog = false
+		if timeline := request.SimOptions.Timeline; timeline != nil {
+			// Requested seeds are only recorded once, min / max DPS iterations are picked after combining.
+			split[i].SimOptions.Timeline = &proto.TimelineOptions{MinDps: timeline.MinDps, MaxDps: timeline.MaxDps}
+		}
 		split[i].SimOptions.RandomSeed = nextStartSeed
 		nextStartSeed += int64(split[i].SimOptions.Iterations)
 	}
@@ -94,6 +99,16 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		newUm.Pets[i] = rsrc.newUnitMetrics(pet)
 	}
 
+	if baseUnit.RotationStats != nil {
+		newActionStats := func(baseStats *proto.APLActionStats) *proto.APLActionStats {
+			return &proto.APLActionStats{Warnings: baseStats.Warnings, AggregatorData: &proto.AggregatorData{}}
+		}
+		newUm.RotationStats = &proto.APLStats{
+			PrepullActions: MapSlice(baseUnit.RotationStats.PrepullActions, newActionStats),
+			PriorityList:   MapSlice(baseUnit.RotationStats.PriorityList, newActionStats),
+		}
+	}
+
 	return newUm
 }
 
@@ -186,6 +201,7 @@ func (rsrc *raidSimResultCombiner) addActionMetrics(unit *proto.UnitMetrics, add
 		baseTgt.Blocks += addTgt.Blocks
 		baseTgt.BlockedCrits += addTgt.BlockedCrits
 		baseTgt.Glances += addTgt.Glances
+		baseTgt.Crushes += addTgt.Crushes
 		baseTgt.Damage += addTgt.Damage
 		baseTgt.ResistedDamage += addTgt.ResistedDamage
 		baseTgt.CritDamage += addTgt.CritDamage
@@ -195,12 +211,14 @@ func (rsrc *raidSimResultCombiner) addActionMetrics(unit *proto.UnitMetrics, add
 		baseTgt.CritTickDamage += addTgt.CritTickDamage
 		baseTgt.ResistedCritTickDamage += addTgt.ResistedCritTickDamage
 		baseTgt.GlanceDamage += addTgt.GlanceDamage
+		baseTgt.CrushDamage += addTgt.CrushDamage
 		baseTgt.BlockDamage += addTgt.BlockDamage
 		baseTgt.BlockedCritDamage += addTgt.BlockedCritDamage
 		baseTgt.Threat += addTgt.Threat
 		baseTgt.Healing += addTgt.Healing
 		baseTgt.CritHealing += addTgt.CritHealing
 		baseTgt.Shielding += addTgt.Shielding
+		baseTgt.Overhealing += addTgt.Overhealing
 		baseTgt.CastTimeMs += addTgt.CastTimeMs
 	}
 }
@@ -216,6 +234,16 @@ func (rsrc *raidSimResultCombiner) combineAuraMetrics(base *proto.AuraMetrics, a
 	}
 }
 
+func (rsrc *raidSimResultCombiner) combineAPLActionStats(base *proto.APLActionStats, add *proto.APLActionStats, weight float64) {
+	if numIntervals := base.AggregatorData.N + add.AggregatorData.N; numIntervals > 0 {
+		base.SecondsBetweenExecutionsAvg = (base.SecondsBetweenExecutionsAvg*float64(base.AggregatorData.N) + add.SecondsBetweenExecutionsAvg*float64(add.AggregatorData.N)) / float64(numIntervals)
+		base.AggregatorData.N = numIntervals
+	}
+	base.EvaluationsAvg += add.EvaluationsAvg * weight
+	base.ReadyAvg += add.ReadyAvg * weight
+	base.ExecutionsAvg += add.ExecutionsAvg * weight
+}
+
 func (rsrc *raidSimResultCombiner) addResourceMetrics(unit *proto.UnitMetrics, add *proto.ResourceMetrics) {
 	var rm *proto.ResourceMetrics
 
@@ -270,6 +298,12 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
+
+	if base.RotationStats != nil && add.RotationStats != nil {
+		for i, addStats := range add.RotationStats.PriorityList {
+			rsrc.combineAPLActionStats(base.RotationStats.PriorityList[i], addStats, weight)
+		}
+	}
 }
 
 func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast bool, weight float64) {
@@ -295,6 +329,11 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 	if rsrc.Debug {
 		rsrc.Combined.Logs += "-SIMSTART-\n" + result.Logs
 	}
+
+	rsrc.Combined.Timelines = append(rsrc.Combined.Timelines, result.Timelines...)
+	if isLast {
+		rsrc.Combined.Timelines = combineTimelines(rsrc.Combined.Timelines, rsrc.Combined.RaidMetrics.Dps)
+	}
 }
 
 func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
@@ -313,6 +352,7 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 	if !rsrc.Debug {
 		newRsr.Logs = baseRsr.Logs
 	}
+	newRsr.CombatLog = baseRsr.CombatLog
 
 	for i, party := range baseRsr.RaidMetrics.Parties {
 		newRsr.RaidMetrics.Parties[i] = rsrc.newPartyMetrics(party)
@@ -0,0 +1,279 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	googleProto "google.golang.org/protobuf/proto"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// A reinforcement learning style environment controlling a single player of an interactive sim.
+// Each step takes an action, runs the sim until the player needs input again, and returns the
+// new observation with the damage done in between as the reward.
+type SimEnv struct {
+	*InteractiveSim
+
+	waitDuration time.Duration
+
+	// Spellbook indices of the castable spells. Spellbooks and aura lists are built during environment
+	// construction, so they're the same for every sim constructed from the request.
+	actionSpells []int
+
+	lastDamage float64
+	done       bool
+}
+
+func NewSimEnv(request *proto.EnvRequest, seed int64) (*SimEnv, error) {
+	if request.Request == nil {
+		return nil, fmt.Errorf("env requests need a raid sim request")
+	}
+	simRequest := googleProto.Clone(request.Request).(*proto.RaidSimRequest)
+	if simRequest.SimOptions == nil {
+		simRequest.SimOptions = &proto.SimOptions{}
+	}
+	simRequest.SimOptions.Interactive = true
+
+	isim, err := NewInteractiveSim(simRequest, seed, request.PlayerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &SimEnv{
+		InteractiveSim: isim,
+		waitDuration:   DurationFromSeconds(request.WaitSeconds),
+	}
+	if env.waitDuration <= 0 {
+		env.waitDuration = time.Millisecond * 100
+	}
+
+	character := env.Player().GetCharacter()
+	for i, spell := range character.Spellbook {
+		if spell.Flags.Matches(SpellFlagAPL) {
+			env.actionSpells = append(env.actionSpells, i)
+		}
+	}
+
+	env.advance()
+	return env, nil
+}
+
+func (env *SimEnv) Spec() *proto.EnvSpec {
+	character := env.Player().GetCharacter()
+	spec := &proto.EnvSpec{}
+	for _, i := range env.actionSpells {
+		spec.Actions = append(spec.Actions, character.Spellbook[i].ActionID.ToProto())
+	}
+	for _, aura := range character.GetAuras() {
+		spec.PlayerAuras = append(spec.PlayerAuras, aura.ActionID.ToProto())
+		spec.PlayerAuraLabels = append(spec.PlayerAuraLabels, aura.Label)
+	}
+	for _, aura := range character.CurrentTarget.GetAuras() {
+		spec.TargetAuras = append(spec.TargetAuras, aura.ActionID.ToProto())
+		spec.TargetAuraLabels = append(spec.TargetAuraLabels, aura.Label)
+	}
+	spec.ObservationSize = int32(len(EnvObservationToVector(env.Observe())))
+	return spec
+}
+
+// Applies the action, 0 to wait or i to cast the i-th castable spell, then runs the sim until the
+// player needs input again or the iteration is over.
+func (env *SimEnv) Step(action int32) *proto.EnvStepResult {
+	if env.done {
+		return &proto.EnvStepResult{Observation: env.Observe(), Done: true}
+	}
+
+	succeeded := true
+	if action != 0 {
+		if action < 0 || int(action) > len(env.actionSpells) {
+			succeeded = false
+		} else {
+			succeeded = env.ApplyInput(castSpellbookSpellInput(env.Player().GetCharacter().UnitIndex, env.actionSpells[action-1]))
+		}
+	}
+	if action == 0 || !succeeded {
+		env.ApplyInput(waitInput(env.Player().GetCharacter().UnitIndex, env.waitDuration))
+	}
+
+	env.advance()
+
+	damage := env.damageDone()
+	reward := damage - env.lastDamage
+	env.lastDamage = damage
+
+	return &proto.EnvStepResult{
+		Observation:     env.Observe(),
+		Reward:          reward,
+		Done:            env.done,
+		ActionSucceeded: succeeded,
+	}
+}
+
+func (env *SimEnv) advance() {
+	for !env.NeedsInput && !env.done {
+		env.done = env.InteractiveSim.Step()
+	}
+	if env.done {
+		env.Cleanup()
+	}
+}
+
+func (env *SimEnv) Restore(snapshot *SimSnapshot) error {
+	if err := env.InteractiveSim.Restore(snapshot); err != nil {
+		return err
+	}
+	env.done = false
+	env.advance()
+	env.lastDamage = env.damageDone()
+	return nil
+}
+
+func (env *SimEnv) damageDone() float64 {
+	character := env.Player().GetCharacter()
+	damage := 0.0
+	for _, unit := range append([]*Unit{&character.Unit}, petUnits(character)...) {
+		for _, spell := range unit.Spellbook {
+			for _, metrics := range spell.SpellMetrics {
+				damage += metrics.TotalDamage
+			}
+		}
+	}
+	return damage
+}
+
+func petUnits(character *Character) []*Unit {
+	units := make([]*Unit, len(character.Pets))
+	for i, pet := range character.Pets {
+		units[i] = &pet.Unit
+	}
+	return units
+}
+
+func (env *SimEnv) Observe() *proto.EnvObservation {
+	sim := env.Simulation
+	character := env.Player().GetCharacter()
+	target := character.CurrentTarget
+
+	obs := &proto.EnvObservation{
+		CurrentTime:         sim.CurrentTime.Seconds(),
+		RemainingDuration:   sim.GetRemainingDuration().Seconds(),
+		TargetHealthPercent: sim.GetRemainingDurationPercent(),
+		Resources: &proto.EnvResources{
+			HealthPercent: 1,
+		},
+		GcdRemaining: character.GCD.TimeToReady(sim).Seconds(),
+	}
+	if target.HasHealthBar() {
+		obs.TargetHealthPercent = target.CurrentHealthPercent()
+	}
+	if character.HasHealthBar() {
+		obs.Resources.HealthPercent = character.CurrentHealthPercent()
+	}
+	if character.HasManaBar() {
+		obs.Resources.Mana = character.CurrentMana()
+		obs.Resources.ManaPercent = character.CurrentManaPercent()
+	}
+	if character.HasRageBar() {
+		obs.Resources.Rage = character.CurrentRage()
+	}
+	if character.HasEnergyBar() {
+		obs.Resources.Energy = character.CurrentEnergy()
+		obs.Resources.ComboPoints = character.ComboPoints()
+	}
+	if character.IsCasting(sim) {
+		obs.CastRemaining = (character.Hardcast.Expires - sim.CurrentTime).Seconds()
+	}
+
+	obs.MhSwingRemaining = swingRemaining(sim, character.AutoAttacks.MainhandSwingAt())
+	obs.OhSwingRemaining = swingRemaining(sim, character.AutoAttacks.OffhandSwingAt())
+	obs.RangedSwingRemaining = swingRemaining(sim, character.AutoAttacks.NextRangedAttackAt())
+
+	for _, i := range env.actionSpells {
+		spell := character.Spellbook[i]
+		obs.Spells = append(obs.Spells, &proto.EnvSpellState{
+			CooldownRemaining: spell.TimeToReady(sim).Seconds(),
+			CanCast:           spell.CanCast(sim, target),
+		})
+	}
+	obs.PlayerAuras = observeAuras(sim, character.GetAuras())
+	obs.TargetAuras = observeAuras(sim, target.GetAuras())
+	return obs
+}
+
+func swingRemaining(sim *Simulation, swingAt time.Duration) float64 {
+	if swingAt == NeverExpires || swingAt < sim.CurrentTime {
+		return 0
+	}
+	return (swingAt - sim.CurrentTime).Seconds()
+}
+
+func observeAuras(sim *Simulation, auras []*Aura) []*proto.EnvAuraState {
+	states := make([]*proto.EnvAuraState, len(auras))
+	for i, aura := range auras {
+		state := &proto.EnvAuraState{}
+		if aura.IsActive() {
+			state.Active = true
+			state.Stacks = aura.GetStacks()
+			if aura.ExpiresAt() != NeverExpires {
+				state.Remaining = aura.RemainingDuration(sim).Seconds()
+			}
+		}
+		states[i] = state
+	}
+	return states
+}
+
+// Flattens an observation into a fixed length vector, with booleans as 0 or 1.
+func EnvObservationToVector(obs *proto.EnvObservation) []float64 {
+	boolToFloat := func(b bool) float64 {
+		return TernaryFloat64(b, 1, 0)
+	}
+
+	vector := []float64{
+		obs.CurrentTime,
+		obs.RemainingDuration,
+		obs.TargetHealthPercent,
+		obs.Resources.HealthPercent,
+		obs.Resources.Mana,
+		obs.Resources.ManaPercent,
+		obs.Resources.Rage,
+		obs.Resources.Energy,
+		float64(obs.Resources.ComboPoints),
+		obs.GcdRemaining,
+		obs.CastRemaining,
+		obs.MhSwingRemaining,
+		obs.OhSwingRemaining,
+		obs.RangedSwingRemaining,
+	}
+	for _, spell := range obs.Spells {
+		vector = append(vector, spell.CooldownRemaining, boolToFloat(spell.CanCast))
+	}
+	for _, aura := range append(append([]*proto.EnvAuraState{}, obs.PlayerAuras...), obs.TargetAuras...) {
+		vector = append(vector, boolToFloat(aura.Active), aura.Remaining, float64(aura.Stacks))
+	}
+	return vector
+}
+
+func castSpellbookSpellInput(unitIndex int32, spellbookIndex int) SimInput {
+	return func(sim *Simulation) bool {
+		character := sim.Raid.GetPlayerFromUnitIndex(unitIndex).GetCharacter()
+		spell := character.Spellbook[spellbookIndex]
+		if !spell.CanCast(sim, character.CurrentTarget) || !spell.Cast(sim, character.CurrentTarget) {
+			return false
+		}
+		if spell.CurCast.GCD > 0 {
+			sim.NeedsInput = false
+		}
+		return true
+	}
+}
+
+func waitInput(unitIndex int32, duration time.Duration) SimInput {
+	return func(sim *Simulation) bool {
+		character := sim.Raid.GetPlayerFromUnitIndex(unitIndex).GetCharacter()
+		character.WaitUntil(sim, max(character.GCD.ReadyAt(), sim.CurrentTime+duration))
+		sim.NeedsInput = false
+		return true
+	}
+}
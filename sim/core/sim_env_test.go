@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestSimEnvStepsUntilDone(t *testing.T) {
+	request := &proto.EnvRequest{
+		Request: &proto.RaidSimRequest{
+			Raid: &proto.Raid{
+				Parties: []*proto.Party{{
+					Players: []*proto.Player{{
+						Name:      "Caster",
+						Class:     proto.Class_ClassShaman,
+						Consumes:  &proto.Consumes{},
+						Buffs:     &proto.IndividualBuffs{},
+						Spec:      &proto.Player_ElementalShaman{},
+						Equipment: &proto.EquipmentSpec{},
+					}},
+					Buffs: &proto.PartyBuffs{},
+				}},
+			},
+			Encounter: &proto.Encounter{
+				Targets:  []*proto.Target{{Name: "target", Level: 63}},
+				Duration: 10,
+			},
+			SimOptions: &proto.SimOptions{
+				IsTest: true,
+			},
+		},
+		WaitSeconds: 1,
+	}
+
+	if _, err := NewSimEnv(&proto.EnvRequest{Request: request.Request, PlayerIndex: 1}, 1); err == nil {
+		t.Fatalf("Expected an error for a missing player")
+	}
+
+	env, err := NewSimEnv(request, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := env.Spec()
+	if size := len(EnvObservationToVector(env.Observe())); int32(size) != spec.ObservationSize {
+		t.Fatalf("Expected observations of size %d, got %d", spec.ObservationSize, size)
+	}
+
+	if result := env.Step(int32(len(spec.Actions) + 1)); result.ActionSucceeded {
+		t.Fatalf("Expected an out of range action to fail")
+	}
+
+	snapshot := env.Snapshot()
+	waitsToEnd := func() int {
+		waits := 0
+		lastTime := env.CurrentTime
+		for !env.Step(0).Done {
+			if env.CurrentTime <= lastTime {
+				t.Fatalf("Expected waiting to advance the sim past %s", lastTime)
+			}
+			lastTime = env.CurrentTime
+			waits++
+		}
+		return waits
+	}
+
+	// 10 second fight with 1 second waits.
+	waits := waitsToEnd()
+	if waits < 7 || waits > 9 {
+		t.Fatalf("Expected about 8 waits until the end of the fight, got %d", waits)
+	}
+
+	if err := env.Restore(snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if restoredWaits := waitsToEnd(); restoredWaits != waits {
+		t.Fatalf("Expected %d waits after restoring, got %d", waits, restoredWaits)
+	}
+}
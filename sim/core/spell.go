@@ -23,6 +23,8 @@ type SpellConfig struct {
 	Flags         SpellFlag
 	CastType      proto.CastType
 	MissileSpeed  float64
+	MaxRange      float64
+	Radius        float64
 	BaseCost      float64
 	MetricSplits  int
 	Rank          int
@@ -100,6 +102,14 @@ type Spell struct {
 	// Example: https://wow.tools/dbc/?dbc=spellmisc&build=3.4.0.44996
 	MissileSpeed float64
 
+	// Maximum distance to the target, in yards. 0 means the spell can be cast
+	// from any distance.
+	MaxRange float64
+
+	// Radius of an area of effect spell, in yards. 0 means it hits every target.
+	Radius          float64
+	targetsInRadius []*Unit
+
 	Rank          int
 	RequiredLevel int
 
@@ -243,6 +253,8 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		Flags:        config.Flags,
 		CastType:     config.CastType,
 		MissileSpeed: config.MissileSpeed,
+		MaxRange:     config.MaxRange,
+		Radius:       config.Radius,
 
 		SpellSchool:       config.SpellSchool,
 		SchoolIndex:       config.SpellSchool.GetSchoolIndex(),
@@ -506,6 +518,15 @@ func (spell *Spell) TimeToReady(sim *Simulation) time.Duration {
 	return MaxTimeToReady(spell.CdSpell.CD.Timer, spell.CdSpell.SharedCD.Timer, sim)
 }
 
+// Whether this spell is used against an enemy. Self-cast spells are also given
+// the current target, so this goes by how the spell is resolved instead.
+func (spell *Spell) isHostile() bool {
+	if spell.Flags.Matches(SpellFlagHelpful) {
+		return false
+	}
+	return spell.ProcMask&^(ProcMaskEmpty|ProcMaskSpellHealing) != 0 || spell.DefenseType != DefenseTypeNone
+}
+
 // Returns whether a call to Cast() would be successful, without actually doing a cast.
 func (spell *Spell) CanCast(sim *Simulation, target *Unit) bool {
 	if spell == nil {
@@ -543,6 +564,30 @@ func (spell *Spell) CanCast(sim *Simulation, target *Unit) bool {
 		return false
 	}
 
+	if spell.Unit.IsSilenced() && spell.ActionID.SpellID != 0 && spell.SpellSchool != SpellSchoolPhysical {
+		//if sim.Log != nil {
+		//	sim.Log("Cant cast because silenced")
+		//}
+		return false
+	}
+
+	if target == nil {
+		target = spell.Unit.CurrentTarget
+	}
+	if target != nil && target.Type == EnemyUnit && target.IsUntargetable() && spell.isHostile() {
+		//if sim.Log != nil {
+		//	sim.Log("Cant cast because target is untargetable")
+		//}
+		return false
+	}
+
+	if spell.MaxRange > 0 && target != nil && spell.Unit.DistanceTo(target) > spell.MaxRange {
+		//if sim.Log != nil {
+		//	sim.Log("Cant cast because out of range")
+		//}
+		return false
+	}
+
 	if spell.DefaultCast.GCD > 0 && !spell.Unit.GCD.IsReady(sim) {
 		//if sim.Log != nil {
 		//	sim.Log("Cant cast because of GCD")
@@ -580,17 +625,29 @@ func (spell *Spell) applyEffects(sim *Simulation, target *Unit) {
 	spell.SpellMetrics[target.UnitIndex].Casts++
 	spell.casts++
 
+	if sim.CombatLog != nil {
+		spell.logCastSuccess(sim, target)
+
+		outerSpell := sim.combatLogSpell
+		sim.combatLogSpell = spell
+		spell.ApplyEffects(sim, target, spell)
+		sim.combatLogSpell = outerSpell
+		return
+	}
+
 	spell.ApplyEffects(sim, target, spell)
 }
 
-func (spell *Spell) ApplyAOEThreatIgnoreMultipliers(threatAmount float64) {
-	numTargets := spell.Unit.Env.GetNumTargets()
-	for i := int32(0); i < numTargets; i++ {
-		spell.SpellMetrics[i].TotalThreat += threatAmount
+// Adds the threat to every enemy in the fight, for effects which threaten
+// everything without dealing damage, e.g. shouts or resource gains.
+func (spell *Spell) ApplyAOEThreatIgnoreMultipliers(sim *Simulation, threatAmount float64) {
+	for _, target := range spell.Unit.Env.Encounter.ActiveTargetUnits {
+		spell.SpellMetrics[target.UnitIndex].TotalThreat += threatAmount
+		spell.Unit.addThreatTo(sim, target, threatAmount)
 	}
 }
-func (spell *Spell) ApplyAOEThreat(threatAmount float64) {
-	spell.ApplyAOEThreatIgnoreMultipliers(threatAmount * spell.Unit.PseudoStats.ThreatMultiplier)
+func (spell *Spell) ApplyAOEThreat(sim *Simulation, threatAmount float64) {
+	spell.ApplyAOEThreatIgnoreMultipliers(sim, threatAmount*spell.Unit.PseudoStats.ThreatMultiplier)
 }
 
 func (spell *Spell) finalizeExpectedDamage(result *SpellResult) {
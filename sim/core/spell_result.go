@@ -397,6 +397,13 @@ func (spell *Spell) CalcAndDealOutcome(sim *Simulation, target *Unit, outcomeApp
 
 // Applies the fully computed spell result to the sim.
 func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result *SpellResult) {
+	// Damage against enemies that are not in the fight is lost, so cleaves and
+	// AoEs only count the targets that are actually alive.
+	if result.Target.Type == EnemyUnit && !result.Target.enabled {
+		spell.DisposeResult(result)
+		return
+	}
+
 	isPartialResist := result.DidResist()
 
 	if sim.CurrentTime >= 0 {
@@ -432,6 +439,7 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.SpellMetrics[result.Target.UnitIndex].TotalCrushDamage += result.Damage
 		}
 		spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+		spell.Unit.addThreatTo(sim, result.Target, result.Threat)
 	}
 
 	// Mark total damage done in raid so far for health based fights.
@@ -447,6 +455,9 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		}
 	}
+	if sim.CombatLog != nil {
+		spell.logDamage(sim, isPeriodic, result)
+	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
 		if isPeriodic {
@@ -458,6 +469,12 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 		}
 	}
 
+	if result.Target.Type == EnemyUnit {
+		if target := sim.Encounter.Targets[result.Target.Index]; target.DiesAtZeroHealth {
+			target.onDamageTaken(sim, result.Damage)
+		}
+	}
+
 	spell.DisposeResult(result)
 }
 func (spell *Spell) DealDamage(sim *Simulation, result *SpellResult) {
@@ -521,6 +538,8 @@ func (dot *Dot) CalcSnapshotHealing(sim *Simulation, target *Unit, outcomeApplie
 	return dot.Spell.calcHealingInternal(sim, target, dot.SnapshotBaseDamage, dot.SnapshotAttackerMultiplier, outcomeApplier)
 }
 
+// Uses the same caster modifiers and crit chance as CalcHealing, so a HoT isn't
+// scaled by damage modifiers or the target's attack table.
 func (dot *Dot) SnapshotHeal(target *Unit, baseHealing float64, isRollover bool) {
 	// Rollovers in SoD don't seem to update anything
 	if !isRollover {
@@ -529,11 +548,10 @@ func (dot *Dot) SnapshotHeal(target *Unit, baseHealing float64, isRollover bool)
 			dot.SnapshotBaseDamage += dot.BonusCoefficient * dot.Spell.HealingPower(target)
 		}
 
-		attackTable := dot.Spell.Unit.AttackTables[target.UnitIndex][dot.Spell.CastType]
-		dot.SnapshotAttackerMultiplier = dot.Spell.AttackerDamageMultiplier(attackTable)
+		dot.SnapshotAttackerMultiplier = dot.Spell.CasterHealingMultiplier()
 		dot.SnapshotAttackerMultiplier *= dot.DamageMultiplier
 
-		dot.SnapshotCritChance = dot.Spell.SpellCritChance(target)
+		dot.SnapshotCritChance = dot.Spell.HealingCritChance()
 	}
 }
 
@@ -544,9 +562,14 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	}
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+	spell.Unit.addThreatToActiveTargets(sim, result.Threat)
+	overHealing := 0.0
 	if result.Target.HasHealthBar() {
+		oldHealth := result.Target.CurrentHealth()
 		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
+		overHealing = result.Damage - (result.Target.CurrentHealth() - oldHealth)
 	}
+	spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += overHealing
 
 	if sim.Log != nil {
 		if isPeriodic {
@@ -555,6 +578,9 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
+	if sim.CombatLog != nil {
+		spell.logHealing(sim, isPeriodic, result, overHealing)
+	}
 
 	if isPeriodic {
 		spell.Unit.OnPeriodicHealDealt(sim, spell, result)
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// A copy of every value reachable from a root object, which can be written back into the same
+// objects any number of times.
+//
+// Sim state is spread over units, auras, dots, timers, pending actions, metrics and the RNG, and
+// much of it is referenced from closures registered during environment construction. Copying the
+// object graph would leave those closures pointing at the old objects, so instead the values are
+// restored in place and every pointer held by the sim or a caller stays valid. Objects allocated
+// after the capture are simply dropped, as nothing restored references them.
+//
+// Only state reachable through fields, slices, maps and interfaces is captured. Variables captured
+// by closures can't be reached, so effects need to keep iteration state in fields to be restorable.
+type stateCapture struct {
+	visited map[stateKey]struct{}
+	values  []capturedValue
+	maps    []capturedMap
+}
+
+type stateKey struct {
+	addr unsafe.Pointer
+	typ  reflect.Type
+}
+
+type capturedValue struct {
+	target reflect.Value
+	saved  reflect.Value
+}
+
+type capturedMap struct {
+	target reflect.Value
+	keys   []reflect.Value
+	values []reflect.Value
+}
+
+// Structs from these packages are config which doesn't change during an iteration, or which isn't
+// safe to write back.
+var stateCaptureSkippedPackages = []string{
+	"github.com/wowsims/sod/sim/core/proto",
+	"google.golang.org/protobuf",
+	"sync",
+	"time",
+	"reflect",
+}
+
+func captureState(root any) *stateCapture {
+	capture := &stateCapture{visited: make(map[stateKey]struct{})}
+	capture.follow(reflect.ValueOf(root))
+	return capture
+}
+
+// Writes the captured values back, skipping those which haven't changed.
+func (capture *stateCapture) restore() {
+	for _, value := range capture.values {
+		if !bytes.Equal(valueBytes(value.target), valueBytes(value.saved)) {
+			value.target.Set(value.saved)
+		}
+	}
+	for _, m := range capture.maps {
+		if mapUnchanged(m) {
+			continue
+		}
+		m.target.Clear()
+		for i, key := range m.keys {
+			m.target.SetMapIndex(key, m.values[i])
+		}
+	}
+}
+
+func (capture *stateCapture) follow(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			capture.captureAt(value.UnsafePointer(), value.Type().Elem())
+		}
+	case reflect.Interface:
+		if !value.IsNil() {
+			capture.follow(addressable(value.Elem()))
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			capture.follow(writable(value.Field(i)))
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			capture.follow(value.Index(i))
+		}
+	case reflect.Slice:
+		if value.Len() > 0 && value.Type().Elem().Size() > 0 {
+			capture.captureAt(value.UnsafePointer(), reflect.ArrayOf(value.Len(), value.Type().Elem()))
+		}
+	case reflect.Map:
+		capture.captureMap(value)
+	}
+}
+
+// Saves the object of the given type at addr, then follows its references.
+func (capture *stateCapture) captureAt(addr unsafe.Pointer, typ reflect.Type) {
+	key := stateKey{addr: addr, typ: typ}
+	if _, ok := capture.visited[key]; ok || skipStateCapture(typ) {
+		return
+	}
+	capture.visited[key] = struct{}{}
+
+	target := reflect.NewAt(typ, addr).Elem()
+	saved := reflect.New(typ).Elem()
+	saved.Set(target)
+	capture.values = append(capture.values, capturedValue{target: target, saved: saved})
+
+	capture.follow(target)
+}
+
+func (capture *stateCapture) captureMap(value reflect.Value) {
+	if value.IsNil() {
+		return
+	}
+	key := stateKey{addr: value.UnsafePointer(), typ: value.Type()}
+	if _, ok := capture.visited[key]; ok || skipStateCapture(value.Type()) {
+		return
+	}
+	capture.visited[key] = struct{}{}
+
+	m := capturedMap{target: value}
+	iter := value.MapRange()
+	for iter.Next() {
+		m.keys = append(m.keys, addressable(iter.Key()))
+		m.values = append(m.values, addressable(iter.Value()))
+	}
+	capture.maps = append(capture.maps, m)
+
+	for i := range m.keys {
+		capture.follow(m.keys[i])
+		capture.follow(m.values[i])
+	}
+}
+
+func skipStateCapture(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+		typ = typ.Elem()
+	}
+	pkgPath := typ.PkgPath()
+	if typ.Kind() != reflect.Struct || pkgPath == "" {
+		return false
+	}
+	for _, skipped := range stateCaptureSkippedPackages {
+		if pkgPath == skipped || strings.HasPrefix(pkgPath, skipped+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func mapUnchanged(m capturedMap) bool {
+	if m.target.Len() != len(m.keys) {
+		return false
+	}
+	for i, key := range m.keys {
+		current := m.target.MapIndex(key)
+		if !current.IsValid() || !bytes.Equal(valueBytes(addressable(current)), valueBytes(m.values[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a settable view of an addressable value, even if it was reached through unexported fields.
+func writable(value reflect.Value) reflect.Value {
+	if !value.CanAddr() {
+		return value
+	}
+	return reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr())).Elem()
+}
+
+// Returns an addressable copy of the value, e.g. for map entries or values held by interfaces.
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return writable(value)
+	}
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+	return copied
+}
+
+func valueBytes(value reflect.Value) []byte {
+	size := value.Type().Size()
+	if size == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(value.UnsafeAddr())), size)
+}
@@ -596,6 +596,13 @@ func (s UnitStat) AddToStatsProto(p *proto.UnitStats, value float64) {
 		p.PseudoStats[s.PseudoStatIdx()] += value
 	}
 }
+func (s UnitStat) GetFromStatsProto(p *proto.UnitStats) float64 {
+	if s.IsStat() {
+		return p.Stats[s.StatIdx()]
+	} else {
+		return p.PseudoStats[s.PseudoStatIdx()]
+	}
+}
 
 func UnitStatFromIdx(s int) UnitStat                     { return UnitStat(s) }
 func UnitStatFromStat(s Stat) UnitStat                   { return UnitStat(s) }
@@ -12,6 +12,12 @@ import (
 
 const DTPSReferenceStat = stats.Armor
 
+// z-score for a two-sided 95% confidence interval.
+const confidenceIntervalZ = 1.96
+
+// Time budget for adaptive stat weights when the request doesn't set one.
+const defaultAdaptiveStatWeightsTime = time.Minute
+
 type UnitStats struct {
 	Stats       stats.Stats
 	PseudoStats []float64
@@ -49,6 +55,10 @@ type StatWeightValues struct {
 	WeightsStdev  UnitStats
 	EpValues      UnitStats
 	EpValuesStdev UnitStats
+
+	// Half-widths of the 95% confidence intervals of Weights and EpValues.
+	WeightsCI  UnitStats
+	EpValuesCI UnitStats
 }
 
 func NewStatWeightValues() StatWeightValues {
@@ -57,15 +67,19 @@ func NewStatWeightValues() StatWeightValues {
 		WeightsStdev:  NewUnitStats(),
 		EpValues:      NewUnitStats(),
 		EpValuesStdev: NewUnitStats(),
+		WeightsCI:     NewUnitStats(),
+		EpValuesCI:    NewUnitStats(),
 	}
 }
 
 func (swv *StatWeightValues) ToProto() *proto.StatWeightValues {
 	return &proto.StatWeightValues{
-		Weights:       swv.Weights.ToProto(),
-		WeightsStdev:  swv.WeightsStdev.ToProto(),
-		EpValues:      swv.EpValues.ToProto(),
-		EpValuesStdev: swv.EpValuesStdev.ToProto(),
+		Weights:                    swv.Weights.ToProto(),
+		WeightsStdev:               swv.WeightsStdev.ToProto(),
+		EpValues:                   swv.EpValues.ToProto(),
+		EpValuesStdev:              swv.EpValuesStdev.ToProto(),
+		WeightsConfidenceInterval:  swv.WeightsCI.ToProto(),
+		EpValuesConfidenceInterval: swv.EpValuesCI.ToProto(),
 	}
 }
 
@@ -232,6 +246,22 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 			mean, stdev := lo.merge(&hi).meanAndStdDev()
 			weightResults.Weights.AddStat(stat, mean)
 			weightResults.WeightsStdev.AddStat(stat, stdev)
+
+			// The low and high sims of an iteration share their random numbers, so
+			// their errors are correlated. Average them per iteration, and take
+			// the confidence interval over those paired weights.
+			var paired aggregator
+			for i := 0; i < len(baselineMetrics.AllValues); i++ {
+				loWeight := (modLowMetrics.AllValues[i] - baselineMetrics.AllValues[i]) / statResult.StatData.ModLow
+				hiWeight := (modHighMetrics.AllValues[i] - baselineMetrics.AllValues[i]) / statResult.StatData.ModHigh
+				paired.add((loWeight + hiWeight) / 2)
+			}
+			_, pairedStdev := paired.meanAndStdDev()
+			if math.IsNaN(pairedStdev) {
+				// Rounding can make the variance very slightly negative when all weights are equal.
+				pairedStdev = 0
+			}
+			weightResults.WeightsCI.AddStat(stat, confidenceIntervalZ*pairedStdev/math.Sqrt(float64(paired.n)))
 		}
 
 		calcWeightResults(baselinePlayer.Dps, modPlayerLow.Dps, modPlayerHigh.Dps, &result.Dps)
@@ -257,8 +287,10 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 			}
 			mean := weightResults.Weights.Get(stat) / weightResults.Weights.Stats[refStat]
 			stdev := weightResults.WeightsStdev.Get(stat) / math.Abs(weightResults.Weights.Stats[refStat])
+			ci := weightResults.WeightsCI.Get(stat) / math.Abs(weightResults.Weights.Stats[refStat])
 			weightResults.EpValues.AddStat(stat, mean)
 			weightResults.EpValuesStdev.AddStat(stat, stdev)
+			weightResults.EpValuesCI.AddStat(stat, ci)
 		}
 
 		calcEpResults(&result.Dps, referenceStat)
@@ -276,68 +308,222 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatWeightsResult {
 	requestData := buildStatWeightRequests(request)
 
-	var iterationsTotal int32 = requestData.BaseRequest.SimOptions.Iterations
-	var iterationsDone int32 = 0
-	var simsTotal int32 = 1
-	var simsCompleted int32 = 0
+	simFunc := runSimConcurrent
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() || request.SimOptions.IsTest {
+		simFunc = RunSim
+	}
+
+	if request.Adaptive != nil {
+		return runAdaptiveStatWeights(requestData, request.Adaptive, simFunc, progress, signals)
+	}
+
+	sw := newStatWeightsRunner(requestData, simFunc, progress, signals)
+	calcRequest, err := sw.runBatch()
+	if err != nil {
+		return &proto.StatWeightsResult{Error: err}
+	}
+	return computeStatWeights(calcRequest)
+}
+
+// Keeps running batches of stat weight sims until the EPs of every weighed stat
+// are within the requested confidence interval, or the time budget is used up.
+//
+// Every sim within a batch uses the same seed, so the baseline and each
+// stat-shifted sim see the same random numbers and their per-iteration
+// differences stay low-variance. Iterations are seeded with consecutive
+// seeds, so each batch starts where the previous one's seeds end, and the
+// per-iteration values of all batches are pooled together.
+func runAdaptiveStatWeights(requestData *proto.StatWeightRequestsData, options *proto.AdaptiveStatWeightsOptions, simFunc statWeightsSimFunc, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatWeightsResult {
+	timeBudget := time.Duration(options.MaxTimeSeconds * float64(time.Second))
+	if timeBudget <= 0 {
+		timeBudget = defaultAdaptiveStatWeightsTime
+	}
+	startTime := time.Now()
+
+	baseSeed := requestData.BaseRequest.SimOptions.RandomSeed
+	batchIterations := int64(requestData.BaseRequest.SimOptions.Iterations)
+	sw := newStatWeightsRunner(requestData, simFunc, progress, signals)
+
+	var pooled *proto.StatWeightsCalcRequest
+	var result *proto.StatWeightsResult
+	for batch := int64(0); ; batch++ {
+		setStatWeightsSeed(requestData, baseSeed+batch*batchIterations)
+
+		calcRequest, err := sw.runBatch()
+		if err != nil {
+			return &proto.StatWeightsResult{Error: err}
+		}
+		if pooled == nil {
+			pooled = calcRequest
+		} else {
+			mergeStatWeightsCalcRequests(pooled, calcRequest)
+		}
+
+		result = computeStatWeights(pooled)
+		if result.Error != nil {
+			return result
+		}
+		result.Iterations = requestData.BaseRequest.SimOptions.Iterations * int32(batch+1)
+		result.Converged = statWeightsConverged(result, pooled, options)
 
+		if result.Converged || time.Since(startTime) >= timeBudget {
+			return result
+		}
+	}
+}
+
+func setStatWeightsSeed(requestData *proto.StatWeightRequestsData, seed int64) {
+	requestData.BaseRequest.SimOptions.RandomSeed = seed
 	for _, reqData := range requestData.StatSimRequests {
-		iterationsTotal += reqData.RequestLow.SimOptions.Iterations
-		iterationsTotal += reqData.RequestHigh.SimOptions.Iterations
-		simsTotal += 2
-	}
-
-	waitForResult := func(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
-		var lastCompleted int32 = 0
-		for metrics := range srcProgressChannel {
-			iterationsDone += metrics.CompletedIterations - lastCompleted
-			lastCompleted = metrics.CompletedIterations
-
-			if progress != nil {
-				progress <- &proto.ProgressMetrics{
-					TotalIterations:     iterationsTotal,
-					CompletedIterations: iterationsDone,
-					CompletedSims:       simsCompleted,
-					TotalSims:           simsTotal,
-				}
-			}
+		reqData.RequestLow.SimOptions.RandomSeed = seed
+		reqData.RequestHigh.SimOptions.RandomSeed = seed
+	}
+}
+
+// Whether the EP confidence intervals of every weighed stat are below the
+// threshold, for the metric selected in the options.
+func statWeightsConverged(result *proto.StatWeightsResult, calcRequest *proto.StatWeightsCalcRequest, options *proto.AdaptiveStatWeightsOptions) bool {
+	var values *proto.StatWeightValues
+	switch options.Metric {
+	case proto.StatWeightsMetric_StatWeightsMetricHps:
+		values = result.Hps
+	case proto.StatWeightsMetric_StatWeightsMetricTps:
+		values = result.Tps
+	case proto.StatWeightsMetric_StatWeightsMetricDtps:
+		values = result.Dtps
+	case proto.StatWeightsMetric_StatWeightsMetricTmi:
+		values = result.Tmi
+	default:
+		values = result.Dps
+	}
+
+	for _, statResult := range calcRequest.StatSimResults {
+		stat := stats.UnitStatFromIdx(int(statResult.StatData.UnitStat))
+		if stat.GetFromStatsProto(values.EpValuesConfidenceInterval) > options.MaxEpConfidenceInterval {
+			return false
+		}
+	}
+	return true
+}
+
+// Appends the per-iteration values of another batch to the pooled results.
+func mergeStatWeightsCalcRequests(dst *proto.StatWeightsCalcRequest, src *proto.StatWeightsCalcRequest) {
+	mergeStatWeightsSimResults(dst.BaseResult, src.BaseResult)
+	for i, statResult := range dst.StatSimResults {
+		mergeStatWeightsSimResults(statResult.ResultLow, src.StatSimResults[i].ResultLow)
+		mergeStatWeightsSimResults(statResult.ResultHigh, src.StatSimResults[i].ResultHigh)
+	}
+}
+
+// Merges the metrics of the first player, which are the only ones stat weights use.
+func mergeStatWeightsSimResults(dst *proto.RaidSimResult, src *proto.RaidSimResult) {
+	dstPlayer := dst.RaidMetrics.Parties[0].Players[0]
+	srcPlayer := src.RaidMetrics.Parties[0].Players[0]
+
+	dstIterations := float64(len(dstPlayer.Dps.AllValues))
+	srcIterations := float64(len(srcPlayer.Dps.AllValues))
+	dstPlayer.ChanceOfDeath = (dstPlayer.ChanceOfDeath*dstIterations + srcPlayer.ChanceOfDeath*srcIterations) / (dstIterations + srcIterations)
+
+	mergeDistributionValues(dstPlayer.Dps, srcPlayer.Dps)
+	mergeDistributionValues(dstPlayer.Hps, srcPlayer.Hps)
+	mergeDistributionValues(dstPlayer.Threat, srcPlayer.Threat)
+	mergeDistributionValues(dstPlayer.Dtps, srcPlayer.Dtps)
+	mergeDistributionValues(dstPlayer.Tmi, srcPlayer.Tmi)
+}
+
+func mergeDistributionValues(dst *proto.DistributionMetrics, src *proto.DistributionMetrics) {
+	dstIterations := float64(len(dst.AllValues))
+	srcIterations := float64(len(src.AllValues))
+	if dstIterations+srcIterations == 0 {
+		return
+	}
+	dst.Avg = (dst.Avg*dstIterations + src.Avg*srcIterations) / (dstIterations + srcIterations)
+	dst.AllValues = append(dst.AllValues, src.AllValues...)
+}
+
+type statWeightsSimFunc func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, simsignals.Signals) *proto.RaidSimResult
 
-			if metrics.FinalRaidResult != nil {
-				simsCompleted++
-				return metrics.FinalRaidResult
+// Runs the baseline and stat-shifted sims of a stat weights request, while
+// reporting combined progress for all of them.
+type statWeightsRunner struct {
+	requestData *proto.StatWeightRequestsData
+	simFunc     statWeightsSimFunc
+	progress    chan *proto.ProgressMetrics
+	signals     simsignals.Signals
+
+	iterationsTotal int32
+	iterationsDone  int32
+	simsTotal       int32
+	simsCompleted   int32
+}
+
+func newStatWeightsRunner(requestData *proto.StatWeightRequestsData, simFunc statWeightsSimFunc, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *statWeightsRunner {
+	return &statWeightsRunner{
+		requestData: requestData,
+		simFunc:     simFunc,
+		progress:    progress,
+		signals:     signals,
+	}
+}
+
+func (sw *statWeightsRunner) waitForResult(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
+	var lastCompleted int32 = 0
+	for metrics := range srcProgressChannel {
+		sw.iterationsDone += metrics.CompletedIterations - lastCompleted
+		lastCompleted = metrics.CompletedIterations
+
+		if sw.progress != nil {
+			sw.progress <- &proto.ProgressMetrics{
+				TotalIterations:     sw.iterationsTotal,
+				CompletedIterations: sw.iterationsDone,
+				CompletedSims:       sw.simsCompleted,
+				TotalSims:           sw.simsTotal,
 			}
 		}
-		return nil
+
+		if metrics.FinalRaidResult != nil {
+			sw.simsCompleted++
+			return metrics.FinalRaidResult
+		}
 	}
+	return nil
+}
 
-	simFunc := runSimConcurrent
-	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
-	if IsRunningInWasm() || request.SimOptions.IsTest {
-		simFunc = RunSim
+func (sw *statWeightsRunner) runSim(request *proto.RaidSimRequest) *proto.RaidSimResult {
+	simProgress := make(chan *proto.ProgressMetrics, 100)
+	go sw.simFunc(request, simProgress, sw.signals)
+	return sw.waitForResult(simProgress)
+}
+
+// Runs one batch of the baseline and every stat-shifted sim.
+func (sw *statWeightsRunner) runBatch() (*proto.StatWeightsCalcRequest, *proto.ErrorOutcome) {
+	requestData := sw.requestData
+
+	sw.iterationsTotal += requestData.BaseRequest.SimOptions.Iterations
+	sw.simsTotal++
+	for _, reqData := range requestData.StatSimRequests {
+		sw.iterationsTotal += reqData.RequestLow.SimOptions.Iterations
+		sw.iterationsTotal += reqData.RequestHigh.SimOptions.Iterations
+		sw.simsTotal += 2
 	}
 
-	baseProgress := make(chan *proto.ProgressMetrics, 100)
-	go simFunc(requestData.BaseRequest, baseProgress, signals)
-	baselineResult := waitForResult(baseProgress)
+	baselineResult := sw.runSim(requestData.BaseRequest)
 	if baselineResult.Error != nil {
-		return &proto.StatWeightsResult{Error: baselineResult.Error}
+		return nil, baselineResult.Error
 	}
 
 	statResults := []*proto.StatWeightsStatResultData{}
 
 	for _, reqData := range requestData.StatSimRequests {
-		lowProgress := make(chan *proto.ProgressMetrics, 100)
-		go simFunc(reqData.RequestLow, lowProgress, signals)
-		lowRes := waitForResult(lowProgress)
+		lowRes := sw.runSim(reqData.RequestLow)
 		if lowRes.Error != nil {
-			return &proto.StatWeightsResult{Error: lowRes.Error}
+			return nil, lowRes.Error
 		}
 
-		highProgress := make(chan *proto.ProgressMetrics, 100)
-		go simFunc(reqData.RequestHigh, highProgress, signals)
-		highRes := waitForResult(highProgress)
+		highRes := sw.runSim(reqData.RequestHigh)
 		if highRes.Error != nil {
-			return &proto.StatWeightsResult{Error: highRes.Error}
+			return nil, highRes.Error
 		}
 
 		statResults = append(statResults, &proto.StatWeightsStatResultData{
@@ -347,9 +533,9 @@ func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.Prog
 		})
 	}
 
-	return computeStatWeights(&proto.StatWeightsCalcRequest{
+	return &proto.StatWeightsCalcRequest{
 		BaseResult:      baselineResult,
 		EpReferenceStat: requestData.EpReferenceStat,
 		StatSimResults:  statResults,
-	})
+	}, nil
 }
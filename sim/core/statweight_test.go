@@ -0,0 +1,197 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+func statWeightsTestResult(dps []float64) *proto.RaidSimResult {
+	distribution := func() *proto.DistributionMetrics {
+		values := append([]float64{}, dps...)
+		avg := 0.0
+		for _, v := range values {
+			avg += v / float64(len(values))
+		}
+		return &proto.DistributionMetrics{Avg: avg, AllValues: values}
+	}
+
+	return &proto.RaidSimResult{
+		RaidMetrics: &proto.RaidMetrics{
+			Parties: []*proto.PartyMetrics{{
+				Players: []*proto.UnitMetrics{{
+					Dps:    distribution(),
+					Hps:    distribution(),
+					Threat: distribution(),
+					Dtps:   distribution(),
+					Tmi:    distribution(),
+				}},
+			}},
+		},
+	}
+}
+
+// Builds a calc request for one batch, where the reference stat is worth 1
+// dps and the other stat is worth 2 dps per point. Each iteration adds its
+// noise to the other stat's low and high sims, or shared noise to both in the
+// same direction. Batches are told apart by their first seed, which shifts the
+// baseline.
+func statWeightsTestCalcRequest(firstSeed int, noise []float64, sharedNoise []float64) *proto.StatWeightsCalcRequest {
+	baseline := make([]float64, len(noise))
+	refLow, refHigh := make([]float64, len(noise)), make([]float64, len(noise))
+	statLow, statHigh := make([]float64, len(noise)), make([]float64, len(noise))
+	for i, n := range noise {
+		baseline[i] = 1000 + float64(firstSeed+i)
+		refLow[i] = baseline[i] - 1
+		refHigh[i] = baseline[i] + 1
+		statLow[i] = baseline[i] - 2 - n + sharedNoise[i]
+		statHigh[i] = baseline[i] + 2 + n + sharedNoise[i]
+	}
+
+	return &proto.StatWeightsCalcRequest{
+		BaseResult:      statWeightsTestResult(baseline),
+		EpReferenceStat: proto.Stat_StatAttackPower,
+		StatSimResults: []*proto.StatWeightsStatResultData{
+			{
+				StatData:   &proto.StatWeightsStatData{UnitStat: int32(stats.AttackPower), ModLow: -1, ModHigh: 1},
+				ResultLow:  statWeightsTestResult(refLow),
+				ResultHigh: statWeightsTestResult(refHigh),
+			},
+			{
+				StatData:   &proto.StatWeightsStatData{UnitStat: int32(stats.Strength), ModLow: -1, ModHigh: 1},
+				ResultLow:  statWeightsTestResult(statLow),
+				ResultHigh: statWeightsTestResult(statHigh),
+			},
+		},
+	}
+}
+
+func TestStatWeightsConfidenceInterval(t *testing.T) {
+	noSharedNoise := []float64{0, 0, 0, 0}
+	calcRequest := statWeightsTestCalcRequest(0, []float64{1, -1, 1, -1}, noSharedNoise)
+
+	result := computeStatWeights(calcRequest)
+	ep := result.Dps.EpValues.Stats[stats.Strength]
+	ci := result.Dps.EpValuesConfidenceInterval.Stats[stats.Strength]
+	if ep != 2 {
+		t.Fatalf("Expected an EP of 2, got %0.3f", ep)
+	}
+	// The 4 paired per-iteration weights are 3 or 1, so the stdev is 1.
+	if expected := confidenceIntervalZ / math.Sqrt(4); math.Abs(ci-expected) > 1e-9 {
+		t.Fatalf("Expected a confidence interval of %0.3f, got %0.3f", expected, ci)
+	}
+
+	options := &proto.AdaptiveStatWeightsOptions{MaxEpConfidenceInterval: 0.5}
+	if statWeightsConverged(result, calcRequest, options) {
+		t.Fatalf("Should not converge with a confidence interval of %0.3f", ci)
+	}
+
+	// Pool batches covering the following seed ranges, each with its own noise.
+	batchNoise := [][]float64{
+		{0.5, -0.5, 1, -1},
+		{1, -1, 0, 0},
+		{0.5, -0.5, 1, -1},
+	}
+	pairedWeights := []float64{3, 1, 3, 1}
+	for i, noise := range batchNoise {
+		mergeStatWeightsCalcRequests(calcRequest, statWeightsTestCalcRequest((i+1)*len(noise), noise, noSharedNoise))
+		for _, n := range noise {
+			pairedWeights = append(pairedWeights, 2+n)
+		}
+	}
+	if numValues := len(calcRequest.BaseResult.RaidMetrics.Parties[0].Players[0].Dps.AllValues); numValues != 16 {
+		t.Fatalf("Expected 16 pooled iterations, got %d", numValues)
+	}
+
+	var expectedStdev float64
+	for _, w := range pairedWeights {
+		expectedStdev += (w - 2) * (w - 2) / float64(len(pairedWeights))
+	}
+	expectedStdev = math.Sqrt(expectedStdev)
+
+	result = computeStatWeights(calcRequest)
+	pooledCI := result.Dps.EpValuesConfidenceInterval.Stats[stats.Strength]
+	if expected := confidenceIntervalZ * expectedStdev / math.Sqrt(16); math.Abs(pooledCI-expected) > 1e-9 {
+		t.Fatalf("Expected a pooled confidence interval of %0.3f, got %0.3f", expected, pooledCI)
+	}
+	if ep := result.Dps.EpValues.Stats[stats.Strength]; math.Abs(ep-2) > 1e-9 {
+		t.Fatalf("Expected the pooled EP to stay at 2, got %0.3f", ep)
+	}
+	if !statWeightsConverged(result, calcRequest, options) {
+		t.Fatalf("Should converge with a confidence interval of %0.3f", pooledCI)
+	}
+}
+
+func TestStatWeightsConfidenceIntervalIsPaired(t *testing.T) {
+	// Noise which moves the low and high sims of an iteration the same way
+	// cancels out of the paired weights.
+	calcRequest := statWeightsTestCalcRequest(0, []float64{0, 0, 0, 0}, []float64{5, -5, 3, -3})
+
+	result := computeStatWeights(calcRequest)
+	if ci := result.Dps.EpValuesConfidenceInterval.Stats[stats.Strength]; ci > 1e-9 {
+		t.Fatalf("Expected no uncertainty from noise shared by the low and high sims, got a confidence interval of %0.3f", ci)
+	}
+	if stdev := result.Dps.EpValuesStdev.Stats[stats.Strength]; stdev == 0 {
+		t.Fatalf("Expected the unpaired stdev to still include the shared noise")
+	}
+}
+
+func TestAdaptiveStatWeightsSeeds(t *testing.T) {
+	request := &proto.StatWeightsRequest{
+		Player: &proto.Player{
+			Name:      "Caster",
+			Class:     proto.Class_ClassShaman,
+			Consumes:  &proto.Consumes{},
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		},
+		Encounter:       &proto.Encounter{Duration: 60},
+		SimOptions:      &proto.SimOptions{Iterations: 4, RandomSeed: 1000},
+		StatsToWeigh:    []proto.Stat{proto.Stat_StatStrength},
+		EpReferenceStat: proto.Stat_StatAttackPower,
+	}
+	requestData := buildStatWeightRequests(request)
+
+	// Strength is worth 2 dps per point, give or take 1 depending on the
+	// iteration's seed, and the reference stat is worth exactly 1.
+	var batchSeeds []int64
+	fakeSim := func(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, _ simsignals.Signals) *proto.RaidSimResult {
+		bonusStats := request.Raid.Parties[0].Players[0].BonusStats.Stats
+		seed := request.SimOptions.RandomSeed
+		if bonusStats[stats.Strength] == 0 && bonusStats[stats.AttackPower] == 0 {
+			batchSeeds = append(batchSeeds, seed)
+		}
+
+		dps := make([]float64, request.SimOptions.Iterations)
+		for i := range dps {
+			iterationSeed := seed + int64(i)
+			noise := TernaryFloat64(iterationSeed%2 == 0, 1, -1)
+			dps[i] = 1000 + float64(iterationSeed) + bonusStats[stats.AttackPower] + bonusStats[stats.Strength]*(2+noise)
+		}
+		result := statWeightsTestResult(dps)
+		progress <- &proto.ProgressMetrics{FinalRaidResult: result}
+		return result
+	}
+
+	// 2 iterations per batch give a confidence interval of 1.96/sqrt(2 * batches),
+	// which is below 0.9 after 3 batches.
+	options := &proto.AdaptiveStatWeightsOptions{MaxEpConfidenceInterval: 0.9, MaxTimeSeconds: 60}
+	result := runAdaptiveStatWeights(requestData, options, fakeSim, nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatal(result.Error.Message)
+	}
+	if !result.Converged || result.Iterations != 6 {
+		t.Fatalf("Expected to converge after 6 iterations, got %d (converged = %t)", result.Iterations, result.Converged)
+	}
+
+	// Each batch continues with the seeds after the previous batch's iterations.
+	expectedSeeds := []int64{1000, 1002, 1004}
+	if !slices.Equal(batchSeeds, expectedSeeds) {
+		t.Fatalf("Expected batches to start at seeds %v, got %v", expectedSeeds, batchSeeds)
+	}
+}
@@ -13,6 +13,11 @@ type Encounter struct {
 	Targets           []*Target
 	TargetUnits       []*Unit
 
+	// Targets which are currently in the fight, in index order. Targets with a
+	// spawn time, despawn time or health-based death enter and leave this list
+	// during the iteration.
+	ActiveTargetUnits []*Unit
+
 	ExecuteProportion_20 float64
 	ExecuteProportion_25 float64
 	ExecuteProportion_35 float64
@@ -24,6 +29,12 @@ type Encounter struct {
 	// In health fight: set to true until we get something to base on
 	DurationIsEstimate bool
 
+	// Scripted events which force players to move.
+	MovementEvents []*proto.MovementEvent
+
+	// Scripted windows in which players can't act normally.
+	DowntimeEvents []*DowntimeEvent
+
 	// Value to multiply by, for damage spells which are subject to the aoe cap.
 	aoeCapMultiplier float64
 }
@@ -38,6 +49,7 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		ExecuteProportion_20: max(options.ExecuteProportion_20, 0),
 		ExecuteProportion_25: max(options.ExecuteProportion_25, 0),
 		ExecuteProportion_35: max(options.ExecuteProportion_35, 0),
+		MovementEvents:       options.MovementEvents,
 		Targets:              []*Target{},
 	}
 	// If UseHealth is set, we use the sum of targets health.
@@ -63,6 +75,9 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
 	}
 
+	encounter.ActiveTargetUnits = append([]*Unit{}, encounter.TargetUnits...)
+	encounter.DowntimeEvents = newDowntimeEvents(options.DowntimeEvents, encounter.TargetUnits)
+
 	if encounter.EndFightAtHealth > 0 {
 		// Until we pre-sim set duration to 10m
 		encounter.Duration = time.Minute * 10
@@ -78,7 +93,17 @@ func (encounter *Encounter) AOECapMultiplier() float64 {
 	return encounter.aoeCapMultiplier
 }
 func (encounter *Encounter) updateAOECapMultiplier() {
-	encounter.aoeCapMultiplier = min(10/float64(len(encounter.Targets)), 1)
+	encounter.aoeCapMultiplier = min(10/float64(max(len(encounter.ActiveTargetUnits), 1)), 1)
+}
+
+func (encounter *Encounter) updateActiveTargets() {
+	encounter.ActiveTargetUnits = encounter.ActiveTargetUnits[:0]
+	for _, targetUnit := range encounter.TargetUnits {
+		if targetUnit.enabled {
+			encounter.ActiveTargetUnits = append(encounter.ActiveTargetUnits, targetUnit)
+		}
+	}
+	encounter.updateAOECapMultiplier()
 }
 
 func (encounter *Encounter) doneIteration(sim *Simulation) {
@@ -107,6 +132,18 @@ type Target struct {
 	Unit
 
 	AI TargetAI
+
+	// When this target enters and leaves the fight. A zero SpawnTime means the
+	// target is present from the start, a zero DespawnTime means it never leaves.
+	SpawnTime   time.Duration
+	DespawnTime time.Duration
+
+	// Whether this target dies after taking its Health stat worth of damage.
+	DiesAtZeroHealth bool
+	damageTaken      float64
+
+	// Threat generated on this target this iteration, indexed by UnitIndex.
+	threatTable []float64
 }
 
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
@@ -127,8 +164,14 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 			PseudoStats: stats.NewPseudoStats(),
 			Metrics:     NewUnitMetrics(),
 
+			StartPosition:    PositionFromProto(options.Position),
+			Position:         PositionFromProto(options.Position),
+
 			StatDependencyManager: stats.NewStatDependencyManager(),
 		},
+		SpawnTime:        DurationFromSeconds(options.SpawnTime),
+		DespawnTime:      DurationFromSeconds(options.DespawnTime),
+		DiesAtZeroHealth: options.DiesAtZeroHealth,
 	}
 	defaultRaidBossLevel := int32(CharacterMaxLevel + 3)
 	target.GCD = target.NewTimer()
@@ -157,12 +200,100 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 
 func (target *Target) Reset(sim *Simulation) {
 	target.Unit.reset(sim, nil)
-	target.SetGCDTimer(sim, 0)
+	target.damageTaken = 0
+	target.resetThreat()
+
+	if target.SpawnTime > 0 {
+		target.enabled = false
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     target.SpawnTime,
+			OnAction: target.Spawn,
+		})
+	} else {
+		target.SetGCDTimer(sim, 0)
+	}
+
+	if target.DespawnTime > 0 {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt:     target.DespawnTime,
+			OnAction: target.Despawn,
+		})
+	}
+
 	if target.AI != nil {
 		target.AI.Reset(sim)
 	}
 }
 
+// Brings this target into the fight. Raid members without a live target
+// switch to it.
+func (target *Target) Spawn(sim *Simulation) {
+	if target.enabled {
+		return
+	}
+
+	target.enabled = true
+	target.Env.Encounter.updateActiveTargets()
+
+	if sim.Log != nil {
+		target.Log(sim, "Spawned")
+	}
+
+	target.AutoAttacks.startPull(sim)
+	target.SetGCDTimer(sim, sim.CurrentTime)
+
+	for _, unit := range target.Env.Raid.AllUnits {
+		if unit.CurrentTarget != nil && unit.CurrentTarget.Type == EnemyUnit && !unit.CurrentTarget.enabled {
+			unit.CurrentTarget = &target.Unit
+			unit.updateDistanceFromTarget()
+		}
+	}
+}
+
+// Removes this target from the fight, either because it died or because it
+// left. Its temporary auras expire, permanent ones (e.g. raid debuffs) stay up
+// for when it comes back. Any damage it would take afterwards is lost. Raid
+// members targeting it switch to the next live target, if there is one.
+func (target *Target) Despawn(sim *Simulation) {
+	if !target.enabled {
+		return
+	}
+
+	target.enabled = false
+	target.Env.Encounter.updateActiveTargets()
+
+	if sim.Log != nil {
+		target.Log(sim, "Despawned")
+	}
+
+	target.AutoAttacks.CancelAutoSwing(sim)
+	if target.gcdAction != nil {
+		target.CancelGCDTimer(sim)
+	}
+	target.Hardcast = Hardcast{}
+	target.auraTracker.expireAll(sim)
+
+	activeTargets := target.Env.Encounter.ActiveTargetUnits
+	if len(activeTargets) == 0 {
+		return
+	}
+	for _, unit := range target.Env.Raid.AllUnits {
+		if unit.CurrentTarget == &target.Unit {
+			unit.CurrentTarget = activeTargets[0]
+			unit.updateDistanceFromTarget()
+		}
+	}
+}
+
+// Tracks damage for time to die estimates and health-based deaths. Only called
+// for targets which die at zero health, the others don't need it.
+func (target *Target) onDamageTaken(sim *Simulation, damage float64) {
+	target.damageTaken += damage
+	if target.damageTaken >= target.GetStat(stats.Health) {
+		target.Despawn(sim)
+	}
+}
+
 func (target *Target) NextTarget() *Target {
 	nextIndex := target.Index + 1
 	if nextIndex >= target.Env.GetNumTargets() {
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+func setupAddsSim() *Simulation {
+	bossStats := stats.Stats{}
+	bossStats[stats.Health] = 500
+
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{
+			RandomSeed: 100,
+		},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Caster",
+							Class:     proto.Class_ClassShaman,
+							Consumes:  &proto.Consumes{},
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_ElementalShaman{},
+							Equipment: &proto.EquipmentSpec{},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "boss", Level: 63, Stats: bossStats[:], DiesAtZeroHealth: true},
+				{Name: "add", Level: 60, SpawnTime: 10, DespawnTime: 30},
+			},
+			Duration: 180,
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	return sim
+}
+
+func runUntil(sim *Simulation, until time.Duration) {
+	for sim.CurrentTime < until {
+		if sim.Step() {
+			return
+		}
+	}
+}
+
+func TestTargetSpawnAndDespawn(t *testing.T) {
+	sim := setupAddsSim()
+	fa := sim.Raid.Parties[0].Players[0].(*FakeAgent)
+	boss := sim.Encounter.TargetUnits[0]
+	add := sim.Encounter.TargetUnits[1]
+
+	if numTargets := sim.GetNumActiveTargets(); numTargets != 1 {
+		t.Fatalf("Expected 1 active target before the add spawns, got %d", numTargets)
+	}
+
+	fa.Spell.CalcAndDealDamage(sim, add, 100, fa.Spell.OutcomeAlwaysHit)
+	if damage := fa.Spell.SpellMetrics[add.UnitIndex].TotalDamage; damage != 0 {
+		t.Fatalf("Damage against an add that has not spawned should be lost, got %0.3f", damage)
+	}
+
+	fa.Spell.CalcAndDealDamage(sim, boss, 1000, fa.Spell.OutcomeAlwaysHit)
+	if boss.IsEnabled() {
+		t.Fatalf("Boss should die after taking its health worth of damage")
+	}
+	if numTargets := sim.GetNumActiveTargets(); numTargets != 0 {
+		t.Fatalf("Expected no active targets after the boss died, got %d", numTargets)
+	}
+
+	runUntil(sim, time.Second*10)
+	if !add.IsEnabled() || sim.GetNumActiveTargets() != 1 {
+		t.Fatalf("Add should have spawned at 10s")
+	}
+	if fa.CurrentTarget != add {
+		t.Fatalf("Player should switch to the add once their target is dead")
+	}
+
+	fa.Spell.CalcAndDealDamage(sim, add, 100, fa.Spell.OutcomeAlwaysHit)
+	if damage := fa.Spell.SpellMetrics[add.UnitIndex].TotalDamage; damage == 0 {
+		t.Fatalf("Damage against a live add should be recorded")
+	}
+
+	runUntil(sim, time.Second*30)
+	if add.IsEnabled() || sim.GetNumActiveTargets() != 0 {
+		t.Fatalf("Add should have despawned at 30s")
+	}
+
+	sim.Cleanup()
+	sim.Reset()
+	if !boss.IsEnabled() || add.IsEnabled() || fa.CurrentTarget != boss {
+		t.Fatalf("Reset should restore the starting targets")
+	}
+}
+
+func TestTargetDespawnKeepsPermanentDebuffs(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Level:     60,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Debuffs: &proto.Debuffs{FaerieFire: true},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	target := sim.Encounter.Targets[0]
+	faerieFire := target.GetAura("Faerie Fire")
+	if !faerieFire.IsActive() {
+		t.Fatalf("Expected Faerie Fire to be active from the start")
+	}
+
+	target.Despawn(sim)
+	target.Spawn(sim)
+	if !faerieFire.IsActive() {
+		t.Errorf("Expected Faerie Fire to still be active after the target despawned and spawned again")
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+// How much of the current victim's threat another unit needs to pull aggro,
+// depending on whether it's in melee range of the target.
+const (
+	MeleeAggroThreshold  = 1.1
+	RangedAggroThreshold = 1.3
+)
+
+func (target *Target) resetThreat() {
+	if target.threatTable == nil {
+		target.threatTable = make([]float64, len(target.Env.AllUnits))
+	} else {
+		clear(target.threatTable)
+	}
+	target.CurrentTarget = target.defaultTarget
+}
+
+// Returns the threat the unit has generated on this target so far this iteration.
+func (target *Target) Threat(unit *Unit) float64 {
+	return target.threatTable[unit.UnitIndex]
+}
+
+// Returns the unit's threat as a fraction of the threat held by whoever this
+// target is attacking, or 0 if it isn't attacking anyone.
+func (target *Target) ThreatPercentOfTank(unit *Unit) float64 {
+	victim := target.CurrentTarget
+	if victim == nil {
+		return 0
+	}
+	if victim == unit {
+		return 1
+	}
+	victimThreat := target.threatTable[victim.UnitIndex]
+	if victimThreat <= 0 {
+		return TernaryFloat64(target.threatTable[unit.UnitIndex] > 0, 1, 0)
+	}
+	return target.threatTable[unit.UnitIndex] / victimThreat
+}
+
+// Adds threat from the unit to this target's threat table. If the target is
+// attacking someone and the unit passes the aggro threshold over them, the
+// target switches to the unit.
+func (target *Target) AddThreat(sim *Simulation, unit *Unit, amount float64) {
+	if amount == 0 || unit.Type == EnemyUnit {
+		return
+	}
+	target.threatTable[unit.UnitIndex] += amount
+
+	victim := target.CurrentTarget
+	if victim == nil || victim == unit || !target.enabled {
+		return
+	}
+
+	threshold := RangedAggroThreshold
+	if unit.DistanceTo(&target.Unit) <= MaxMeleeAttackDistance {
+		threshold = MeleeAggroThreshold
+	}
+	if target.threatTable[unit.UnitIndex] > target.threatTable[victim.UnitIndex]*threshold {
+		target.CurrentTarget = unit
+		if sim.Log != nil {
+			target.Log(sim, "%s pulled aggro from %s (%0.3f threat vs %0.3f).", unit.Label, victim.Label, target.threatTable[unit.UnitIndex], target.threatTable[victim.UnitIndex])
+		}
+	}
+}
+
+// Adds threat to the table of the enemy unit, if it is one.
+func (unit *Unit) addThreatTo(sim *Simulation, enemy *Unit, amount float64) {
+	if enemy.Type != EnemyUnit {
+		return
+	}
+	unit.Env.Encounter.Targets[enemy.Index].AddThreat(sim, unit, amount)
+}
+
+// Splits threat evenly between all targets in the fight, e.g. for healing.
+func (unit *Unit) addThreatToActiveTargets(sim *Simulation, amount float64) {
+	activeTargets := unit.Env.Encounter.ActiveTargetUnits
+	if len(activeTargets) == 0 {
+		return
+	}
+	amount /= float64(len(activeTargets))
+	for _, target := range activeTargets {
+		unit.addThreatTo(sim, target, amount)
+	}
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+)
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_Warrior{},
+		proto.Spec_SpecWarrior,
+		func(char *Character, _ *proto.Player) Agent {
+			return &rageTestAgent{Character: *char}
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_Warrior)
+		},
+	)
+}
+
+// A warrior stand-in with a rage bar, for testing threat from rage gains.
+type rageTestAgent struct {
+	Character
+	bloodrageMetrics *ResourceMetrics
+}
+
+func (agent *rageTestAgent) GetCharacter() *Character { return &agent.Character }
+func (agent *rageTestAgent) Initialize() {
+	agent.EnableRageBar(RageBarOptions{DamageDealtMultiplier: 1, DamageTakenMultiplier: 1})
+	agent.bloodrageMetrics = agent.NewRageMetrics(ActionID{SpellID: 2687})
+}
+func (agent *rageTestAgent) ApplyTalents()            {}
+func (agent *rageTestAgent) ApplyRunes()              {}
+func (agent *rageTestAgent) Reset(_ *Simulation)      {}
+func (agent *rageTestAgent) OnGCDReady(_ *Simulation) {}
+
+func TestThreatTableAggroSwitch(t *testing.T) {
+	player := func(name string, distance float64) *proto.Player {
+		return &proto.Player{
+			Name:               name,
+			Class:              proto.Class_ClassShaman,
+			Consumes:           &proto.Consumes{},
+			Buffs:              &proto.IndividualBuffs{},
+			Spec:               &proto.Player_ElementalShaman{},
+			Equipment:          &proto.EquipmentSpec{},
+			DistanceFromTarget: distance,
+		}
+	}
+
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{player("Tank", 5), player("Melee", 5), player("Caster", 30)},
+				Buffs:   &proto.PartyBuffs{},
+			}},
+			Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "target", Level: 63}},
+			Duration: 10,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	target := sim.Encounter.Targets[0]
+	tank := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	melee := &sim.Raid.Parties[0].Players[1].GetCharacter().Unit
+	caster := &sim.Raid.Parties[0].Players[2].GetCharacter().Unit
+
+	if target.CurrentTarget != tank {
+		t.Fatalf("Expected the target to start on the tank, got %s", target.CurrentTarget.Label)
+	}
+
+	target.AddThreat(sim, tank, 1000)
+	target.AddThreat(sim, caster, 1250)
+	target.AddThreat(sim, melee, 1050)
+	if target.CurrentTarget != tank {
+		t.Fatalf("Expected the tank to keep aggro below the thresholds, got %s", target.CurrentTarget.Label)
+	}
+	if percent := target.ThreatPercentOfTank(caster); percent != 1.25 {
+		t.Errorf("Expected the caster to be at 125%% of the tank's threat, got %f", percent)
+	}
+
+	target.AddThreat(sim, caster, 100)
+	if target.CurrentTarget != caster {
+		t.Fatalf("Expected the caster to pull aggro above 130%%, got %s", target.CurrentTarget.Label)
+	}
+
+	// The melee player now needs 110% of the caster's threat.
+	target.AddThreat(sim, melee, 400)
+	if target.CurrentTarget != caster {
+		t.Fatalf("Expected the caster to keep aggro, got %s", target.CurrentTarget.Label)
+	}
+	target.AddThreat(sim, melee, 100)
+	if target.CurrentTarget != melee {
+		t.Fatalf("Expected the melee player to pull aggro above 110%%, got %s", target.CurrentTarget.Label)
+	}
+
+	sim.Cleanup()
+	sim.reset()
+	if target.CurrentTarget != tank || target.Threat(melee) != 0 {
+		t.Errorf("Expected the threat table to be reset along with the sim")
+	}
+}
+
+func TestThreatTableAggroThresholdUsesDistanceToThreatenedTarget(t *testing.T) {
+	player := func(name string, position *proto.Position) *proto.Player {
+		return &proto.Player{
+			Name:      name,
+			Class:     proto.Class_ClassShaman,
+			Consumes:  &proto.Consumes{},
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+			Position:  position,
+		}
+	}
+
+	// Both players attack the first target. The melee player stands next to
+	// it, far from the second target; the caster stands next to the second.
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{
+					player("Tank", &proto.Position{X: 15}),
+					player("Melee", &proto.Position{X: 5}),
+					player("Caster", &proto.Position{X: 32}),
+				},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "near", Level: 63},
+				{Name: "far", Level: 63, Position: &proto.Position{X: 30}},
+			},
+			Duration: 10,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	far := sim.Encounter.Targets[1]
+	tank := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	melee := &sim.Raid.Parties[0].Players[1].GetCharacter().Unit
+	caster := &sim.Raid.Parties[0].Players[2].GetCharacter().Unit
+
+	if melee.DistanceFromTarget > MaxMeleeAttackDistance || caster.DistanceFromTarget <= MaxMeleeAttackDistance {
+		t.Fatalf("Expected the melee player in range of the first target and the caster out of it")
+	}
+	if far.CurrentTarget != tank {
+		t.Fatalf("Expected the second target to start on the tank, got %s", far.CurrentTarget.Label)
+	}
+
+	far.AddThreat(sim, tank, 1000)
+	far.AddThreat(sim, melee, 1200)
+	if far.CurrentTarget != tank {
+		t.Fatalf("Expected the melee player to need 130%% of the tank's threat on a target it isn't next to, got %s", far.CurrentTarget.Label)
+	}
+
+	far.AddThreat(sim, caster, 1150)
+	if far.CurrentTarget != caster {
+		t.Fatalf("Expected the caster to pull aggro above 110%% on a target it is next to, got %s", far.CurrentTarget.Label)
+	}
+}
+
+func TestThreatFromRageGainAndAOEThreat(t *testing.T) {
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Warrior",
+					Class:     proto.Class_ClassWarrior,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_Warrior{},
+					Equipment: &proto.EquipmentSpec{},
+					Rotation:  &proto.APLRotation{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets:  []*proto.Target{{Name: "first", Level: 63}, {Name: "second", Level: 63}},
+			Duration: 10,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	agent := sim.Raid.Parties[0].Players[0].(*rageTestAgent)
+	warrior := &agent.Unit
+
+	agent.AddRage(sim, 10, agent.bloodrageMetrics)
+	for _, target := range sim.Encounter.Targets {
+		if threat := target.Threat(warrior); threat != 10*ThreatPerRageGained {
+			t.Errorf("Expected rage gain to add %0.1f threat on %s right away, got %0.1f", 10.0*ThreatPerRageGained, target.Label, threat)
+		}
+	}
+
+	agent.AddRage(sim, 10, agent.RageRefundMetrics)
+	agent.rageGainSpell.ApplyAOEThreat(sim, 100)
+	for _, target := range sim.Encounter.Targets {
+		if threat := target.Threat(warrior); threat != 10*ThreatPerRageGained+100 {
+			t.Errorf("Expected AOE threat but no refund threat on %s, got %0.1f", target.Label, threat)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+// Damage intake is too noisy to extrapolate from before this much of it has
+// been seen.
+const timeToDieMinSampleTime = time.Second * 5
+
+// Returns the damage per second this target has taken since it spawned, or 0
+// if there isn't enough data yet.
+func (target *Target) damageTakenRate(sim *Simulation) float64 {
+	sampleTime := sim.CurrentTime - target.SpawnTime
+	if sampleTime < timeToDieMinSampleTime || target.damageTaken <= 0 {
+		return 0
+	}
+	return target.damageTaken / sampleTime.Seconds()
+}
+
+// Returns how long this target stays in the fight if nobody kills it, based on
+// the fight duration and its despawn time.
+func (target *Target) remainingLifetime(sim *Simulation) time.Duration {
+	remaining := sim.GetRemainingDuration()
+	if target.DespawnTime > 0 {
+		remaining = min(remaining, target.DespawnTime-sim.CurrentTime)
+	}
+	return max(0, remaining)
+}
+
+// Estimates how long until this target's health drops to the given percent
+// (0-100), never longer than the target stays in the fight.
+//
+// Targets which die at zero health use their own damage intake. In health
+// fights the encounter-wide health pool and damage intake are used instead,
+// and in time-based fights health is assumed to drop linearly over the
+// fight duration.
+func (target *Target) TimeToPercent(sim *Simulation, percent float64) time.Duration {
+	if !target.enabled {
+		return 0
+	}
+	remaining := target.remainingLifetime(sim)
+	fraction := percent / 100
+
+	var damageLeft, rate float64
+	if maxHealth := target.GetStat(stats.Health); target.DiesAtZeroHealth && maxHealth > 0 {
+		damageLeft = (1-fraction)*maxHealth - target.damageTaken
+		rate = target.damageTakenRate(sim)
+	} else if sim.Encounter.EndFightAtHealth > 0 {
+		damageLeft = (1-fraction)*sim.Encounter.EndFightAtHealth - sim.Encounter.DamageTaken
+		if sim.CurrentTime >= timeToDieMinSampleTime {
+			rate = sim.Encounter.DamageTaken / sim.CurrentTime.Seconds()
+		}
+	} else {
+		healthPercent := sim.GetRemainingDurationPercent()
+		if healthPercent <= fraction {
+			return 0
+		}
+		return min(remaining, time.Duration((healthPercent-fraction)*float64(sim.Duration)))
+	}
+
+	if damageLeft <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return remaining
+	}
+	return min(remaining, DurationFromSeconds(damageLeft/rate))
+}
+
+// Estimates how long until this target dies or leaves the fight.
+func (target *Target) TimeToDie(sim *Simulation) time.Duration {
+	return target.TimeToPercent(sim, 0)
+}
+
+// Like Target.TimeToPercent, but for any unit. Only enemies lose health over
+// the fight, so for everyone else this is the remaining fight duration.
+func (unit *Unit) TimeToPercent(sim *Simulation, percent float64) time.Duration {
+	if unit.Type == EnemyUnit {
+		return unit.Env.Encounter.Targets[unit.Index].TimeToPercent(sim, percent)
+	}
+	return sim.GetRemainingDuration()
+}
+
+func (unit *Unit) TimeToDie(sim *Simulation) time.Duration {
+	return unit.TimeToPercent(sim, 0)
+}
+
+// Returns how many ticks a fresh application of this dot would deal before its
+// target is expected to die.
+func (dot *Dot) ExpectedTicksBeforeDeath(sim *Simulation) int32 {
+	tickPeriod := dot.TickLength
+	if dot.AffectedByCastSpeed {
+		tickPeriod = dot.Spell.Unit.ApplyCastSpeedForSpell(dot.TickLength, dot.Spell)
+	}
+	if tickPeriod <= 0 {
+		return dot.NumberOfTicks
+	}
+	return min(dot.NumberOfTicks, int32(dot.Unit.TimeToDie(sim)/tickPeriod))
+}
+
+// Whether reapplying this dot now gains at least minTicks ticks over letting
+// the current application run out, given how long its target is expected to
+// live. Spec code can use this to skip refreshes that won't pay off.
+func (dot *Dot) RefreshPaysOff(sim *Simulation, minTicks int32) bool {
+	ticksRemaining := int32(0)
+	if dot.IsActive() {
+		ticksRemaining = int32(dot.NumTicksRemaining(sim))
+	}
+	return dot.ExpectedTicksBeforeDeath(sim)-ticksRemaining >= minTicks
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/simsignals"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+func TestTimeToDie(t *testing.T) {
+	healthStats := stats.Stats{}
+	healthStats[stats.Health] = 10000
+
+	sim := NewSim(&proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Caster",
+					Class:     proto.Class_ClassShaman,
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Spec:      &proto.Player_ElementalShaman{},
+					Equipment: &proto.EquipmentSpec{},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+		},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "health", Level: 63, Stats: healthStats[:], DiesAtZeroHealth: true},
+				{Name: "timed", Level: 63, DespawnTime: 40},
+			},
+			Duration: 60,
+		},
+		SimOptions: &proto.SimOptions{},
+	}, simsignals.CreateSignals())
+	sim.reset()
+
+	healthTarget := sim.Encounter.Targets[0]
+	timedTarget := sim.Encounter.Targets[1]
+	dot := sim.Raid.Parties[0].Players[0].(*FakeAgent).Spell.Dot(&timedTarget.Unit)
+
+	expect := func(at time.Duration, target *Target, percent float64, expected time.Duration) {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: at,
+			OnAction: func(sim *Simulation) {
+				if actual := target.TimeToPercent(sim, percent); actual != expected {
+					t.Errorf("At %s: expected %s to reach %0.0f%% in %s, got %s", at, target.Label, percent, expected, actual)
+				}
+			},
+		})
+	}
+
+	// Not enough damage taken yet, so fall back to the remaining duration.
+	expect(time.Second*3, healthTarget, 0, time.Second*57)
+
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Second * 10,
+		OnAction: func(sim *Simulation) {
+			healthTarget.onDamageTaken(sim, 2000)
+		},
+	})
+	expect(time.Second*10, healthTarget, 0, time.Second*40)
+	expect(time.Second*10, healthTarget, 50, time.Second*15)
+	expect(time.Second*10, healthTarget, 90, 0)
+
+	// Time-based targets lose health linearly, and leave when they despawn.
+	expect(time.Second*10, timedTarget, 0, time.Second*30)
+	expect(time.Second*10, timedTarget, 50, time.Second*20)
+
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: time.Second * 30,
+		OnAction: func(sim *Simulation) {
+			if actual := dot.ExpectedTicksBeforeDeath(sim); actual != 3 {
+				t.Errorf("Expected 3 dot ticks before death, got %d", actual)
+			}
+			if !dot.RefreshPaysOff(sim, 3) {
+				t.Errorf("Expected a 3 tick refresh to pay off")
+			}
+			if dot.RefreshPaysOff(sim, 4) {
+				t.Errorf("Expected a 4 tick refresh not to pay off")
+			}
+		},
+	})
+
+	for sim.CurrentTime < time.Second*31 && !sim.Step() {
+	}
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+type timelineAuraKey struct {
+	unit     *Unit
+	actionID ActionID
+}
+
+// Builds an IterationTimeline from the combat events of a single iteration.
+type timelineRecorder struct {
+	timeline  *proto.IterationTimeline
+	openAuras map[timelineAuraKey]*proto.TimelineAura
+
+	// Hardcasts which have started but not completed yet. A unit only casts one at a time.
+	openCasts map[*Unit]CombatLogEvent
+}
+
+func newTimelineRecorder(seed int64) *timelineRecorder {
+	return &timelineRecorder{
+		timeline:  &proto.IterationTimeline{Seed: seed},
+		openAuras: make(map[timelineAuraKey]*proto.TimelineAura),
+		openCasts: make(map[*Unit]CombatLogEvent),
+	}
+}
+
+func (tr *timelineRecorder) addEvent(event *CombatLogEvent) {
+	switch event.Type {
+	case CombatLogSpellCastStart:
+		tr.openCasts[event.Source] = *event
+	case CombatLogSpellCastSuccess:
+		cast := &proto.TimelineCast{
+			Start: event.Timestamp.Seconds(),
+			End:   event.Timestamp.Seconds(),
+			Unit:  event.Source.Label,
+			Id:    event.ActionID.ToProto(),
+		}
+		if event.Target != nil {
+			cast.Target = event.Target.Label
+		}
+		// Casts which can be used while casting complete without ending the unit's hardcast.
+		if start, ok := tr.openCasts[event.Source]; ok && start.ActionID == event.ActionID {
+			cast.Start = start.Timestamp.Seconds()
+			delete(tr.openCasts, event.Source)
+		}
+		tr.timeline.Casts = append(tr.timeline.Casts, cast)
+		tr.snapshotResources(event)
+	case CombatLogSpellAuraApplied:
+		aura := &proto.TimelineAura{
+			Unit:      event.Target.Label,
+			Id:        event.ActionID.ToProto(),
+			Start:     event.Timestamp.Seconds(),
+			End:       -1,
+			MaxStacks: event.Stacks,
+		}
+		if event.Stacks > 0 {
+			aura.Stacks = append(aura.Stacks, &proto.TimelineAuraStacks{Time: aura.Start, Stacks: event.Stacks})
+		}
+		tr.timeline.Auras = append(tr.timeline.Auras, aura)
+		tr.openAuras[timelineAuraKey{event.Target, event.ActionID}] = aura
+	case CombatLogSpellAuraAppliedDose, CombatLogSpellAuraRemovedDose:
+		if aura, ok := tr.openAuras[timelineAuraKey{event.Target, event.ActionID}]; ok {
+			aura.MaxStacks = max(aura.MaxStacks, event.Stacks)
+			aura.Stacks = append(aura.Stacks, &proto.TimelineAuraStacks{Time: event.Timestamp.Seconds(), Stacks: event.Stacks})
+		}
+	case CombatLogSpellAuraRemoved:
+		key := timelineAuraKey{event.Target, event.ActionID}
+		if aura, ok := tr.openAuras[key]; ok {
+			aura.End = event.Timestamp.Seconds()
+			delete(tr.openAuras, key)
+		}
+	}
+}
+
+func (tr *timelineRecorder) snapshotResources(event *CombatLogEvent) {
+	unit := event.Source
+	addSnapshot := func(resourceType proto.ResourceType, value float64) {
+		tr.timeline.Resources = append(tr.timeline.Resources, &proto.TimelineResourceSnapshot{
+			Time:  event.Timestamp.Seconds(),
+			Unit:  unit.Label,
+			Type:  resourceType,
+			Value: value,
+		})
+	}
+
+	if unit.HasManaBar() {
+		addSnapshot(proto.ResourceType_ResourceTypeMana, unit.CurrentMana())
+	}
+	if unit.HasRageBar() {
+		addSnapshot(proto.ResourceType_ResourceTypeRage, unit.CurrentRage())
+	}
+	if unit.HasEnergyBar() {
+		addSnapshot(proto.ResourceType_ResourceTypeEnergy, unit.CurrentEnergy())
+		addSnapshot(proto.ResourceType_ResourceTypeComboPoints, float64(unit.ComboPoints()))
+	}
+	if unit.HasFocusBar() {
+		addSnapshot(proto.ResourceType_ResourceTypeFocus, unit.CurrentFocus())
+	}
+	if unit.HasHealthBar() {
+		addSnapshot(proto.ResourceType_ResourceTypeHealth, unit.CurrentHealth())
+	}
+}
+
+// Duration is how long the iteration actually lasted, which is shorter than
+// sim.Duration when a health-based fight ends early.
+func (tr *timelineRecorder) finish(sim *Simulation, duration float64) *proto.IterationTimeline {
+	for _, aura := range tr.openAuras {
+		aura.End = duration
+	}
+	tr.timeline.Duration = duration
+	tr.timeline.Dps = sim.Raid.dpsMetrics.Total / duration
+	return tr.timeline
+}
+
+// Replays the iterations selected by SimOptions.Timeline and records their timelines.
+// Must be called after all metrics have been collected, because replaying iterations
+// adds to them.
+func (sim *Simulation) recordTimelines(raidDps *proto.DistributionMetrics) []*proto.IterationTimeline {
+	options := sim.Options.Timeline
+	if options == nil {
+		return nil
+	}
+
+	var timelines []*proto.IterationTimeline
+	bySeed := make(map[int64]*proto.IterationTimeline)
+	selectSeed := func(seed int64) *proto.IterationTimeline {
+		if timeline, ok := bySeed[seed]; ok {
+			return timeline
+		}
+		timeline := &proto.IterationTimeline{Seed: seed}
+		bySeed[seed] = timeline
+		timelines = append(timelines, timeline)
+		return timeline
+	}
+
+	for _, seed := range options.Seeds {
+		selectSeed(seed).IsRequested = true
+	}
+	if options.MinDps {
+		selectSeed(raidDps.MinSeed).IsMinDps = true
+	}
+	if options.MaxDps {
+		selectSeed(raidDps.MaxSeed).IsMaxDps = true
+	}
+
+	for i, selected := range timelines {
+		recorder := newTimelineRecorder(selected.Seed)
+		sim.seedRands(selected.Seed)
+		sim.CombatLog = recorder.addEvent
+		sim.runOnce()
+		sim.CombatLog = nil
+
+		iterDuration := sim.Duration
+		if sim.Encounter.EndFightAtHealth != 0 {
+			iterDuration = sim.CurrentTime
+		}
+		timeline := recorder.finish(sim, iterDuration.Seconds())
+		timeline.IsRequested = selected.IsRequested
+		timeline.IsMinDps = selected.IsMinDps
+		timeline.IsMaxDps = selected.IsMaxDps
+		timelines[i] = timeline
+	}
+
+	return timelines
+}
+
+// Keeps only the timelines which are still relevant after combining split results:
+// requested seeds, and the min / max DPS iterations of the combined result.
+func combineTimelines(timelines []*proto.IterationTimeline, raidDps *proto.DistributionMetrics) []*proto.IterationTimeline {
+	var combined []*proto.IterationTimeline
+	bySeed := make(map[int64]*proto.IterationTimeline)
+	for _, timeline := range timelines {
+		timeline.IsMinDps = timeline.IsMinDps && timeline.Seed == raidDps.MinSeed
+		timeline.IsMaxDps = timeline.IsMaxDps && timeline.Seed == raidDps.MaxSeed
+		if !timeline.IsRequested && !timeline.IsMinDps && !timeline.IsMaxDps {
+			continue
+		}
+
+		if existing, ok := bySeed[timeline.Seed]; ok {
+			existing.IsRequested = existing.IsRequested || timeline.IsRequested
+			existing.IsMinDps = existing.IsMinDps || timeline.IsMinDps
+			existing.IsMaxDps = existing.IsMaxDps || timeline.IsMaxDps
+			continue
+		}
+		bySeed[timeline.Seed] = timeline
+		combined = append(combined, timeline)
+	}
+	return combined
+}
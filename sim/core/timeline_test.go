@@ -0,0 +1,93 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func TestCombineTimelines(t *testing.T) {
+	// Two splits each recorded their own min / max iteration, and the first split also recorded a requested seed.
+	timelines := []*proto.IterationTimeline{
+		{Seed: 1, IsRequested: true},
+		{Seed: 3, IsMinDps: true},
+		{Seed: 7, IsMaxDps: true},
+		{Seed: 12, IsMinDps: true},
+		{Seed: 1, IsMaxDps: true},
+	}
+	raidDps := &proto.DistributionMetrics{MinSeed: 12, MaxSeed: 1}
+
+	combined := combineTimelines(timelines, raidDps)
+	if len(combined) != 2 {
+		t.Fatalf("Expected 2 timelines, got %d: %v", len(combined), combined)
+	}
+	if combined[0].Seed != 1 || !combined[0].IsRequested || !combined[0].IsMaxDps || combined[0].IsMinDps {
+		t.Errorf("Unexpected first timeline: %v", combined[0])
+	}
+	if combined[1].Seed != 12 || !combined[1].IsMinDps || combined[1].IsMaxDps || combined[1].IsRequested {
+		t.Errorf("Unexpected second timeline: %v", combined[1])
+	}
+}
+
+func TestTimelineDpsUsesIterationDuration(t *testing.T) {
+	sim := SetupFakeSim()
+	sim.Raid.dpsMetrics.Total = 1000
+
+	// A health-based fight which ended well before the estimated duration.
+	timeline := newTimelineRecorder(1).finish(sim, 5)
+	if timeline.Duration != 5 {
+		t.Errorf("Expected a duration of 5s, got %0.2fs", timeline.Duration)
+	}
+	if timeline.Dps != 200 {
+		t.Errorf("Expected 200 DPS over the 5s the iteration lasted, got %0.2f (sim duration is %s)", timeline.Dps, sim.Duration)
+	}
+}
+
+func TestTimelineRecorderCastsAndStacks(t *testing.T) {
+	player := &Unit{Label: "Player"}
+	target := &Unit{Label: "Target"}
+	frostbolt := ActionID{SpellID: 116}
+	sunder := ActionID{SpellID: 7386}
+
+	recorder := newTimelineRecorder(1)
+	for _, event := range []CombatLogEvent{
+		{Timestamp: time.Second * 1, Type: CombatLogSpellCastStart, Source: player, Target: target, ActionID: frostbolt},
+		{Timestamp: time.Millisecond * 3500, Type: CombatLogSpellCastSuccess, Source: player, Target: target, ActionID: frostbolt},
+		{Timestamp: time.Second * 4, Type: CombatLogSpellCastSuccess, Source: player, Target: target, ActionID: sunder},
+		{Timestamp: time.Second * 4, Type: CombatLogSpellAuraApplied, Source: player, Target: target, ActionID: sunder, Stacks: 1},
+		{Timestamp: time.Second * 5, Type: CombatLogSpellAuraAppliedDose, Source: player, Target: target, ActionID: sunder, Stacks: 2},
+		{Timestamp: time.Second * 6, Type: CombatLogSpellAuraRemovedDose, Source: player, Target: target, ActionID: sunder, Stacks: 1},
+		{Timestamp: time.Second * 7, Type: CombatLogSpellAuraRemoved, Source: player, Target: target, ActionID: sunder},
+	} {
+		recorder.addEvent(&event)
+	}
+	timeline := recorder.timeline
+
+	if len(timeline.Casts) != 2 {
+		t.Fatalf("Expected 2 casts, got %d: %v", len(timeline.Casts), timeline.Casts)
+	}
+	if cast := timeline.Casts[0]; cast.Start != 1 || cast.End != 3.5 {
+		t.Errorf("Expected the hardcast to last from 1s to 3.5s, got %0.1fs to %0.1fs", cast.Start, cast.End)
+	}
+	if cast := timeline.Casts[1]; cast.Start != 4 || cast.End != 4 {
+		t.Errorf("Expected the instant cast to start and end at 4s, got %0.1fs to %0.1fs", cast.Start, cast.End)
+	}
+
+	if len(timeline.Auras) != 1 {
+		t.Fatalf("Expected 1 aura, got %d: %v", len(timeline.Auras), timeline.Auras)
+	}
+	aura := timeline.Auras[0]
+	if aura.Start != 4 || aura.End != 7 || aura.MaxStacks != 2 {
+		t.Errorf("Unexpected aura: %v", aura)
+	}
+	expectedStacks := []*proto.TimelineAuraStacks{{Time: 4, Stacks: 1}, {Time: 5, Stacks: 2}, {Time: 6, Stacks: 1}}
+	if len(aura.Stacks) != len(expectedStacks) {
+		t.Fatalf("Expected %d stack changes, got %v", len(expectedStacks), aura.Stacks)
+	}
+	for i, expected := range expectedStacks {
+		if aura.Stacks[i].Time != expected.Time || aura.Stacks[i].Stacks != expected.Stacks {
+			t.Errorf("Expected stack change %d to be %v, got %v", i, expected, aura.Stacks[i])
+		}
+	}
+}
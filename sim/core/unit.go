@@ -61,6 +61,11 @@ type Unit struct {
 	StartDistanceFromTarget float64
 	DistanceFromTarget      float64
 
+	// Where this unit stands, in yards.
+	StartPosition    Position
+	Position         Position
+	hasStartPosition bool
+
 	MovementHandler *MovementHandler
 
 	// Environment in which this Unit exists. This will be nil until after the
@@ -139,6 +144,11 @@ type Unit struct {
 	// No more than one cast may be active at any given time.
 	Hardcast Hardcast
 
+	// Number of active downtime events silencing this unit, or making it
+	// untargetable.
+	silenced     int32
+	untargetable int32
+
 	// GCD-related PendingActions.
 	gcdAction              *PendingAction
 	hardcastAction         *PendingAction
@@ -159,8 +169,9 @@ type Unit struct {
 
 // Units can be disabled for several reasons:
 //  1. Downtime for temporary pets (e.g. Water Elemental)
-//  2. Enemy units in various phases (not yet implemented)
-//  3. Dead units (not yet implemented)
+//  2. Enemy units which have not spawned yet or have despawned
+//  3. Enemy units which have died
+//  4. Dead players (not yet implemented)
 func (unit *Unit) IsEnabled() bool {
 	return unit.enabled
 }
@@ -479,6 +490,9 @@ func (unit *Unit) reset(sim *Simulation, _ Agent) {
 	}
 
 	unit.DistanceFromTarget = unit.StartDistanceFromTarget
+	unit.Position = unit.StartPosition
+	unit.silenced = 0
+	unit.untargetable = 0
 
 	unit.manaBar.reset()
 	unit.focusBar.reset(sim)
@@ -514,7 +528,6 @@ func (unit *Unit) doneIteration(sim *Simulation) {
 	unit.Hardcast = Hardcast{}
 
 	unit.manaBar.doneIteration(sim)
-	unit.rageBar.doneIteration()
 
 	unit.auraTracker.doneIteration(sim)
 	for _, spell := range unit.Spellbook {
@@ -7,12 +7,12 @@ import (
 )
 
 func (druid *Druid) registerDemoralizingRoarSpell() {
-	druid.DemoralizingRoarAuras = druid.NewEnemyAuraArray(func(target *core.Unit) *core.Aura {
-		return core.DemoralizingRoarAura(target, druid.Talents.FeralAggression)
+	druid.DemoralizingRoarAuras = druid.NewEnemyAuraArray(func(target *core.Unit, _ int32) *core.Aura {
+		return core.DemoralizingRoarAura(target, druid.Talents.FeralAggression, druid.Level)
 	})
 
 	druid.DemoralizingRoar = druid.RegisterSpell(Bear, core.SpellConfig{
-		ActionID:    core.ActionID{SpellID: 48560},
+		ActionID:    druid.DemoralizingRoarAuras.Get(druid.Env.Encounter.TargetUnits[0]).ActionID,
 		SpellSchool: core.SpellSchoolPhysical,
 		ProcMask:    core.ProcMaskEmpty,
 		Flags:       SpellFlagOmen | core.SpellFlagAPL,
@@ -28,7 +28,6 @@ func (druid *Druid) registerDemoralizingRoarSpell() {
 		},
 
 		ThreatMultiplier: 1,
-		FlatThreatBonus:  62 * 2,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			for _, aoeTarget := range sim.Encounter.TargetUnits {
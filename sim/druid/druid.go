@@ -22,11 +22,14 @@ const (
 	SpellCode_DruidFaerieFire
 	SpellCode_DruidFaerieFireFeral
 	SpellCode_DruidFerociousBite
+	SpellCode_DruidHealingTouch
 	SpellCode_DruidInsectSwarm
 	SpellCode_DruidMangleCat
 	SpellCode_DruidMangleBear
 	SpellCode_DruidMoonfire
 	SpellCode_DruidRake
+	SpellCode_DruidRegrowth
+	SpellCode_DruidRejuvenation
 	SpellCode_DruidRip
 	SpellCode_DruidShred
 	SpellCode_DruidStarfire
@@ -62,6 +65,7 @@ type Druid struct {
 	ForceOfNature        *DruidSpell
 	FrenziedRegeneration *DruidSpell
 	GiftOfTheWild        *DruidSpell
+	HealingTouch         []*DruidSpell
 	Hurricane            []*DruidSpell
 	Innervate            *DruidSpell
 	InsectSwarm          []*DruidSpell
@@ -75,6 +79,8 @@ type Druid struct {
 	Moonfire             []*DruidSpell
 	Rebirth              *DruidSpell
 	Rake                 *DruidSpell
+	Regrowth             []*DruidSpell
+	Rejuvenation         []*DruidSpell
 	Rip                  *DruidSpell
 	SavageRoar           *DruidSpell
 	Shred                *DruidSpell
@@ -211,6 +217,12 @@ func (druid *Druid) RegisterBalanceSpells() {
 	druid.registerWrathSpell()
 }
 
+func (druid *Druid) RegisterRestorationSpells() {
+	druid.registerHealingTouchSpell()
+	druid.registerRegrowthSpell()
+	druid.registerRejuvenationSpell()
+}
+
 // TODO: Classic feral
 func (druid *Druid) RegisterFeralCatSpells() {
 	druid.registerCatFormSpell()
@@ -227,21 +239,17 @@ func (druid *Druid) RegisterFeralCatSpells() {
 	druid.registerTigersFurySpell()
 }
 
-// TODO: Classic feral tank
 func (druid *Druid) RegisterFeralTankSpells() {
-	// druid.registerBarkskinCD()
-	// druid.registerBerserkCD()
-	// druid.registerBearFormSpell()
-	// druid.registerDemoralizingRoarSpell()
-	// druid.registerEnrageSpell()
+	druid.registerBarkskinCD()
+	druid.registerBearFormSpell()
+	druid.registerDemoralizingRoarSpell()
+	druid.registerEnrageSpell()
 	// druid.registerFrenziedRegenerationCD()
-	// druid.registerMangleBearSpell()
-	// druid.registerMaulSpell()
+	druid.registerMangleBearSpell()
+	druid.registerMaulSpell()
 	// druid.registerLacerateSpell()
-	// druid.registerRakeSpell()
-	// druid.registerRipSpell()
 	// druid.registerSurvivalInstinctsCD()
-	// druid.registerSwipeBearSpell()
+	druid.registerSwipeBearSpell()
 }
 
 func (druid *Druid) Reset(_ *core.Simulation) {
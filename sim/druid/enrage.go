@@ -7,35 +7,25 @@ import (
 	"github.com/wowsims/sod/sim/core/stats"
 )
 
+// https://www.wowhead.com/classic/spell=5229/enrage
 func (druid *Druid) registerEnrageSpell() {
 	actionID := core.ActionID{SpellID: 5229}
 	rageMetrics := druid.NewRageMetrics(actionID)
 
-	instantRage := []float64{20, 24, 27, 30}[druid.Talents.Intensity]
+	instantRage := 20 + 5*float64(druid.Talents.ImprovedEnrage)
 
-	dmgBonus := 0.05 * float64(druid.Talents.KingOfTheJungle)
-
-	t10_4p := druid.HasSetBonus(ItemSetLasherweaveBattlegear, 4)
+	// Enrage reduces armor by 27% in Dire Bear Form and 16% in Bear Form
+	armorMultiplier := core.TernaryFloat64(druid.Level >= 40, 0.73, 0.84)
 
 	druid.EnrageAura = druid.RegisterAura(core.Aura{
 		Label:    "Enrage Aura",
 		ActionID: actionID,
 		Duration: 10 * time.Second,
 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-			druid.PseudoStats.DamageDealtMultiplier *= 1.0 + dmgBonus
-			if !t10_4p {
-				druid.ApplyDynamicEquipScaling(sim, stats.Armor, 0.84)
-			} else {
-				druid.PseudoStats.DamageTakenMultiplier *= 0.88
-			}
+			druid.ApplyDynamicEquipScaling(sim, stats.Armor, armorMultiplier)
 		},
 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-			druid.PseudoStats.DamageDealtMultiplier /= 1.0 + dmgBonus
-			if !t10_4p {
-				druid.RemoveDynamicEquipScaling(sim, stats.Armor, 0.84)
-			} else {
-				druid.PseudoStats.DamageTakenMultiplier /= 0.88
-			}
+			druid.RemoveDynamicEquipScaling(sim, stats.Armor, armorMultiplier)
 		},
 	})
 
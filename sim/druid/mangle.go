@@ -7,25 +7,25 @@ import (
 	"github.com/wowsims/sod/sim/core/proto"
 )
 
-/* TODO: Bear mangle
 func (druid *Druid) registerMangleBearSpell() {
-	if !druid.Talents.Mangle {
+	if !druid.HasRune(proto.DruidRune_RuneHandsMangle) {
 		return
 	}
 
-	mangleAuras := druid.NewEnemyAuraArray(core.MangleAura)
-	durReduction := (0.5) * float64(druid.Talents.ImprovedMangle)
+	weaponMulti := 1.6
+	rageCost := 15 - float64(druid.Talents.Ferocity)
 
+	mangleAuras := druid.NewEnemyAuraArray(core.MangleAura)
 	druid.MangleBear = druid.RegisterSpell(Bear, core.SpellConfig{
-		SpellCode:   SpellCode_DruidMangleBear
-		ActionID:    core.ActionID{SpellID: 48564},
+		SpellCode:   SpellCode_DruidMangleBear,
+		ActionID:    core.ActionID{SpellID: int32(proto.DruidRune_RuneHandsMangle)},
 		SpellSchool: core.SpellSchoolPhysical,
 		DefenseType: core.DefenseTypeMelee,
 		ProcMask:    core.ProcMaskMeleeMHSpecial,
-		Flags:       SpellFlagOmen | core.SpellFlagMeleeMetrics | core.SpellFlagIncludeTargetBonusDamage | core.SpellFlagAPL,
+		Flags:       core.SpellFlagMeleeMetrics | core.SpellFlagAPL | SpellFlagOmen,
 
 		RageCost: core.RageCostOptions{
-			Cost:   20 - float64(druid.Talents.Ferocity),
+			Cost:   rageCost,
 			Refund: 0.8,
 		},
 		Cast: core.CastConfig{
@@ -35,18 +35,16 @@ func (druid *Druid) registerMangleBearSpell() {
 			IgnoreHaste: true,
 			CD: core.Cooldown{
 				Timer:    druid.NewTimer(),
-				Duration: time.Duration(float64(time.Second) * (6 - durReduction)),
+				Duration: time.Second * 6,
 			},
 		},
 
-		DamageMultiplier: (1 + 0.1*float64(druid.Talents.SavageFury)) * 1.15
-		ThreatMultiplier: core.TernaryFloat64(druid.HasSetBonus(ItemSetThunderheartHarness, 2), 1.15, 1),
+		DamageMultiplier: (1 + 0.1*float64(druid.Talents.SavageFury)) * weaponMulti,
+		ThreatMultiplier: 1,
+		BonusCoefficient: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			baseDamage := 299/1.15 +
-				spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower()) +
-				spell.BonusWeaponDamage()
-
+			baseDamage := spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower())
 			result := spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 
 			if result.Landed() {
@@ -54,16 +52,11 @@ func (druid *Druid) registerMangleBearSpell() {
 			} else {
 				spell.IssueRefund(sim)
 			}
-
-			if druid.BerserkAura.IsActive() {
-				spell.CD.Reset()
-			}
 		},
 
 		RelatedAuras: []core.AuraArray{mangleAuras},
 	})
-        }
-*/
+}
 
 func (druid *Druid) registerMangleCatSpell() {
 	if !druid.HasRune(proto.DruidRune_RuneHandsMangle) {
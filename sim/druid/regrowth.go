@@ -0,0 +1,92 @@
+package druid
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+)
+
+const RegrowthRanks = 9
+const RegrowthTicks = 7
+
+var RegrowthSpellId = [RegrowthRanks + 1]int32{0, 8936, 8938, 8939, 8940, 8941, 9750, 9856, 9857, 9858}
+var RegrowthBaseHealing = [RegrowthRanks + 1][]float64{{0}, {93, 107}, {176, 201}, {255, 290}, {336, 378}, {425, 479}, {534, 599}, {672, 751}, {839, 935}, {1003, 1119}}
+var RegrowthBaseHotHealing = [RegrowthRanks + 1]float64{0, 98, 175, 259, 343, 427, 546, 686, 861, 1064}
+var RegrowthSpellCoeff = [RegrowthRanks + 1]float64{0, 0.2, 0.265, 0.286, 0.286, 0.286, 0.286, 0.286, 0.286, 0.286}
+var RegrowthHotCoeff = [RegrowthRanks + 1]float64{0, 0.49, 0.648, 0.7, 0.7, 0.7, 0.7, 0.7, 0.7, 0.7}
+var RegrowthManaCost = [RegrowthRanks + 1]float64{0, 120, 205, 280, 350, 420, 510, 615, 740, 880}
+var RegrowthLevel = [RegrowthRanks + 1]int{0, 12, 18, 24, 30, 36, 42, 48, 54, 60}
+
+func (druid *Druid) registerRegrowthSpell() {
+	druid.Regrowth = make([]*DruidSpell, RegrowthRanks+1)
+
+	for rank := 1; rank <= RegrowthRanks; rank++ {
+		config := druid.newRegrowthSpellConfig(rank)
+
+		if config.RequiredLevel <= int(druid.Level) {
+			druid.Regrowth[rank] = druid.RegisterSpell(Humanoid|Tree, config)
+		}
+	}
+}
+
+func (druid *Druid) newRegrowthSpellConfig(rank int) core.SpellConfig {
+	spellId := RegrowthSpellId[rank]
+	baseHealingLow := RegrowthBaseHealing[rank][0]
+	baseHealingHigh := RegrowthBaseHealing[rank][1]
+	baseTickHealing := RegrowthBaseHotHealing[rank] / RegrowthTicks
+	spellCoeff := RegrowthSpellCoeff[rank]
+	hotCoeff := RegrowthHotCoeff[rank] / RegrowthTicks
+	manaCost := RegrowthManaCost[rank]
+	level := RegrowthLevel[rank]
+
+	return core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: spellId},
+		SpellCode:   SpellCode_DruidRegrowth,
+		SpellSchool: core.SpellSchoolNature,
+		DefenseType: core.DefenseTypeMagic,
+		ProcMask:    core.ProcMaskSpellHealing,
+		Flags:       SpellFlagOmen | core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		RequiredLevel: level,
+		Rank:          rank,
+
+		ManaCost: core.ManaCostOptions{
+			FlatCost: manaCost,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Second * 2,
+			},
+		},
+
+		BonusCritRating: 10 * float64(druid.Talents.ImprovedRegrowth) * core.CritRatingPerCritChance,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		BonusCoefficient: spellCoeff,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: fmt.Sprintf("Regrowth (Rank %d)", rank),
+			},
+			NumberOfTicks:    RegrowthTicks,
+			TickLength:       time.Second * 3,
+			BonusCoefficient: hotCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				dot.SnapshotHeal(target, baseTickHealing, isRollover)
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := sim.Roll(baseHealingLow, baseHealingHigh)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			spell.Hot(target).Apply(sim)
+		},
+	}
+}
@@ -28,11 +28,16 @@ func NewRestorationDruid(character *core.Character, options *proto.Player) *Rest
 	selfBuffs := druid.SelfBuffs{}
 
 	resto := &RestorationDruid{
-		Druid: druid.New(character, druid.Tree, selfBuffs, options.TalentsString),
+		Druid:   druid.New(character, druid.Humanoid, selfBuffs, options.TalentsString),
+		Options: restoOptions.Options,
 	}
 
 	resto.SelfBuffs.InnervateTarget = &proto.UnitReference{}
-	if restoOptions.Options.InnervateTarget != nil {
+	if restoOptions.Options.InnervateTarget == nil || restoOptions.Options.InnervateTarget.Type == proto.UnitReference_Unknown {
+		resto.SelfBuffs.InnervateTarget = &proto.UnitReference{
+			Type: proto.UnitReference_Self,
+		}
+	} else {
 		resto.SelfBuffs.InnervateTarget = restoOptions.Options.InnervateTarget
 	}
 
@@ -41,14 +46,27 @@ func NewRestorationDruid(character *core.Character, options *proto.Player) *Rest
 
 type RestorationDruid struct {
 	*druid.Druid
+
+	Options *proto.RestorationDruid_Options
 }
 
 func (resto *RestorationDruid) GetDruid() *druid.Druid {
 	return resto.Druid
 }
 
+func (resto *RestorationDruid) GetMainTarget() *core.Unit {
+	target := resto.Env.Raid.GetFirstTargetDummy()
+	if target == nil {
+		return &resto.Unit
+	} else {
+		return &target.Unit
+	}
+}
+
 func (resto *RestorationDruid) Initialize() {
+	resto.CurrentTarget = resto.GetMainTarget()
 	resto.Druid.Initialize()
+	resto.RegisterRestorationSpells()
 }
 
 func (resto *RestorationDruid) Reset(sim *core.Simulation) {
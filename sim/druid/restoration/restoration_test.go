@@ -0,0 +1,93 @@
+package restoration
+
+import (
+	"testing"
+
+	_ "github.com/wowsims/sod/sim/common" // imported to get caster sets included.
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func init() {
+	RegisterRestorationDruid()
+}
+
+func TestRestoration(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator([]core.CharacterSuiteConfig{
+		{
+			Class:      proto.Class_ClassDruid,
+			Level:      25,
+			Race:       proto.Race_RaceTauren,
+			OtherRaces: []proto.Race{proto.Race_RaceNightElf},
+			IsHealer:   true,
+
+			Talents:     Phase1Talents,
+			GearSet:     core.GetGearSet("../../../ui/restoration_druid/gear_sets", "phase_1"),
+			Rotation:    core.GetAplRotation("../../../ui/restoration_druid/apls", "phase_1"),
+			Buffs:       core.FullBuffsPhase1,
+			Consumes:    Phase1Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Default", SpecOptions: PlayerOptionsBasic},
+
+			ItemFilter: ItemFilters,
+		},
+		{
+			Class:      proto.Class_ClassDruid,
+			Level:      60,
+			Phase:      4,
+			Race:       proto.Race_RaceTauren,
+			OtherRaces: []proto.Race{proto.Race_RaceNightElf},
+			IsHealer:   true,
+
+			Talents:     Phase4Talents,
+			GearSet:     core.GetGearSet("../../../ui/restoration_druid/gear_sets", "phase_4"),
+			Rotation:    core.GetAplRotation("../../../ui/restoration_druid/apls", "phase_4"),
+			Buffs:       core.FullBuffsPhase4,
+			Consumes:    Phase4Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Default", SpecOptions: PlayerOptionsBasic},
+
+			ItemFilter: ItemFilters,
+		},
+	}))
+}
+
+var Phase1Talents = "--0555001"
+var Phase4Talents = "511--055503155315051"
+
+var Phase1Consumes = core.ConsumesCombo{
+	Label: "P1-Consumes",
+	Consumes: &proto.Consumes{
+		DefaultPotion: proto.Potions_LesserManaPotion,
+		Food:          proto.Food_FoodSmokedSagefish,
+	},
+}
+
+var Phase4Consumes = core.ConsumesCombo{
+	Label: "P4-Consumes",
+	Consumes: &proto.Consumes{
+		DefaultPotion:   proto.Potions_MajorManaPotion,
+		Flask:           proto.Flask_FlaskOfDistilledWisdom,
+		Food:            proto.Food_FoodNightfinSoup,
+		MainHandImbue:   proto.WeaponImbue_BrilliantManaOil,
+		ManaRegenElixir: proto.ManaRegenElixir_MagebloodPotion,
+	},
+}
+
+var PlayerOptionsBasic = &proto.Player_RestorationDruid{
+	RestorationDruid: &proto.RestorationDruid{
+		Options: &proto.RestorationDruid_Options{},
+	},
+}
+
+var ItemFilters = core.ItemFilter{
+	ArmorType: proto.ArmorType_ArmorTypeLeather,
+
+	WeaponTypes: []proto.WeaponType{
+		proto.WeaponType_WeaponTypeDagger,
+		proto.WeaponType_WeaponTypeMace,
+		proto.WeaponType_WeaponTypeOffHand,
+		proto.WeaponType_WeaponTypeStaff,
+	},
+	RangedWeaponTypes: []proto.RangedWeaponType{
+		proto.RangedWeaponType_RangedWeaponTypeIdol,
+	},
+}
@@ -76,9 +76,8 @@ const (
 	Gore_CatResetProcChance  = .15
 )
 
-// TODO: Bear spells not implemented: MangleBear, Swipe, Maul
 func (druid *Druid) rollGoreBearReset(sim *core.Simulation) {
-	if sim.RandomFloat("Gore (Bear)") < Gore_BearResetProcChance {
+	if druid.MangleBear != nil && sim.RandomFloat("Gore (Bear)") < Gore_BearResetProcChance {
 		druid.MangleBear.CD.Reset()
 	}
 }
@@ -19,12 +19,13 @@ const SwipeThreatMultiplier = 2.0
 
 func (druid *Druid) registerSwipeBearSpell() {
 	hasImprovedSwipeRune := druid.HasRune(proto.DruidRune_RuneCloakImprovedSwipe)
+	hasGoreRune := druid.HasRune(proto.DruidRune_RuneHelmGore)
 
 	rank := map[int32]int{
 		25: 2,
 		40: 3,
 		50: 4,
-		60: 6,
+		60: 5,
 	}[druid.Level]
 
 	level := SwipeLevel[rank]
@@ -52,7 +53,7 @@ func (druid *Druid) registerSwipeBearSpell() {
 		RequiredLevel: level,
 
 		RageCost: core.RageCostOptions{
-			Cost: 20 - float64(druid.Talents.Ferocity),
+			Cost: rageCost,
 		},
 
 		Cast: core.CastConfig{
@@ -74,6 +75,10 @@ func (druid *Druid) registerSwipeBearSpell() {
 			for _, result := range results {
 				spell.DealDamage(sim, result)
 			}
+
+			if hasGoreRune && results[0].Landed() {
+				druid.rollGoreBearReset(sim)
+			}
 		},
 	})
 }
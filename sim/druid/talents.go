@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
 	"github.com/wowsims/sod/sim/core/stats"
 )
 
@@ -32,6 +33,7 @@ func (druid *Druid) ApplyTalents() {
 
 	// Restoration
 	druid.applyFuror()
+	druid.applyGiftOfNature()
 
 	druid.PseudoStats.SpiritRegenRateCasting += .05 * float64(druid.Talents.Reflection)
 }
@@ -46,9 +48,13 @@ func (druid *Druid) ThickHideMultiplier() float64 {
 	return thickHideMulti
 }
 
+// Bear Form increases armor from items by 180%, Dire Bear Form by 360%.
 func (druid *Druid) BearArmorMultiplier() float64 {
-	sotfMulti := 1.0 + 0.33/3.0
-	return 4.7 * sotfMulti
+	multiplier := core.TernaryFloat64(druid.Level >= 40, 4.6, 2.8)
+	if druid.HasRune(proto.DruidRune_RuneChestSurvivalOfTheFittest) {
+		multiplier *= 1.33
+	}
+	return multiplier
 }
 
 func (druid *Druid) applyNaturesGrace() {
@@ -251,6 +257,20 @@ func (druid *Druid) applyFuror() {
 	})
 }
 
+func (druid *Druid) applyGiftOfNature() {
+	if druid.Talents.GiftOfNature == 0 {
+		return
+	}
+
+	modifier := 0.02 * float64(druid.Talents.GiftOfNature)
+
+	druid.OnSpellRegistered(func(spell *core.Spell) {
+		if spell.ProcMask.Matches(core.ProcMaskSpellHealing) {
+			spell.DamageMultiplierAdditive += modifier
+		}
+	})
+}
+
 func (druid *Druid) applyOmenOfClarity() {
 	if !druid.Talents.OmenOfClarity {
 		return
@@ -328,6 +348,9 @@ func (druid *Druid) applyMoonfury() {
 						druid.Wrath,
 						druid.Starfire,
 						druid.Moonfire,
+						druid.HealingTouch,
+						druid.Regrowth,
+						druid.Rejuvenation,
 						{druid.Starsurge},
 						{druid.Sunfire},
 					},
@@ -38,17 +38,20 @@ func NewFeralTankDruid(character *core.Character, options *proto.Player) *FeralT
 	}
 
 	bear.EnableRageBar(core.RageBarOptions{
-		StartingRage:   bear.Options.StartingRage,
-		RageMultiplier: 1,
+		StartingRage:          bear.Options.StartingRage,
+		DamageDealtMultiplier: 1,
+		DamageTakenMultiplier: 1,
 	})
 
 	bear.EnableAutoAttacks(bear, core.AutoAttackOptions{
 		// Base paw weapon.
-		MainHand:       bear.GetBearWeapon(),
+		MainHand:       bear.GetBearWeapon(bear.Level),
 		AutoSwingMelee: true,
-		ReplaceMHSwing: bear.TryMaul,
 	})
-	bear.ReplaceBearMHFunc = bear.TryMaul
+	bear.ReplaceBearMHFunc = bear.MaulReplaceMH
+
+	bear.PseudoStats.FeralCombatEnabled = true
+	bear.PseudoStats.InFrontOfTarget = true
 
 	healingModel := options.HealingModel
 	if healingModel != nil {
@@ -77,7 +80,7 @@ func (bear *FeralTankDruid) Initialize() {
 
 func (bear *FeralTankDruid) Reset(sim *core.Simulation) {
 	bear.Druid.Reset(sim)
-	bear.Druid.ClearForm(sim)
+	bear.Druid.CancelShapeshift(sim)
 	bear.BearFormAura.Activate(sim)
 	bear.Druid.PseudoStats.Stunned = false
 }
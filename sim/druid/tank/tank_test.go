@@ -0,0 +1,102 @@
+package tank
+
+import (
+	"testing"
+
+	_ "github.com/wowsims/sod/sim/common"
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func init() {
+	RegisterFeralTankDruid()
+}
+
+func TestFeralTank(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator([]core.CharacterSuiteConfig{
+		{
+			Class:      proto.Class_ClassDruid,
+			Level:      25,
+			Race:       proto.Race_RaceTauren,
+			OtherRaces: []proto.Race{proto.Race_RaceNightElf},
+
+			Talents:     Phase1Talents,
+			GearSet:     core.GetGearSet("../../../ui/feral_tank_druid/gear_sets", "phase_1"),
+			Rotation:    core.GetAplRotation("../../../ui/feral_tank_druid/apls", "phase_1"),
+			Buffs:       core.FullBuffsPhase1,
+			Consumes:    Phase1Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Default", SpecOptions: PlayerOptionsDefault},
+
+			IsTank:          true,
+			InFrontOfTarget: true,
+
+			ItemFilter: ItemFilters,
+		},
+		{
+			Class:      proto.Class_ClassDruid,
+			Level:      60,
+			Phase:      4,
+			Race:       proto.Race_RaceTauren,
+			OtherRaces: []proto.Race{proto.Race_RaceNightElf},
+
+			Talents:     Phase4Talents,
+			GearSet:     core.GetGearSet("../../../ui/feral_tank_druid/gear_sets", "phase_4"),
+			Rotation:    core.GetAplRotation("../../../ui/feral_tank_druid/apls", "phase_4"),
+			Buffs:       core.FullBuffsPhase4,
+			Consumes:    Phase4Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Default", SpecOptions: PlayerOptionsDefault},
+
+			IsTank:          true,
+			InFrontOfTarget: true,
+
+			ItemFilter: ItemFilters,
+		},
+	}))
+}
+
+var Phase1Talents = "-50505001"
+var Phase4Talents = "-5052501303022151-550123"
+
+var PlayerOptionsDefault = &proto.Player_FeralTankDruid{
+	FeralTankDruid: &proto.FeralTankDruid{
+		Options: &proto.FeralTankDruid_Options{
+			InnervateTarget: &proto.UnitReference{}, // no Innervate
+			StartingRage:    20,
+		},
+	},
+}
+
+var Phase1Consumes = core.ConsumesCombo{
+	Label: "P1-Consumes",
+	Consumes: &proto.Consumes{
+		AgilityElixir: proto.AgilityElixir_ElixirOfLesserAgility,
+		ArmorElixir:   proto.ArmorElixir_ElixirOfDefense,
+		Food:          proto.Food_FoodSmokedSagefish,
+	},
+}
+
+var Phase4Consumes = core.ConsumesCombo{
+	Label: "P4-Consumes",
+	Consumes: &proto.Consumes{
+		AgilityElixir:   proto.AgilityElixir_ElixirOfTheMongoose,
+		ArmorElixir:     proto.ArmorElixir_ElixirOfSuperiorDefense,
+		Flask:           proto.Flask_FlaskOfTheTitans,
+		Food:            proto.Food_FoodBlessSunfruit,
+		StrengthBuff:    proto.StrengthBuff_JujuPower,
+		AttackPowerBuff: proto.AttackPowerBuff_JujuMight,
+	},
+}
+
+var ItemFilters = core.ItemFilter{
+	ArmorType: proto.ArmorType_ArmorTypeLeather,
+
+	WeaponTypes: []proto.WeaponType{
+		proto.WeaponType_WeaponTypeDagger,
+		proto.WeaponType_WeaponTypeMace,
+		proto.WeaponType_WeaponTypeOffHand,
+		proto.WeaponType_WeaponTypeStaff,
+	},
+	RangedWeaponTypes: []proto.RangedWeaponType{
+		proto.RangedWeaponType_RangedWeaponTypeIdol,
+	},
+}
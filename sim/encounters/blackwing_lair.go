@@ -48,48 +48,68 @@ func addVaelastraszTheCorrupt(bossPrefix string) {
 	})
 }
 
-type VaelastraszTheCorruptAI struct {
-	Target                     *core.Target
-	essenceOfTheRedSpell       *core.Spell
-	burningAdrenalineSpell     *core.Spell
-	burningAdrenalineTankSpell *core.Spell
-	burningAdrenalineTime      float64
-	fireNovaSpell              *core.Spell
-	flameBreathSpell           *core.Spell
-	cleaveSpell                *core.Spell
-	canAct                     bool
-}
-
 func NewVaelastraszTheCorruptAI() core.AIFactory {
-	return func() core.TargetAI {
-		return &VaelastraszTheCorruptAI{}
-	}
-}
+	return NewScriptedAI(func() *ScriptedAI {
+		burningAdrenaline := &ScriptedAbility{
+			MakeSpell: makeBurningAdrenalineSpell,
+			Targeting: TargetPlayer,
+		}
+		burningAdrenaline.Condition = func(sim *core.Simulation, ai *ScriptedAI) bool {
+			// Only received by non tank players, and never if the input is 0
+			return ai.Target.CurrentTarget == nil && burningAdrenaline.InitialDelay > 0
+		}
 
-func (ai *VaelastraszTheCorruptAI) Initialize(target *core.Target, config *proto.Target) {
-	ai.Target = target
-	if len(config.TargetInputs) > 0 {
-		ai.burningAdrenalineTime = config.TargetInputs[0].NumberValue
-	}
-	ai.registerSpells()
-	ai.canAct = true
+		return &ScriptedAI{
+			Abilities: []*ScriptedAbility{
+				{
+					MakeSpell: makeEssenceOfTheRedSpell,
+					Targeting: TargetPlayer,
+				},
+				burningAdrenaline,
+				{
+					MakeSpell:    makeBurningAdrenalineTankSpell,
+					Targeting:    TargetTank,
+					InitialDelay: time.Second * 10,
+					Period:       time.Minute * 4,
+				},
+				{
+					// Fire Nova would quickly kill a raid nobody heals, so it is
+					// only used when the boss is tanked.
+					MakeSpell: makeVaelastraszFireNovaSpell,
+					Targeting: TargetTank,
+					Period:    time.Second * 3,
+				},
+				{
+					MakeSpell: makeFlameBreathSpell,
+					Targeting: TargetTank,
+					Period:    time.Second * 9,
+				},
+				{
+					MakeSpell: makeVaelastraszCleaveSpell,
+					Targeting: TargetTank,
+					Period:    time.Second * 8,
+				},
+			},
+			OnInitialize: func(ai *ScriptedAI, config *proto.Target) {
+				if len(config.TargetInputs) > 0 {
+					burningAdrenaline.InitialDelay = core.DurationFromSeconds(config.TargetInputs[0].NumberValue)
+				}
+			},
+		}
+	})
 }
 
-func (ai *VaelastraszTheCorruptAI) registerSpells() {
-	essenceOfTheRedActionID := core.ActionID{SpellID: 23513}
-	burningAdrenalineActionID := core.ActionID{SpellID: 367987}
-	burningAdrenalineTankActionID := core.ActionID{SpellID: 469261}
-	fireNovaActionID := core.ActionID{SpellID: 23462}
-	flameBreathActionID := core.ActionID{SpellID: 23461}
-	cleaveActionID := core.ActionID{SpellID: 19983}
+const BossGCD = time.Millisecond * 1600
 
-	target := &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+func makeEssenceOfTheRedSpell(ai *ScriptedAI) *core.Spell {
+	essenceOfTheRedActionID := core.ActionID{SpellID: 23513}
 
+	target := ai.GetTarget(TargetPlayer)
 	essenceOfTheRedManaMetrics := target.NewManaMetrics(essenceOfTheRedActionID)
 	essenceOfTheRedEnergyMetrics := target.NewEnergyMetrics(essenceOfTheRedActionID)
 	essenceOfTheRedRageMetrics := target.NewRageMetrics(essenceOfTheRedActionID)
 
-	ai.essenceOfTheRedSpell = ai.Target.RegisterSpell(core.SpellConfig{
+	return ai.Target.RegisterSpell(core.SpellConfig{
 		ActionID: essenceOfTheRedActionID,
 		ProcMask: core.ProcMaskEmpty,
 
@@ -122,12 +142,25 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 			spell.Dot(target).Apply(sim)
 		},
 	})
+}
 
-	multiplierBonus := 1.0
-	inverseMultiplierBonus := 1.0
+// Burning Adrenaline increases the target's damage and attack / cast speed by 10% per stack.
+func burningAdrenalineStacksChange(target *core.Unit) func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+	return func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+		inverseMultiplierBonus := 1 / (1.0 + float64(oldStacks)*0.1)
+		target.MultiplyMeleeSpeed(sim, inverseMultiplierBonus)
+		target.MultiplyCastSpeed(inverseMultiplierBonus)
+		target.PseudoStats.DamageDealtMultiplier *= inverseMultiplierBonus
+		multiplierBonus := 1.0 + float64(newStacks)*0.1
+		target.MultiplyMeleeSpeed(sim, multiplierBonus)
+		target.MultiplyCastSpeed(multiplierBonus)
+		target.PseudoStats.DamageDealtMultiplier *= multiplierBonus
+	}
+}
 
-	ai.burningAdrenalineSpell = ai.Target.RegisterSpell(core.SpellConfig{
-		ActionID: burningAdrenalineActionID,
+func makeBurningAdrenalineSpell(ai *ScriptedAI) *core.Spell {
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 367987},
 		ProcMask: core.ProcMaskEmpty,
 
 		Cast: core.CastConfig{
@@ -138,18 +171,9 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 		},
 		Dot: core.DotConfig{
 			Aura: core.Aura{
-				Label:     "Burning Adrenaline",
-				MaxStacks: 100,
-				OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
-					inverseMultiplierBonus = 1 / (1.0 + float64(oldStacks)*0.1)
-					target.MultiplyMeleeSpeed(sim, inverseMultiplierBonus)
-					target.MultiplyCastSpeed(inverseMultiplierBonus)
-					target.PseudoStats.DamageDealtMultiplier *= inverseMultiplierBonus
-					multiplierBonus = 1.0 + float64(newStacks)*0.1
-					target.MultiplyMeleeSpeed(sim, multiplierBonus)
-					target.MultiplyCastSpeed(multiplierBonus)
-					target.PseudoStats.DamageDealtMultiplier *= multiplierBonus
-				},
+				Label:          "Burning Adrenaline",
+				MaxStacks:      100,
+				OnStacksChange: burningAdrenalineStacksChange(ai.GetTarget(TargetPlayer)),
 			},
 			NumberOfTicks: 240,
 			TickLength:    time.Second * 2,
@@ -162,9 +186,11 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 			spell.Dot(target).AddStack(sim)
 		},
 	})
+}
 
-	ai.burningAdrenalineTankSpell = ai.Target.RegisterSpell(core.SpellConfig{
-		ActionID:         burningAdrenalineTankActionID,
+func makeBurningAdrenalineTankSpell(ai *ScriptedAI) *core.Spell {
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 469261},
 		SpellSchool:      core.SpellSchoolFire,
 		DefenseType:      core.DefenseTypeMagic,
 		ProcMask:         core.ProcMaskSpellDamage,
@@ -179,18 +205,9 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 		},
 		Dot: core.DotConfig{
 			Aura: core.Aura{
-				Label:     "Burning Adrenaline (Tank)",
-				MaxStacks: 100,
-				OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
-					inverseMultiplierBonus = 1 / (1.0 + float64(oldStacks)*0.1)
-					target.MultiplyMeleeSpeed(sim, inverseMultiplierBonus)
-					target.MultiplyCastSpeed(inverseMultiplierBonus)
-					target.PseudoStats.DamageDealtMultiplier *= inverseMultiplierBonus
-					multiplierBonus = 1.0 + float64(newStacks)*0.1
-					target.MultiplyMeleeSpeed(sim, multiplierBonus)
-					target.MultiplyCastSpeed(multiplierBonus)
-					target.PseudoStats.DamageDealtMultiplier *= multiplierBonus
-				},
+				Label:          "Burning Adrenaline (Tank)",
+				MaxStacks:      100,
+				OnStacksChange: burningAdrenalineStacksChange(ai.GetTarget(TargetPlayer)),
 			},
 			NumberOfTicks: 240,
 			TickLength:    time.Second * 2,
@@ -207,9 +224,11 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 			spell.Dot(target).AddStack(sim)
 		},
 	})
+}
 
-	ai.cleaveSpell = ai.Target.RegisterSpell(core.SpellConfig{
-		ActionID:         cleaveActionID,
+func makeVaelastraszCleaveSpell(ai *ScriptedAI) *core.Spell {
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 19983},
 		SpellSchool:      core.SpellSchoolPhysical,
 		DefenseType:      core.DefenseTypeMelee,
 		ProcMask:         core.ProcMaskEmpty,
@@ -226,9 +245,12 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 			spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeEnemyMeleeWhite)
 		},
 	})
+}
 
-	ai.fireNovaSpell = ai.Target.RegisterSpell(core.SpellConfig{
-		ActionID:         fireNovaActionID,
+// Fire Nova pulses on the whole raid.
+func makeVaelastraszFireNovaSpell(ai *ScriptedAI) *core.Spell {
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 23462},
 		SpellSchool:      core.SpellSchoolFire,
 		DefenseType:      core.DefenseTypeMagic,
 		ProcMask:         core.ProcMaskSpellDamage,
@@ -240,15 +262,20 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 				Duration: time.Second * 3,
 			},
 		},
-		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			baseDamage := sim.Roll(555.0, 645.0)
-			spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMagicHit)
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, player := range sim.Raid.AllPlayerUnits {
+				baseDamage := sim.Roll(555.0, 645.0)
+				spell.CalcAndDealDamage(sim, player, baseDamage, spell.OutcomeMagicHit)
+			}
 		},
 	})
+}
 
+func makeFlameBreathSpell(ai *ScriptedAI) *core.Spell {
+	flameBreathActionID := core.ActionID{SpellID: 23461}
 	flameBreathTickDamage := 0.0
 
-	ai.flameBreathSpell = ai.Target.RegisterSpell(core.SpellConfig{
+	return ai.Target.RegisterSpell(core.SpellConfig{
 		ActionID:         flameBreathActionID,
 		SpellSchool:      core.SpellSchoolFire,
 		DefenseType:      core.DefenseTypeMagic,
@@ -297,55 +324,4 @@ func (ai *VaelastraszTheCorruptAI) registerSpells() {
 			spell.DealDamage(sim, result)
 		},
 	})
-
-}
-
-func (ai *VaelastraszTheCorruptAI) Reset(*core.Simulation) {
-}
-
-const BossGCD = time.Millisecond * 1600
-
-func (ai *VaelastraszTheCorruptAI) ExecuteCustomRotation(sim *core.Simulation) {
-	if !ai.canAct {
-		ai.Target.WaitUntil(sim, sim.CurrentTime+BossGCD)
-		return
-	}
-
-	target := ai.Target.CurrentTarget
-	isTank := true
-	if target == nil {
-		// For individual non tank sims we still want abilities to work
-		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
-		isTank = false
-	}
-
-	if ai.essenceOfTheRedSpell.CanCast(sim, target) {
-		ai.essenceOfTheRedSpell.Cast(sim, target)
-		ai.Target.WaitUntil(sim, sim.CurrentTime+BossGCD)
-		return
-	}
-
-	if isTank {
-		if ai.burningAdrenalineTankSpell.CanCast(sim, target) && (sim.CurrentTime >= (time.Second * 10)) {
-			ai.burningAdrenalineTankSpell.Cast(sim, target)
-		}
-		if ai.fireNovaSpell.CanCast(sim, target) {
-			ai.fireNovaSpell.Cast(sim, target)
-		}
-		if ai.flameBreathSpell.CanCast(sim, target) {
-			ai.flameBreathSpell.Cast(sim, target)
-			return
-		}
-		if ai.cleaveSpell.CanCast(sim, target) {
-			ai.cleaveSpell.Cast(sim, target)
-			return
-		}
-	} else {
-		//Do not cast if player input is 0 for BA Time
-		if ai.burningAdrenalineTime == 0 {
-			return
-		} else if ai.burningAdrenalineSpell.CanCast(sim, target) && (sim.CurrentTime >= (time.Second * time.Duration(ai.burningAdrenalineTime))) {
-			ai.burningAdrenalineSpell.Cast(sim, target)
-		}
-	}
 }
@@ -0,0 +1,287 @@
+package encounters
+
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// ScriptedAI is a TargetAI which uses its abilities on fixed timers instead of
+// only auto attacking. Abilities can be limited to certain phases, and phases
+// can start at a given fight time or once the boss drops below a health
+// threshold.
+type ScriptedAI struct {
+	Target *core.Target
+
+	Phases    []ScriptedPhase
+	Abilities []*ScriptedAbility
+
+	// Called once during Initialize, after the abilities' spells have been made.
+	// Can be used to read TargetInputs or register extra auras.
+	OnInitialize func(ai *ScriptedAI, config *proto.Target)
+
+	phase int
+}
+
+type ScriptedPhase struct {
+	Name string
+
+	// The phase starts at whichever of these is reached first. A zero value
+	// disables that trigger. Phases are entered in order, and the first phase
+	// always starts at the pull.
+	StartAt              time.Duration
+	StartAtHealthPercent float64
+
+	// Called when the phase starts.
+	OnStart func(sim *core.Simulation, ai *ScriptedAI)
+}
+
+type ScriptedTargeting int32
+
+const (
+	// Cast on the unit the boss is tanked by. Skipped if the boss isn't tanked.
+	TargetTank ScriptedTargeting = iota
+	// Cast on the tank, or on the first player for sims without a tank.
+	TargetPlayer
+)
+
+type ScriptedAbility struct {
+	// Creates the spell used by this ability.
+	MakeSpell func(ai *ScriptedAI) *core.Spell
+
+	Targeting ScriptedTargeting
+
+	// Time before the first use. Measured from the start of the phase for
+	// abilities limited to certain phases, otherwise from the pull.
+	InitialDelay time.Duration
+
+	// Time between uses. 0 means the ability is used once per phase.
+	Period time.Duration
+
+	// Indexes of the phases in which this ability is used. Empty means every phase.
+	Phases []int
+
+	// Optional extra condition checked before each use.
+	Condition func(sim *core.Simulation, ai *ScriptedAI) bool
+
+	Spell *core.Spell
+
+	nextUseAt time.Duration
+}
+
+func NewScriptedAI(makeAI func() *ScriptedAI) core.AIFactory {
+	return func() core.TargetAI {
+		return makeAI()
+	}
+}
+
+func (ai *ScriptedAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+
+	if len(ai.Phases) == 0 {
+		ai.Phases = []ScriptedPhase{{Name: "Phase 1"}}
+	}
+
+	for _, ability := range ai.Abilities {
+		ability.Spell = ability.MakeSpell(ai)
+	}
+
+	if ai.OnInitialize != nil {
+		ai.OnInitialize(ai, config)
+	}
+}
+
+func (ai *ScriptedAI) Reset(_ *core.Simulation) {
+	ai.phase = -1
+}
+
+// The index of the current phase.
+func (ai *ScriptedAI) Phase() int {
+	return ai.phase
+}
+
+// The boss's remaining health as a fraction, based on the encounter's health
+// or duration.
+func (ai *ScriptedAI) HealthPercent(sim *core.Simulation) float64 {
+	return sim.GetRemainingDurationPercent()
+}
+
+// The unit abilities are cast on for the given targeting, or nil if there is none.
+func (ai *ScriptedAI) GetTarget(targeting ScriptedTargeting) *core.Unit {
+	if ai.Target.CurrentTarget != nil {
+		return ai.Target.CurrentTarget
+	}
+	if targeting == TargetPlayer {
+		return &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+	return nil
+}
+
+func (ai *ScriptedAI) startPhase(sim *core.Simulation, phase int) {
+	ai.phase = phase
+
+	for _, ability := range ai.Abilities {
+		if len(ability.Phases) == 0 {
+			// Abilities used in every phase keep their timers across phase changes.
+			if phase == 0 {
+				ability.nextUseAt = sim.CurrentTime + ability.InitialDelay
+			}
+		} else if ability.inPhase(phase) {
+			ability.nextUseAt = sim.CurrentTime + ability.InitialDelay
+		} else {
+			ability.nextUseAt = core.NeverExpires
+		}
+	}
+
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Entering %s", ai.Phases[phase].Name)
+	}
+
+	if ai.Phases[phase].OnStart != nil {
+		ai.Phases[phase].OnStart(sim, ai)
+	}
+}
+
+func (ai *ScriptedAI) updatePhase(sim *core.Simulation) {
+	if ai.phase == -1 {
+		ai.startPhase(sim, 0)
+	}
+
+	for ai.phase+1 < len(ai.Phases) {
+		next := ai.Phases[ai.phase+1]
+		reachedTime := next.StartAt > 0 && sim.CurrentTime >= next.StartAt
+		reachedHealth := next.StartAtHealthPercent > 0 && ai.HealthPercent(sim) <= next.StartAtHealthPercent
+		if !reachedTime && !reachedHealth {
+			return
+		}
+		ai.startPhase(sim, ai.phase+1)
+	}
+}
+
+func (ability *ScriptedAbility) inPhase(phase int) bool {
+	for _, p := range ability.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+func (ai *ScriptedAI) ExecuteCustomRotation(sim *core.Simulation) {
+	ai.updatePhase(sim)
+
+	for _, ability := range ai.Abilities {
+		if ability.nextUseAt > sim.CurrentTime {
+			continue
+		}
+
+		if ability.Condition != nil && !ability.Condition(sim, ai) {
+			continue
+		}
+
+		target := ai.GetTarget(ability.Targeting)
+		if target == nil || !ability.Spell.CanCast(sim, target) {
+			continue
+		}
+
+		ability.Spell.Cast(sim, target)
+		if ability.Period > 0 {
+			ability.nextUseAt = sim.CurrentTime + ability.Period
+		} else {
+			ability.nextUseAt = core.NeverExpires
+		}
+
+		// Abilities with a cast time or GCD hand control back once they finish.
+		if !ai.Target.GCD.IsReady(sim) || ai.Target.Hardcast.Expires > sim.CurrentTime {
+			return
+		}
+	}
+
+	ai.Target.WaitUntil(sim, ai.nextActionAt(sim))
+}
+
+// The next time an ability may become usable or a phase may change.
+func (ai *ScriptedAI) nextActionAt(sim *core.Simulation) time.Duration {
+	nextActionAt := core.NeverExpires
+
+	if ai.phase+1 < len(ai.Phases) {
+		next := ai.Phases[ai.phase+1]
+		if next.StartAt > 0 {
+			nextActionAt = min(nextActionAt, next.StartAt)
+		}
+		if next.StartAtHealthPercent > 0 {
+			nextActionAt = min(nextActionAt, sim.CurrentTime+BossGCD)
+		}
+	}
+
+	for _, ability := range ai.Abilities {
+		if ability.nextUseAt == core.NeverExpires {
+			continue
+		}
+		readyAt := max(ability.nextUseAt, ability.Spell.ReadyAt())
+		if readyAt <= sim.CurrentTime {
+			// Blocked by something other than its timer, so check again shortly.
+			readyAt = sim.CurrentTime + BossGCD
+		}
+		nextActionAt = min(nextActionAt, readyAt)
+	}
+
+	return max(nextActionAt, sim.CurrentTime+time.Millisecond)
+}
+
+type ScriptedDamageConfig struct {
+	ActionID    core.ActionID
+	SpellSchool core.SpellSchool
+	CastTime    time.Duration
+
+	MinDamage float64
+	MaxDamage float64
+
+	// Hits every player instead of only the spell's target.
+	RaidWide bool
+}
+
+// Makes a direct damage spell for a scripted ability. Physical spells are
+// melee attacks which can be avoided, others are spells which can be resisted.
+func (ai *ScriptedAI) MakeDamageSpell(config ScriptedDamageConfig) *core.Spell {
+	isPhysical := config.SpellSchool == core.SpellSchoolPhysical
+
+	defenseType := core.DefenseTypeMagic
+	procMask := core.ProcMaskSpellDamage
+	if isPhysical {
+		defenseType = core.DefenseTypeMelee
+		procMask = core.ProcMaskMeleeMHSpecial
+	}
+
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:    config.ActionID,
+		SpellSchool: config.SpellSchool,
+		DefenseType: defenseType,
+		ProcMask:    procMask,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				CastTime: config.CastTime,
+			},
+		},
+
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			targets := []*core.Unit{target}
+			if config.RaidWide {
+				targets = sim.Raid.AllPlayerUnits
+			}
+
+			for _, target := range targets {
+				baseDamage := sim.Roll(config.MinDamage, config.MaxDamage)
+				if isPhysical {
+					spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeEnemyMeleeWhite)
+				} else {
+					spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMagicHit)
+				}
+			}
+		},
+	})
+}
@@ -0,0 +1,223 @@
+package encounters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+	"github.com/wowsims/sod/sim/druid/tank"
+)
+
+const (
+	testBoltSpellID   = 1
+	testStrikeSpellID = 2
+	testFrenzySpellID = 3
+)
+
+func init() {
+	tank.RegisterFeralTankDruid()
+
+	AddSingleTargetBossEncounter(&core.PresetTarget{
+		PathPrefix: "Test",
+		Config: &proto.Target{
+			Id:        1,
+			Name:      "Scripted Boss",
+			Level:     63,
+			MobType:   proto.MobType_MobTypeUndead,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      2_000_000,
+				stats.Armor:       3731,
+				stats.AttackPower: 805,
+			}.ToFloatArray(),
+
+			SpellSchool:      proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:       2,
+			MinBaseDamage:    2000,
+			DamageSpread:     0.3333,
+			ParryHaste:       true,
+			DualWieldPenalty: false,
+			TargetInputs: []*proto.TargetInput{{
+				Label:     "Use Strike",
+				InputType: proto.InputType_Bool,
+			}},
+		},
+		AI: newTestScriptedAI(),
+	})
+}
+
+// A boss which casts a raid-wide bolt only after 2 minutes, and which uses a
+// tank strike when enabled through its input and frenzies below 5% health.
+func newTestScriptedAI() core.AIFactory {
+	return NewScriptedAI(func() *ScriptedAI {
+		useStrike := false
+
+		return &ScriptedAI{
+			Phases: []ScriptedPhase{
+				{Name: "Phase 1"},
+				{Name: "Bolts", StartAt: time.Minute * 2},
+				{
+					Name:                 "Frenzy",
+					StartAtHealthPercent: 0.05,
+					OnStart: func(sim *core.Simulation, ai *ScriptedAI) {
+						ai.Target.GetAura("Frenzy").Activate(sim)
+					},
+				},
+			},
+			Abilities: []*ScriptedAbility{
+				{
+					MakeSpell: func(ai *ScriptedAI) *core.Spell {
+						return ai.MakeDamageSpell(ScriptedDamageConfig{
+							ActionID:    core.ActionID{SpellID: testBoltSpellID},
+							SpellSchool: core.SpellSchoolShadow,
+							MinDamage:   1000,
+							MaxDamage:   1000,
+							RaidWide:    true,
+						})
+					},
+					Targeting: TargetPlayer,
+					Period:    time.Second * 30,
+					Phases:    []int{1, 2},
+				},
+				{
+					MakeSpell: func(ai *ScriptedAI) *core.Spell {
+						return ai.MakeDamageSpell(ScriptedDamageConfig{
+							ActionID:    core.ActionID{SpellID: testStrikeSpellID},
+							SpellSchool: core.SpellSchoolPhysical,
+							MinDamage:   2000,
+							MaxDamage:   3000,
+						})
+					},
+					Targeting: TargetTank,
+					Period:    time.Second * 2,
+					Condition: func(_ *core.Simulation, _ *ScriptedAI) bool {
+						return useStrike
+					},
+				},
+			},
+			OnInitialize: func(ai *ScriptedAI, config *proto.Target) {
+				if len(config.TargetInputs) > 0 {
+					useStrike = config.TargetInputs[0].BoolValue
+				}
+
+				ai.Target.RegisterAura(core.Aura{
+					ActionID: core.ActionID{SpellID: testFrenzySpellID},
+					Label:    "Frenzy",
+					Duration: core.NeverExpires,
+				})
+			},
+		}
+	})
+}
+
+func runBossSim(t *testing.T, path string, inputs []*proto.TargetInput, tanked bool, duration float64) *proto.RaidSimResult {
+	preset := core.GetPresetTargetWithPath(path)
+	if preset == nil {
+		t.Fatalf("No preset target with path %s", path)
+	}
+	target := preset.Config
+	if inputs != nil {
+		target.TargetInputs = inputs
+	}
+
+	request := &proto.RaidSimRequest{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{
+				Players: []*proto.Player{{
+					Name:      "Bear",
+					Class:     proto.Class_ClassDruid,
+					Race:      proto.Race_RaceTauren,
+					Level:     60,
+					Equipment: &proto.EquipmentSpec{},
+					Consumes:  &proto.Consumes{},
+					Buffs:     &proto.IndividualBuffs{},
+					Rotation:  &proto.APLRotation{Type: proto.APLRotation_TypeAPL},
+					Spec:      &proto.Player_FeralTankDruid{FeralTankDruid: &proto.FeralTankDruid{Options: &proto.FeralTankDruid_Options{}}},
+				}},
+				Buffs: &proto.PartyBuffs{},
+			}},
+			Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+		},
+		Encounter: &proto.Encounter{
+			Duration: duration,
+			Targets:  []*proto.Target{target},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 1,
+			RandomSeed: 101,
+		},
+	}
+	if !tanked {
+		request.Raid.Tanks = nil
+	}
+
+	result := core.RunRaidSim(request)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	return result
+}
+
+func targetActionDamage(result *proto.RaidSimResult, spellID int32) float64 {
+	damage := 0.0
+	for _, action := range result.EncounterMetrics.Targets[0].Actions {
+		if action.Id.GetSpellId() != spellID {
+			continue
+		}
+		for _, target := range action.Targets {
+			damage += target.Damage
+		}
+	}
+	return damage
+}
+
+func TestScriptedAITimedPhases(t *testing.T) {
+	path := "Test/Scripted Boss"
+
+	// The bolts are only cast once the second phase starts at 2 minutes.
+	if damage := targetActionDamage(runBossSim(t, path, nil, true, 100), testBoltSpellID); damage != 0 {
+		t.Fatalf("Expected no bolt damage before 2 minutes, got %0.1f", damage)
+	}
+	if damage := targetActionDamage(runBossSim(t, path, nil, true, 300), testBoltSpellID); damage <= 0 {
+		t.Fatalf("Expected bolt damage on the raid after 2 minutes")
+	}
+}
+
+func TestScriptedAIHealthPhases(t *testing.T) {
+	path := "Test/Scripted Boss"
+	if damage := targetActionDamage(runBossSim(t, path, nil, true, 300), testStrikeSpellID); damage != 0 {
+		t.Fatalf("Expected no strike damage without the input, got %0.1f", damage)
+	}
+
+	result := runBossSim(t, path, []*proto.TargetInput{
+		{InputType: proto.InputType_Bool, BoolValue: true},
+	}, true, 300)
+	if damage := targetActionDamage(result, testStrikeSpellID); damage <= 0 {
+		t.Fatalf("Expected strike damage when the input is enabled")
+	}
+
+	frenzyUptime := 0.0
+	for _, aura := range result.EncounterMetrics.Targets[0].Auras {
+		if aura.Id.GetSpellId() == testFrenzySpellID {
+			frenzyUptime = aura.UptimeSecondsAvg
+		}
+	}
+	// Frenzy starts at 5% health, i.e. the last 15 seconds of a 5 minute fight.
+	if frenzyUptime < 13 || frenzyUptime > 17 {
+		t.Fatalf("Expected about 15s of Frenzy, got %0.1fs", frenzyUptime)
+	}
+}
+
+func TestVaelastraszFireNovaOnlyWhenTanked(t *testing.T) {
+	path := "SoD/Blackwing Lair Vaelastrasz the Corrupt"
+
+	if damage := targetActionDamage(runBossSim(t, path, nil, true, 300), 23462); damage <= 0 {
+		t.Fatalf("Expected Fire Nova damage when the boss is tanked")
+	}
+	if damage := targetActionDamage(runBossSim(t, path, nil, false, 300), 23462); damage != 0 {
+		t.Fatalf("Expected no Fire Nova damage without a tank, got %0.1f", damage)
+	}
+}
@@ -36,12 +36,17 @@ func (hunter *Hunter) getVolleyConfig(rank int) core.SpellConfig {
 		manaCostModifer -= 50
 	}
 
+	// Volley is placed on the ground under the target.
+	var center core.Position
+
 	return core.SpellConfig{
 		SpellCode: SpellCode_HunterVolley,
 		ActionID:    core.ActionID{SpellID: spellId},
 		SpellSchool: core.SpellSchoolArcane,
 		ProcMask:    core.ProcMaskSpellDamage,
 		Flags:       core.SpellFlagChanneled | core.SpellFlagAPL,
+		MaxRange:    35,
+		Radius:      8,
 
 		RequiredLevel: level,
 		Rank:          rank,
@@ -76,7 +81,7 @@ func (hunter *Hunter) getVolleyConfig(rank int) core.SpellConfig {
 				dot.Snapshot(target, damage, isRollover)
 			},
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadius(center) {
 					dot.CalcAndDealPeriodicSnapshotDamage(sim, aoeTarget, dot.OutcomeTick)
 				}
 			},
@@ -91,6 +96,7 @@ func (hunter *Hunter) getVolleyConfig(rank int) core.SpellConfig {
 				spell.CD.Reset()
 			}
 			hunter.Unit.AutoAttacks.DelayRangedUntil(sim, sim.CurrentTime+(time.Second*6))
+			center = target.Position
 			spell.AOEDot().Apply(sim)
 		},
 	}
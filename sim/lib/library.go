@@ -24,7 +24,14 @@ var _default_rsr = proto.RaidSimRequest{
 	SimOptions: &proto.SimOptions{},
 }
 var _active_sim = core.NewSim(&_default_rsr, simsignals.Signals{})
+var _active_interactive_sim *core.InteractiveSim
+var _active_env *core.SimEnv
 var _active_seed int64 = 1
+
+// Snapshots of the active interactive sim. They can't be restored into any other sim, so they're
+// dropped whenever a new one is started.
+var _snapshots = map[int32]*core.SimSnapshot{}
+var _next_snapshot_id int32 = 1
 var _aura_labels = []string{}
 var _target_aura_labels = []string{}
 
@@ -144,42 +151,135 @@ func new(json *C.char) {
 		log.Fatalf("failed to load input json file: %s", err)
 	}
 	sim.RegisterAll()
-	_active_sim = core.NewSim(input, simsignals.Signals{})
-	_active_sim.Reseed(_active_seed)
+	_active_interactive_sim, err = core.NewInteractiveSim(input, _active_seed, -1)
+	if err != nil {
+		log.Fatalf("failed to create interactive sim: %s", err)
+	}
+	_active_sim = _active_interactive_sim.Simulation
+	_active_env = nil
 	_active_seed += 1
-	_active_sim.Reset()
-	_active_sim.PrePull()
+	clear(_snapshots)
+}
+
+// Starts a new iteration of an env, which controls a single player through a fixed action space,
+// and returns the EnvSpec describing the actions and observations as json.
+//
+//export envReset
+func envReset(json *C.char) *C.char {
+	input := &proto.EnvRequest{}
+	err := protojson.Unmarshal([]byte(C.GoString(json)), input)
+	if err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+	sim.RegisterAll()
+	_active_env, err = core.NewSimEnv(input, _active_seed)
+	if err != nil {
+		log.Fatalf("failed to create env: %s", err)
+	}
+	_active_interactive_sim = _active_env.InteractiveSim
+	_active_sim = _active_env.Simulation
+	_active_seed += 1
+	clear(_snapshots)
+	return marshalJson(_active_env.Spec())
+}
+
+// Takes an action, 0 to wait or i to cast EnvSpec.actions[i - 1], and returns the EnvStepResult as json.
+//
+//export envStep
+func envStep(action int32) *C.char {
+	result := _active_env.Step(action)
+	_active_sim = _active_env.Simulation
+	return marshalJson(result)
+}
+
+//export envObserve
+func envObserve() *C.char {
+	return marshalJson(_active_env.Observe())
+}
+
+// Writes the flattened observation, which has EnvSpec.observation_size values.
+//
+//export envObservationVector
+func envObservationVector(storage *float64, n int32) int32 {
+	vector := core.EnvObservationToVector(_active_env.Observe())
+	copy(unsafe.Slice(storage, n), vector)
+	return int32(len(vector))
+}
+
+// Builds an APL from an APLSynthesisRequest, e.g. with decisions recorded from an env, and returns
+// the APLSynthesisResult as json.
+//
+//export synthesizeAPL
+func synthesizeAPL(json *C.char) *C.char {
+	input := &proto.APLSynthesisRequest{}
+	if err := protojson.Unmarshal([]byte(C.GoString(json)), input); err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+	return marshalJson(core.SynthesizeAPL(input))
+}
+
+// Statically checks the APLs of a LintAPLRequest and returns the LintAPLResult as json.
+//
+//export lintAPL
+func lintAPL(json *C.char) *C.char {
+	input := &proto.LintAPLRequest{}
+	if err := protojson.Unmarshal([]byte(C.GoString(json)), input); err != nil {
+		log.Fatalf("failed to load input json file: %s", err)
+	}
+	return marshalJson(core.LintAPL(input))
+}
+
+func marshalJson(message goproto.Message) *C.char {
+	out, err := protojson.Marshal(message)
+	if err != nil {
+		panic(err)
+	}
+	return C.CString(string(out))
+}
+
+// The player controlled by the env, or the first player.
+func activePlayer() core.Agent {
+	if _active_interactive_sim != nil {
+		return _active_interactive_sim.Player()
+	}
+	return _active_sim.Raid.Parties[0].Players[0]
 }
 
 //export trySpell
 func trySpell(act int) bool {
-	player := _active_sim.Raid.Parties[0].Players[0]
-	spells := player.GetCharacter().Spellbook
-	if act >= len(spells) || act < 0 {
-		return false
-	}
-	spell := spells[act]
-	target := player.GetCharacter().CurrentTarget
-	casted := false
+	return _active_interactive_sim.ApplyInput(castSpellInput(act))
+}
 
-	// FIXME : This is a hack to allow Heroic strike to work
-	if spell.ActionID.SpellID == 47450 {
-		aura := player.GetCharacter().GetAura("HS Queue Aura")
-		if aura.IsActive() {
+func castSpellInput(act int) core.SimInput {
+	return func(sim *core.Simulation) bool {
+		player := sim.Raid.Parties[0].Players[0]
+		spells := player.GetCharacter().Spellbook
+		if act >= len(spells) || act < 0 {
 			return false
 		}
-		aura.Activate(_active_sim)
-		return true
-	}
-	// End of Heroic strike hack
+		spell := spells[act]
+		target := player.GetCharacter().CurrentTarget
+		casted := false
+
+		// FIXME : This is a hack to allow Heroic strike to work
+		if spell.ActionID.SpellID == 47450 {
+			aura := player.GetCharacter().GetAura("HS Queue Aura")
+			if aura.IsActive() {
+				return false
+			}
+			aura.Activate(sim)
+			return true
+		}
+		// End of Heroic strike hack
 
-	if spell.CanCast(_active_sim, target) {
-		casted = spell.Cast(_active_sim, target)
-		if casted && spell.CurCast.GCD > 0 {
-			_active_sim.NeedsInput = false
+		if spell.CanCast(sim, target) {
+			casted = spell.Cast(sim, target)
+			if casted && spell.CurCast.GCD > 0 {
+				sim.NeedsInput = false
+			}
 		}
+		return casted
 	}
-	return casted
 }
 
 //export doNothing
@@ -194,7 +294,7 @@ func getRemainingDuration() float64 {
 
 //export getEnergy
 func getEnergy() float64 {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	if !player.GetCharacter().HasEnergyBar() {
 		return 0.0
 	}
@@ -203,7 +303,7 @@ func getEnergy() float64 {
 
 //export getComboPoints
 func getComboPoints() int {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	if !player.GetCharacter().HasEnergyBar() {
 		return 0
 	}
@@ -217,12 +317,12 @@ func getUnitCount() int {
 
 //export getSpellCount
 func getSpellCount() int {
-	return len(_active_sim.Raid.Parties[0].Players[0].GetCharacter().Spellbook)
+	return len(activePlayer().GetCharacter().Spellbook)
 }
 
 //export getSpells
 func getSpells(storage *int32, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	spellbook := player.GetCharacter().Spellbook
 	spells := unsafe.Slice(storage, n)
 	for i, spell := range spellbook[:n] {
@@ -237,7 +337,7 @@ func getSpells(storage *int32, n int32) {
 
 //export getCooldowns
 func getCooldowns(storage *float64, spellbookIndices *int32, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	spellbook := player.GetCharacter().Spellbook
 	spells := unsafe.Slice(spellbookIndices, n)
 	cds := unsafe.Slice(storage, n)
@@ -268,7 +368,7 @@ func registerTargetAuras(strings **C.char) {
 
 //export getAuras
 func getAuras(storage *float64, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	auras := unsafe.Slice(storage, n)
 	for i, label := range _aura_labels {
 		aura := player.GetCharacter().GetAura(label)
@@ -282,7 +382,7 @@ func getAuras(storage *float64, n int32) {
 
 //export getTargetAuras
 func getTargetAuras(storage *float64, n int32) {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	target := player.GetCharacter().CurrentTarget
 	auras := unsafe.Slice(storage, n)
 	for i, label := range _target_aura_labels {
@@ -297,7 +397,7 @@ func getTargetAuras(storage *float64, n int32) {
 
 //export getDamageDone
 func getDamageDone() float64 {
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	spellbook := player.GetCharacter().Spellbook
 	totalDamage := 0.0
 	for _, spell := range spellbook {
@@ -311,7 +411,7 @@ func getDamageDone() float64 {
 //export getSpellMetrics
 func getSpellMetrics() *C.char {
 	all_metrics := make(map[int32][]core.SpellMetrics)
-	player := _active_sim.Raid.Parties[0].Players[0]
+	player := activePlayer()
 	spellbook := player.GetCharacter().Spellbook
 	for _, spell := range spellbook {
 		spell_id := spell.ActionID.SpellID
@@ -330,7 +430,45 @@ func getSpellMetrics() *C.char {
 
 //export step
 func step() bool {
-	return _active_sim.Step()
+	return _active_interactive_sim.Step()
+}
+
+// Saves the current state of the active sim, returning an ID to pass to restore. Starting a new
+// sim or env frees every snapshot.
+//
+//export snapshot
+func snapshot() int32 {
+	id := _next_snapshot_id
+	_next_snapshot_id++
+	_snapshots[id] = _active_interactive_sim.Snapshot()
+	return id
+}
+
+// Puts the active sim back into the state saved by snapshot. Snapshots can be restored any
+// number of times, until they're freed. Returns false if the snapshot doesn't exist.
+//
+//export restore
+func restore(id int32) bool {
+	snapshot, ok := _snapshots[id]
+	if !ok {
+		return false
+	}
+	var err error
+	if _active_env != nil {
+		err = _active_env.Restore(snapshot)
+	} else {
+		err = _active_interactive_sim.Restore(snapshot)
+	}
+	if err != nil {
+		log.Printf("failed to restore snapshot %d: %s", id, err)
+		return false
+	}
+	return true
+}
+
+//export freeSnapshot
+func freeSnapshot(id int32) {
+	delete(_snapshots, id)
 }
 
 //export needsInput
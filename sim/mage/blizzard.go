@@ -57,11 +57,16 @@ func (mage *Mage) newBlizzardSpellConfig(rank int) core.SpellConfig {
 		})
 	}
 
+	// Blizzard is placed on the ground under the target.
+	var center core.Position
+
 	return core.SpellConfig{
 		ActionID:    core.ActionID{SpellID: spellId},
 		SpellSchool: core.SpellSchoolFrost,
 		ProcMask:    core.ProcMaskSpellDamage,
 		Flags:       SpellFlagMage | core.SpellFlagChanneled | core.SpellFlagAPL,
+		MaxRange:    30,
+		Radius:      8,
 
 		RequiredLevel: level,
 		Rank:          rank,
@@ -87,7 +92,7 @@ func (mage *Mage) newBlizzardSpellConfig(rank int) core.SpellConfig {
 				dot.Snapshot(target, baseDamage, isRollover)
 			},
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadius(center) {
 					dot.CalcAndDealPeriodicSnapshotDamage(sim, aoeTarget, dot.OutcomeTick)
 
 					if improvedBlizzardProcApplication != nil {
@@ -101,6 +106,7 @@ func (mage *Mage) newBlizzardSpellConfig(rank int) core.SpellConfig {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			center = target.Position
 			spell.AOEDot().Apply(sim)
 		},
 	}
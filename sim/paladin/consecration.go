@@ -41,12 +41,16 @@ func (paladin *Paladin) registerConsecration() {
 			break
 		}
 
+		// Consecration is placed on the ground under the paladin, who fights next
+		// to the target, so it is centered on the target like Blizzard.
+		var center core.Position
 		paladin.RegisterSpell(core.SpellConfig{
 			ActionID:    core.ActionID{SpellID: rank.spellID},
 			SpellSchool: core.SpellSchoolHoly,
 			DefenseType: core.DefenseTypeMagic,
 			ProcMask:    core.ProcMaskSpellDamage,
 			Flags:       core.SpellFlagPureDot | core.SpellFlagAPL,
+			Radius:      8,
 
 			RequiredLevel: int(rank.level),
 			Rank:          i + 1,
@@ -86,13 +90,14 @@ func (paladin *Paladin) registerConsecration() {
 					// Consecration can miss, showing up as either a resist in logs or a
 					// silent failure (missing damage tick).
 					outcomeApplier := core.Ternary(hasWrath, dot.OutcomeMagicHitAndSnapshotCrit, dot.Spell.OutcomeMagicHit)
-					for _, aoeTarget := range sim.Encounter.TargetUnits {
+					for _, aoeTarget := range dot.Spell.TargetsInRadius(center) {
 						dot.CalcAndDealPeriodicSnapshotDamage(sim, aoeTarget, outcomeApplier)
 					}
 				},
 			},
 
 			ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+				center = target.Position
 				spell.AOEDot().Apply(sim)
 			},
 		})
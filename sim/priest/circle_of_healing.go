@@ -1,40 +1,52 @@
 package priest
 
-// func (priest *Priest) registerCircleOfHealingSpell() {
-// 	targets := priest.Env.Raid.GetFirstNPlayersOrPets(5)
-
-// 	priest.CircleOfHealing = priest.RegisterSpell(core.SpellConfig{
-// 		ActionID:    core.ActionID{SpellID: 401946},
-// 		SpellSchool: core.SpellSchoolHoly,
-//      DefenseType: core.DefenseTypeMagic,
-// 		ProcMask:    core.ProcMaskSpellHealing,
-// 		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
-
-// 		ManaCost: core.ManaCostOptions{
-// 			BaseCost:   0.21,
-// 			Multiplier: 1,
-// 		},
-// 		Cast: core.CastConfig{
-// 			DefaultCast: core.Cast{
-// 				GCD: core.GCDDefault,
-// 			},
-// 			CD: core.Cooldown{
-// 				Timer:    priest.NewTimer(),
-// 				Duration: time.Second * 6,
-// 			},
-// 		},
-
-// 		BonusCritRating:  float64(priest.Talents.HolySpecialization) * 1 * core.CritRatingPerCritChance,
-
-// 		DamageMultiplier: 1 + .02*float64(priest.Talents.SpiritualHealing),
-// 		ThreatMultiplier: 1 - []float64{0, .07, .14, .20}[priest.Talents.SilentResolve],
-
-// 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-// 			healFromSP := 0.4029 * spell.HealingPower(target)
-// 			for _, aoeTarget := range targets {
-// 				baseHealing := sim.Roll(958, 1058) + healFromSP
-// 				spell.CalcAndDealHealing(sim, aoeTarget, baseHealing, spell.OutcomeHealingCrit)
-// 			}
-// 		},
-// 	})
-// }
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+// https://www.wowhead.com/classic/spell=401946/circle-of-healing
+func (priest *Priest) registerCircleOfHealingSpell() {
+	if !priest.HasRune(proto.PriestRune_RuneHandsCircleOfHealing) {
+		return
+	}
+
+	baseHealingLow := priest.baseRuneAbilityHealing() * 0.51
+	baseHealingHigh := priest.baseRuneAbilityHealing() * 0.563
+	spellCoeff := 0.214
+
+	priest.CircleOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: int32(proto.PriestRune_RuneHandsCircleOfHealing)},
+		SpellSchool: core.SpellSchoolHoly,
+		DefenseType: core.DefenseTypeMagic,
+		ProcMask:    core.ProcMaskSpellHealing,
+		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost: 0.21,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 6,
+			},
+		},
+
+		BonusCoefficient: spellCoeff,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			// Parties hold at most 5 players, so this is always within the 5 target cap.
+			for _, agent := range priest.Env.Raid.GetPlayerParty(target).Players {
+				spell.CalcAndDealHealing(sim, &agent.GetCharacter().Unit, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
+			}
+		},
+	})
+}
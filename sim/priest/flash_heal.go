@@ -1,33 +1,68 @@
 package priest
 
-// func (priest *Priest) registerFlashHealSpell() {
-// 	spellCoeff := 0.8057
-
-// 	priest.FlashHeal = priest.RegisterSpell(core.SpellConfig{
-// 		ActionID:    core.ActionID{SpellID: 10917},
-// 		SpellSchool: core.SpellSchoolHoly,
-//      DefenseType: core.DefenseTypeMagic,
-// 		ProcMask:    core.ProcMaskSpellHealing,
-// 		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
-
-// 		ManaCost: core.ManaCostOptions{
-// 			BaseCost: 0.18,
-// 		},
-// 		Cast: core.CastConfig{
-// 			DefaultCast: core.Cast{
-// 				GCD:      core.GCDDefault,
-// 				CastTime: time.Millisecond * 1500,
-// 			},
-// 		},
-
-// 		BonusCritRating:  float64(priest.Talents.HolySpecialization) * 1 * core.CritRatingPerCritChance,
-
-// 		DamageMultiplier: 1 + .02*float64(priest.Talents.SpiritualHealing),
-// 		ThreatMultiplier: 1 - []float64{0, .07, .14, .20}[priest.Talents.SilentResolve],
-
-// 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-// 			baseHealing := sim.Roll(1896, 2203) + spellCoeff*spell.HealingPower(target)
-// 			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
-// 		},
-// 	})
-// }
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+)
+
+const FlashHealRanks = 7
+
+var FlashHealSpellId = [FlashHealRanks + 1]int32{0, 2061, 9472, 9473, 9474, 10915, 10916, 10917}
+var FlashHealBaseHealing = [FlashHealRanks + 1][]float64{{0}, {193, 237}, {258, 314}, {327, 393}, {400, 478}, {518, 616}, {644, 764}, {812, 958}}
+var FlashHealSpellCoef = [FlashHealRanks + 1]float64{0, 0.429, 0.429, 0.429, 0.429, 0.429, 0.429, 0.429}
+var FlashHealManaCost = [FlashHealRanks + 1]float64{0, 125, 155, 185, 215, 265, 315, 380}
+var FlashHealLevel = [FlashHealRanks + 1]int{0, 20, 26, 32, 38, 44, 50, 56}
+
+func (priest *Priest) registerFlashHealSpell() {
+	priest.FlashHeal = make([]*core.Spell, FlashHealRanks+1)
+
+	for rank := 1; rank <= FlashHealRanks; rank++ {
+		config := priest.getFlashHealBaseConfig(rank)
+
+		if config.RequiredLevel <= int(priest.Level) {
+			priest.FlashHeal[rank] = priest.GetOrRegisterSpell(config)
+		}
+	}
+}
+
+func (priest *Priest) getFlashHealBaseConfig(rank int) core.SpellConfig {
+	spellId := FlashHealSpellId[rank]
+	baseHealingLow := FlashHealBaseHealing[rank][0]
+	baseHealingHigh := FlashHealBaseHealing[rank][1]
+	spellCoeff := FlashHealSpellCoef[rank]
+	manaCost := FlashHealManaCost[rank]
+	level := FlashHealLevel[rank]
+
+	return core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: spellId},
+		SpellCode:   SpellCode_PriestFlashHeal,
+		SpellSchool: core.SpellSchoolHoly,
+		DefenseType: core.DefenseTypeMagic,
+		ProcMask:    core.ProcMaskSpellHealing,
+		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		RequiredLevel: level,
+		Rank:          rank,
+
+		ManaCost: core.ManaCostOptions{
+			FlatCost: manaCost,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		BonusCoefficient: spellCoeff,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := sim.Roll(baseHealingLow, baseHealingHigh)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	}
+}
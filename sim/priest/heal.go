@@ -0,0 +1,69 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+)
+
+const HealRanks = 4
+
+var HealSpellId = [HealRanks + 1]int32{0, 2054, 2055, 6063, 6064}
+var HealBaseHealing = [HealRanks + 1][]float64{{0}, {295, 341}, {429, 491}, {566, 642}, {712, 804}}
+var HealSpellCoef = [HealRanks + 1]float64{0, 0.729, 0.857, 0.857, 0.857}
+var HealManaCost = [HealRanks + 1]float64{0, 155, 205, 255, 305}
+var HealLevel = [HealRanks + 1]int{0, 16, 22, 28, 34}
+
+func (priest *Priest) registerHealSpell() {
+	priest.Heal = make([]*core.Spell, HealRanks+1)
+
+	for rank := 1; rank <= HealRanks; rank++ {
+		config := priest.getHealBaseConfig(rank)
+
+		if config.RequiredLevel <= int(priest.Level) {
+			priest.Heal[rank] = priest.GetOrRegisterSpell(config)
+		}
+	}
+}
+
+func (priest *Priest) getHealBaseConfig(rank int) core.SpellConfig {
+	spellId := HealSpellId[rank]
+	baseHealingLow := HealBaseHealing[rank][0]
+	baseHealingHigh := HealBaseHealing[rank][1]
+	spellCoeff := HealSpellCoef[rank]
+	manaCost := HealManaCost[rank]
+	level := HealLevel[rank]
+
+	return core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: spellId},
+		SpellCode:   SpellCode_PriestHeal,
+		SpellSchool: core.SpellSchoolHoly,
+		DefenseType: core.DefenseTypeMagic,
+		ProcMask:    core.ProcMaskSpellHealing,
+		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		RequiredLevel: level,
+		Rank:          rank,
+
+		ManaCost: core.ManaCostOptions{
+			FlatCost:   manaCost,
+			Multiplier: 100 - 5*priest.Talents.ImprovedHealing,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Second*3 - time.Millisecond*100*time.Duration(priest.Talents.DivineFury),
+			},
+		},
+
+		BonusCoefficient: spellCoeff,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := sim.Roll(baseHealingLow, baseHealingHigh)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	}
+}
@@ -1,7 +1,10 @@
 package healing
 
 import (
+	"testing"
+
 	_ "github.com/wowsims/sod/sim/common" // imported to get caster sets included.
+	"github.com/wowsims/sod/sim/core"
 	"github.com/wowsims/sod/sim/core/proto"
 )
 
@@ -9,92 +12,81 @@ func init() {
 	RegisterHealingPriest()
 }
 
-// TODO: Classic
-// func TestDisc(t *testing.T) {
-// 	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
-// 		Class:    proto.Class_ClassPriest,
-// 		Race:     proto.Race_RaceUndead,
-// 		IsHealer: true,
-
-// 		GearSet:     core.GetGearSet("../../../ui/healing_priest/gear_sets", "p1_disc"),
-// 		Talents:     DiscTalents,
-// 		Consumes:    FullConsumes,
-// 		SpecOptions: core.SpecOptionsCombo{Label: "Disc", SpecOptions: PlayerOptionsDisc},
-// 		Rotation:    core.GetAplRotation("../../../ui/healing_priest/apls", "disc"),
+func TestHoly(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator([]core.CharacterSuiteConfig{
+		{
+			Class:      proto.Class_ClassPriest,
+			Level:      25,
+			Race:       proto.Race_RaceTroll,
+			OtherRaces: []proto.Race{proto.Race_RaceDwarf},
+			IsHealer:   true,
 
-// 		ItemFilter: core.ItemFilter{
-// 			WeaponTypes: []proto.WeaponType{
-// 				proto.WeaponType_WeaponTypeDagger,
-// 				proto.WeaponType_WeaponTypeMace,
-// 				proto.WeaponType_WeaponTypeOffHand,
-// 				proto.WeaponType_WeaponTypeStaff,
-// 			},
-// 			ArmorType: proto.ArmorType_ArmorTypeCloth,
-// 			RangedWeaponTypes: []proto.RangedWeaponType{
-// 				proto.RangedWeaponType_RangedWeaponTypeWand,
-// 			},
-// 		},
+			Talents:     Phase1Talents,
+			GearSet:     core.GetGearSet("../../../ui/healing_priest/gear_sets", "phase_1"),
+			Rotation:    core.GetAplRotation("../../../ui/healing_priest/apls", "phase_1"),
+			Buffs:       core.FullBuffsPhase1,
+			Consumes:    Phase1Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Holy", SpecOptions: PlayerOptionsHoly},
 
-// 		EPReferenceStat: proto.Stat_StatSpellPower,
-// 		StatsToWeigh: []proto.Stat{
-// 			proto.Stat_StatIntellect,
-// 			proto.Stat_StatSpellPower,
-// 			proto.Stat_StatSpellHaste,
-// 			proto.Stat_StatSpellCrit,
-// 		},
-// 	}))
-// }
-
-// func TestHoly(t *testing.T) {
-// 	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
-// 		Class:    proto.Class_ClassPriest,
-// 		Race:     proto.Race_RaceUndead,
-// 		IsHealer: true,
+			ItemFilter: ItemFilters,
+		},
+		{
+			Class:      proto.Class_ClassPriest,
+			Level:      60,
+			Phase:      4,
+			Race:       proto.Race_RaceTroll,
+			OtherRaces: []proto.Race{proto.Race_RaceDwarf},
+			IsHealer:   true,
 
-// 		GearSet:     core.GetGearSet("../../../ui/healing_priest/gear_sets", "p1_holy"),
-// 		Talents:     HolyTalents,
-// 		Consumes:    FullConsumes,
-// 		SpecOptions: core.SpecOptionsCombo{Label: "Holy", SpecOptions: PlayerOptionsHoly},
-// 		Rotation:    core.GetAplRotation("../../../ui/healing_priest/apls", "holy"),
+			Talents:     Phase4Talents,
+			GearSet:     core.GetGearSet("../../../ui/healing_priest/gear_sets", "phase_4"),
+			Rotation:    core.GetAplRotation("../../../ui/healing_priest/apls", "phase_4"),
+			Buffs:       core.FullBuffsPhase4,
+			Consumes:    Phase4Consumes,
+			SpecOptions: core.SpecOptionsCombo{Label: "Holy", SpecOptions: PlayerOptionsHoly},
 
-// 		ItemFilter: core.ItemFilter{
-// 			WeaponTypes: []proto.WeaponType{
-// 				proto.WeaponType_WeaponTypeDagger,
-// 				proto.WeaponType_WeaponTypeMace,
-// 				proto.WeaponType_WeaponTypeOffHand,
-// 				proto.WeaponType_WeaponTypeStaff,
-// 			},
-// 			ArmorType: proto.ArmorType_ArmorTypeCloth,
-// 			RangedWeaponTypes: []proto.RangedWeaponType{
-// 				proto.RangedWeaponType_RangedWeaponTypeWand,
-// 			},
-// 		},
-// 	}))
-// }
+			ItemFilter: ItemFilters,
+		},
+	}))
+}
 
-var DiscTalents = "0503203130300512301313231251-2351010303"
-var HolyTalents = "05032031103-234051032002152530004311051"
+var Phase1Talents = "-03505003"
+var Phase4Talents = "0551001305-035050031300155"
 
-var FullConsumes = &proto.Consumes{
-	Flask: proto.Flask_FlaskUnknown,
-	Food:  proto.Food_FoodUnknown,
+var Phase1Consumes = core.ConsumesCombo{
+	Label: "P1-Consumes",
+	Consumes: &proto.Consumes{
+		DefaultPotion: proto.Potions_LesserManaPotion,
+		Food:          proto.Food_FoodSmokedSagefish,
+	},
 }
 
-var PlayerOptionsDisc = &proto.Player_HealingPriest{
-	HealingPriest: &proto.HealingPriest{
-		Options: &proto.HealingPriest_Options{
-			UseInnerFire:      true,
-			UseShadowfiend:    true,
-			RapturesPerMinute: 5,
-		},
+var Phase4Consumes = core.ConsumesCombo{
+	Label: "P4-Consumes",
+	Consumes: &proto.Consumes{
+		DefaultPotion:   proto.Potions_MajorManaPotion,
+		Flask:           proto.Flask_FlaskOfDistilledWisdom,
+		Food:            proto.Food_FoodNightfinSoup,
+		MainHandImbue:   proto.WeaponImbue_BrilliantManaOil,
+		ManaRegenElixir: proto.ManaRegenElixir_MagebloodPotion,
 	},
 }
 
 var PlayerOptionsHoly = &proto.Player_HealingPriest{
 	HealingPriest: &proto.HealingPriest{
-		Options: &proto.HealingPriest_Options{
-			UseInnerFire:   true,
-			UseShadowfiend: true,
-		},
+		Options: &proto.HealingPriest_Options{},
+	},
+}
+
+var ItemFilters = core.ItemFilter{
+	ArmorType: proto.ArmorType_ArmorTypeCloth,
+	WeaponTypes: []proto.WeaponType{
+		proto.WeaponType_WeaponTypeDagger,
+		proto.WeaponType_WeaponTypeMace,
+		proto.WeaponType_WeaponTypeOffHand,
+		proto.WeaponType_WeaponTypeStaff,
+	},
+	RangedWeaponTypes: []proto.RangedWeaponType{
+		proto.RangedWeaponType_RangedWeaponTypeWand,
 	},
 }
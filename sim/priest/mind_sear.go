@@ -37,11 +37,16 @@ func (priest *Priest) newMindSearSpellConfig(tickIdx int32) core.SpellConfig {
 
 	priest.MindSearTicks[tickIdx] = priest.newMindSearTickSpell(tickIdx)
 
+	// Mind Sear hits everything around the target.
+	var center core.Position
+
 	return core.SpellConfig{
 		ActionID:    core.ActionID{SpellID: spellId}.WithTag(tickIdx),
 		SpellSchool: core.SpellSchoolShadow,
 		ProcMask:    core.ProcMaskSpellDamage,
 		Flags:       flags,
+		MaxRange:    30,
+		Radius:      10,
 
 		ManaCost: core.ManaCostOptions{
 			BaseCost: manaCost,
@@ -61,7 +66,7 @@ func (priest *Priest) newMindSearSpellConfig(tickIdx int32) core.SpellConfig {
 			NumberOfTicks: numTicks,
 			TickLength:    tickLength,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				for _, aoeTarget := range sim.Encounter.TargetUnits {
+				for _, aoeTarget := range dot.Spell.TargetsInRadius(center) {
 					priest.MindSearTicks[tickIdx].Cast(sim, aoeTarget)
 					priest.MindSearTicks[tickIdx].SpellMetrics[target.UnitIndex].Casts -= 1
 				}
@@ -76,6 +81,7 @@ func (priest *Priest) newMindSearSpellConfig(tickIdx int32) core.SpellConfig {
 			priest.MindSearTicks[tickIdx].SpellMetrics[target.UnitIndex].Casts += 1
 
 			if result.Landed() {
+				center = target.Position
 				spell.AOEDot().Apply(sim)
 			}
 			spell.DealOutcome(sim, result)
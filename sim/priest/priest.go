@@ -24,6 +24,7 @@ const (
 	SpellCode_PriestMindBlast
 	SpellCode_PriestMindFlay
 	SpellCode_PriestMindSpike
+	SpellCode_PriestRenew
 	SpellCode_PriestShadowWordPain
 	SpellCode_PriestSmite
 	SpellCode_PriestVampiricTouch
@@ -43,6 +44,7 @@ type Priest struct {
 	EyeOfTheVoid      *core.Spell
 	FlashHeal         []*core.Spell
 	GreaterHeal       []*core.Spell
+	Heal              []*core.Spell
 	HolyFire          []*core.Spell
 	Homunculi         *core.Spell
 	InnerFocus        *core.Spell
@@ -119,11 +121,13 @@ func (priest *Priest) Initialize() {
 }
 
 func (priest *Priest) RegisterHealingSpells() {
-	// priest.registerFlashHealSpell()
-	// priest.registerGreaterHealSpell()
+	priest.registerHealSpell()
+	priest.registerFlashHealSpell()
+	priest.registerGreaterHealSpell()
+	priest.registerRenewSpell()
+	priest.registerCircleOfHealingSpell()
 	// priest.registerPowerWordShieldSpell()
 	// priest.registerPrayerOfHealingSpell()
-	// priest.registerRenewSpell()
 }
 
 func (priest *Priest) Reset(_ *core.Simulation) {
@@ -1,58 +1,82 @@
 package priest
 
-// func (priest *Priest) registerRenewSpell() {
-// 	actionID := core.ActionID{SpellID: 25315}
-
-// 	priest.Renew = priest.RegisterSpell(core.SpellConfig{
-// 		ActionID:    actionID,
-// 		SpellSchool: core.SpellSchoolHoly,
-// 		ProcMask:    core.ProcMaskSpellHealing,
-// 		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
-
-// 		ManaCost: core.ManaCostOptions{
-// 			BaseCost:   0.17,
-// 			Multiplier: 1,
-// 		},
-// 		Cast: core.CastConfig{
-// 			DefaultCast: core.Cast{
-// 				GCD: core.GCDDefault,
-// 			},
-// 		},
-
-// 		DamageMultiplier: priest.renewHealingMultiplier(),
-// 		ThreatMultiplier: 1 - []float64{0, .07, .14, .20}[priest.Talents.SilentResolve],
-
-// 		Hot: core.DotConfig{
-// 			Aura: core.Aura{
-// 				Label: "Renew",
-// 			},
-// 			NumberOfTicks: priest.renewTicks(),
-// 			TickLength:    time.Second * 3,
-// 			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
-// 				dot.SnapshotBaseDamage = 280
-// 				dot.SnapshotAttackerMultiplier = dot.Spell.CasterHealingMultiplier()
-// 			},
-// 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-// 				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
-// 			},
-// 		},
-
-// 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-// 			spell.Hot(target).Apply(sim)
-
-// 			if priest.EmpoweredRenew != nil {
-// 				priest.EmpoweredRenew.Cast(sim, target)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) renewTicks() int32 {
-// 	return 5
-// }
-
-// func (priest *Priest) renewHealingMultiplier() float64 {
-// 	return 1 *
-// 		(1 + .02*float64(priest.Talents.SpiritualHealing)) *
-// 		(1 + .05*float64(priest.Talents.ImprovedRenew))
-// }
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/sod/sim/core"
+)
+
+const RenewRanks = 10
+const RenewTicks = 5
+
+var RenewSpellId = [RenewRanks + 1]int32{0, 139, 6074, 6075, 6076, 6077, 6078, 10927, 10928, 10929, 25315}
+var RenewBaseHealing = [RenewRanks + 1]float64{0, 45, 100, 175, 245, 315, 400, 510, 650, 810, 970}
+var RenewSpellCoef = [RenewRanks + 1]float64{0, 0.55, 0.775, 1, 1, 1, 1, 1, 1, 1, 1}
+var RenewManaCost = [RenewRanks + 1]float64{0, 30, 65, 105, 140, 170, 205, 250, 305, 365, 410}
+var RenewLevel = [RenewRanks + 1]int{0, 8, 14, 20, 26, 32, 38, 44, 50, 56, 60}
+
+func (priest *Priest) registerRenewSpell() {
+	priest.Renew = make([]*core.Spell, RenewRanks+1)
+
+	for rank := 1; rank <= RenewRanks; rank++ {
+		config := priest.getRenewBaseConfig(rank)
+
+		if config.RequiredLevel <= int(priest.Level) {
+			priest.Renew[rank] = priest.GetOrRegisterSpell(config)
+		}
+	}
+}
+
+func (priest *Priest) getRenewBaseConfig(rank int) core.SpellConfig {
+	spellId := RenewSpellId[rank]
+	baseTickHealing := RenewBaseHealing[rank] / RenewTicks
+	spellCoeff := RenewSpellCoef[rank] / RenewTicks
+	manaCost := RenewManaCost[rank]
+	level := RenewLevel[rank]
+
+	return core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: spellId},
+		SpellCode:   SpellCode_PriestRenew,
+		SpellSchool: core.SpellSchoolHoly,
+		DefenseType: core.DefenseTypeMagic,
+		ProcMask:    core.ProcMaskSpellHealing,
+		Flags:       SpellFlagPriest | core.SpellFlagHelpful | core.SpellFlagAPL,
+
+		RequiredLevel: level,
+		Rank:          rank,
+
+		ManaCost: core.ManaCostOptions{
+			FlatCost: manaCost,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1 + .05*float64(priest.Talents.ImprovedRenew),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: fmt.Sprintf("Renew (Rank %d)", rank),
+			},
+			NumberOfTicks:    RenewTicks,
+			TickLength:       time.Second * 3,
+			BonusCoefficient: spellCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				dot.SnapshotHeal(target, baseTickHealing, isRollover)
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.SpellMetrics[target.UnitIndex].Hits++
+			spell.Hot(target).Apply(sim)
+		},
+	}
+}
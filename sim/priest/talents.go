@@ -32,6 +32,7 @@ func (priest *Priest) ApplyTalents() {
 	priest.applyInspiration()
 	priest.applyHolySpecialization()
 	priest.applySearingLight()
+	priest.applySpiritualHealing()
 
 	priest.PseudoStats.SchoolDamageTakenMultiplier.MultiplyMagicSchools(1 - 0.02*float64(priest.Talents.SpellWarding))
 
@@ -130,6 +131,20 @@ func (priest *Priest) applySearingLight() {
 	})
 }
 
+func (priest *Priest) applySpiritualHealing() {
+	if priest.Talents.SpiritualHealing == 0 {
+		return
+	}
+
+	modifier := 0.02 * float64(priest.Talents.SpiritualHealing)
+
+	priest.OnSpellRegistered(func(spell *core.Spell) {
+		if spell.Flags.Matches(SpellFlagPriest) && spell.ProcMask.Matches(core.ProcMaskSpellHealing) {
+			spell.DamageMultiplierAdditive += modifier
+		}
+	})
+}
+
 func (priest *Priest) applySpiritTap() {
 	if priest.Talents.SpiritTap == 0 {
 		return
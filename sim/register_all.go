@@ -11,8 +11,8 @@ import (
 	"github.com/wowsims/sod/sim/shaman/warden"
 
 	"github.com/wowsims/sod/sim/druid/feral"
-	// restoDruid "github.com/wowsims/sod/sim/druid/restoration"
-	// feralTank "github.com/wowsims/sod/sim/druid/tank"
+	restoDruid "github.com/wowsims/sod/sim/druid/restoration"
+	feralTank "github.com/wowsims/sod/sim/druid/tank"
 	_ "github.com/wowsims/sod/sim/encounters"
 	"github.com/wowsims/sod/sim/hunter"
 	"github.com/wowsims/sod/sim/mage"
@@ -20,10 +20,10 @@ import (
 	// holyPaladin "github.com/wowsims/sod/sim/paladin/holy"
 	"github.com/wowsims/sod/sim/paladin/protection"
 	// "github.com/wowsims/sod/sim/paladin/retribution"
-	// healingPriest "github.com/wowsims/sod/sim/priest/healing"
+	healingPriest "github.com/wowsims/sod/sim/priest/healing"
 	"github.com/wowsims/sod/sim/priest/shadow"
 
-	// restoShaman "github.com/wowsims/sod/sim/shaman/restoration"
+	restoShaman "github.com/wowsims/sod/sim/shaman/restoration"
 	dpsWarlock "github.com/wowsims/sod/sim/warlock/dps"
 	tankWarlock "github.com/wowsims/sod/sim/warlock/tank"
 	dpsWarrior "github.com/wowsims/sod/sim/warrior/dps_warrior"
@@ -40,15 +40,15 @@ func RegisterAll() {
 
 	balance.RegisterBalanceDruid()
 	feral.RegisterFeralDruid()
-	// feralTank.RegisterFeralTankDruid()
-	// restoDruid.RegisterRestorationDruid()
+	feralTank.RegisterFeralTankDruid()
+	restoDruid.RegisterRestorationDruid()
 	elemental.RegisterElementalShaman()
 	enhancement.RegisterEnhancementShaman()
 	warden.RegisterWardenShaman()
-	// restoShaman.RegisterRestorationShaman()
+	restoShaman.RegisterRestorationShaman()
 	hunter.RegisterHunter()
 	mage.RegisterMage()
-	// healingPriest.RegisterHealingPriest()
+	healingPriest.RegisterHealingPriest()
 	shadow.RegisterShadowPriest()
 	dpsrogue.RegisterDpsRogue()
 	tankrogue.RegisterTankRogue()
@@ -85,10 +85,11 @@ func (shaman *Shaman) newHealingWaveSpellConfig(rank int, isOverload bool) core.
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			// TODO: Take Healing Way into account 6% stacking up to 3x
-			result := spell.CalcAndDealHealing(sim, spell.Unit, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
+			healTarget := shaman.healTarget(target)
+			result := spell.CalcAndDealHealing(sim, healTarget, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
 
 			if canOverload && sim.RandomFloat("HW Overload") < ShamanOverloadChance {
-				shaman.HealingWaveOverload[rank].Cast(sim, spell.Unit)
+				shaman.HealingWaveOverload[rank].Cast(sim, healTarget)
 			}
 
 			if result.Outcome.Matches(core.OutcomeCrit) {
@@ -97,7 +98,7 @@ func (shaman *Shaman) newHealingWaveSpellConfig(rank int, isOverload bool) core.
 
 					// TODO: this should actually target the lowest health target in the raid.
 					//  does it matter in a sim? We currently only simulate tanks taking damage (multiple tanks could be handled here though.)
-					shaman.AncestralAwakening.Cast(sim, spell.Unit)
+					shaman.AncestralAwakening.Cast(sim, healTarget)
 				}
 			}
 		},
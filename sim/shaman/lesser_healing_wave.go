@@ -87,7 +87,8 @@ func (shaman *Shaman) newLesserHealingWaveSpellConfig(rank int) core.SpellConfig
 		BonusCoefficient: spellCoeff,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			result := spell.CalcAndDealHealing(sim, spell.Unit, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
+			healTarget := shaman.healTarget(target)
+			result := spell.CalcAndDealHealing(sim, healTarget, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
 
 			if result.Outcome.Matches(core.OutcomeCrit) {
 				if shaman.HasRune(proto.ShamanRune_RuneFeetAncestralAwakening) {
@@ -95,7 +96,7 @@ func (shaman *Shaman) newLesserHealingWaveSpellConfig(rank int) core.SpellConfig
 
 					// TODO: this should actually target the lowest health target in the raid.
 					//  does it matter in a sim? We currently only simulate tanks taking damage (multiple tanks could be handled here though.)
-					shaman.AncestralAwakening.Cast(sim, spell.Unit)
+					shaman.AncestralAwakening.Cast(sim, healTarget)
 				}
 			}
 		},
@@ -23,45 +23,28 @@ func RegisterRestorationShaman() {
 	)
 }
 
-func NewRestorationShaman(character *core.Character, options *proto.Player) *RestorationShaman {
-	restoShamOptions := options.GetRestorationShaman()
+type RestorationShaman struct {
+	*shaman.Shaman
 
-	selfBuffs := shaman.SelfBuffs{
-		Shield: restoShamOptions.Options.Shield,
-	}
+	Options *proto.RestorationShaman_Options
+}
 
-	totems := &proto.ShamanTotems{}
-	if restoShamOptions.Options.Totems != nil {
-		totems = restoShamOptions.Options.Totems
-	}
+func NewRestorationShaman(character *core.Character, options *proto.Player) *RestorationShaman {
+	restoOptions := options.GetRestorationShaman()
 
 	resto := &RestorationShaman{
-		Shaman: shaman.NewShaman(character, options.TalentsString, totems, selfBuffs, false),
-	}
-
-	if resto.HasMHWeapon() {
-		resto.ApplyEarthlivingImbueToItem(resto.GetMHWeapon())
-	}
-	if resto.HasOHWeapon() {
-		resto.ApplyEarthlivingImbueToItem(resto.GetOHWeapon())
+		Shaman:  shaman.NewShaman(character, options.TalentsString),
+		Options: restoOptions.Options,
 	}
 
 	return resto
 }
 
-type RestorationShaman struct {
-	*shaman.Shaman
-}
-
 func (resto *RestorationShaman) GetShaman() *shaman.Shaman {
 	return resto.Shaman
 }
 
-func (resto *RestorationShaman) Reset(sim *core.Simulation) {
-	resto.Shaman.Reset(sim)
-}
 func (resto *RestorationShaman) GetMainTarget() *core.Unit {
-	// TODO: make this just grab first player that isn't self.
 	target := resto.Env.Raid.GetFirstTargetDummy()
 	if target == nil {
 		return &resto.Unit
@@ -72,17 +55,9 @@ func (resto *RestorationShaman) GetMainTarget() *core.Unit {
 
 func (resto *RestorationShaman) Initialize() {
 	resto.CurrentTarget = resto.GetMainTarget()
-
-	// Has to be here because earthliving can cast hots and needs Env to be set to create the hots.
-	procMask := core.ProcMaskUnknown
-	if resto.HasMHWeapon() {
-		procMask |= core.ProcMaskMeleeMH
-	}
-	if resto.HasOHWeapon() {
-		procMask |= core.ProcMaskMeleeOH
-	}
-	resto.RegisterEarthlivingImbue(procMask)
-
 	resto.Shaman.Initialize()
-	resto.Shaman.RegisterHealingSpells()
+}
+
+func (resto *RestorationShaman) Reset(sim *core.Simulation) {
+	resto.Shaman.Reset(sim)
 }
@@ -49,7 +49,7 @@ func (shaman *Shaman) registerRiptideSpell() {
 			TickLength:       time.Second * 3,
 			BonusCoefficient: hotCoeff,
 			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
-				dot.Snapshot(target, baseHotHealing, isRollover)
+				dot.SnapshotHeal(target, baseHotHealing, isRollover)
 			},
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
 				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
@@ -65,8 +65,9 @@ func (shaman *Shaman) registerRiptideSpell() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			spell.CalcAndDealHealing(sim, spell.Unit, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
-			spell.Hot(spell.Unit).Apply(sim)
+			healTarget := shaman.healTarget(target)
+			spell.CalcAndDealHealing(sim, healTarget, sim.Roll(baseHealingLow, baseHealingHigh), spell.OutcomeHealingCrit)
+			spell.Hot(healTarget).Apply(sim)
 		},
 	})
 }
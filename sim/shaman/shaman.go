@@ -241,6 +241,15 @@ func (shaman *Shaman) RegisterHealingSpells() {
 	shaman.registerChainHealSpell()
 }
 
+// Single target heals aimed at an enemy land on the shaman instead, which is how
+// the damage and tank specs use them.
+func (shaman *Shaman) healTarget(target *core.Unit) *core.Unit {
+	if target == nil || shaman.IsOpponent(target) {
+		return &shaman.Unit
+	}
+	return target
+}
+
 func (shaman *Shaman) HasRune(rune proto.ShamanRune) bool {
 	return shaman.HasRuneById(int32(rune))
 }
@@ -35,6 +35,7 @@ func (warlock *Warlock) getDarkPactConfig(rank int) core.SpellConfig {
 
 			warlock.ActivePet.SpendMana(sim, actualDrain, petManaMetrics)
 			warlock.AddMana(sim, actualDrain, manaMetrics)
+			spell.ApplyAOEThreat(sim, spell.FlatThreatBonus)
 		},
 	}
 }
@@ -25,12 +25,13 @@ func (warrior *Warrior) newShoutSpellConfig(actionID core.ActionID, rank int32,
 
 		FlatThreatBonus: float64(core.BattleShoutLevel[rank]),
 
-		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			for _, aura := range allyAuras {
 				if aura != nil {
 					aura.Activate(sim)
 				}
 			}
+			spell.ApplyAOEThreat(sim, spell.FlatThreatBonus)
 		},
 
 		RelatedAuras: []core.AuraArray{allyAuras},
@@ -37,6 +37,8 @@ func main() {
 	js.Global().Set("statWeightCompute", js.FuncOf(statWeightCompute))
 	js.Global().Set("statWeightsAsync", js.FuncOf(statWeightsAsync))
 	js.Global().Set("bulkSimAsync", js.FuncOf(bulkSimAsync))
+	js.Global().Set("gearOptimizerAsync", js.FuncOf(gearOptimizerAsync))
+	js.Global().Set("pairedSimAsync", js.FuncOf(pairedSimAsync))
 	js.Global().Set("abortById", js.FuncOf(abortById))
 	js.Global().Call("wasmready")
 	<-c
@@ -281,6 +283,44 @@ func bulkSimAsync(this js.Value, args []js.Value) interface{} {
 	return js.Undefined()
 }
 
+func gearOptimizerAsync(this js.Value, args []js.Value) interface{} {
+	request := &proto.GearOptimizerRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), request); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+
+	go core.RunGearOptimizerAsync(request, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func pairedSimAsync(this js.Value, args []js.Value) interface{} {
+	request := &proto.PairedSimRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), request); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+
+	go core.RunPairedSimAsync(request, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
 func raidSimRequestSplit(this js.Value, args []js.Value) interface{} {
 	splitRequest := &proto.RaidSimRequestSplitRequest{}
 	if err := googleProto.Unmarshal(getArgsBinary(args[0]), splitRequest); err != nil {
@@ -388,7 +428,7 @@ func processAsyncProgress(progFunc js.Value, reporter chan *proto.ProgressMetric
 			js.CopyBytesToJS(outArray, outbytes)
 			progFunc.Invoke(outArray)
 
-			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil {
+			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalGearOptimizerResult != nil || progMetric.FinalPairedResult != nil {
 				return
 			}
 		}
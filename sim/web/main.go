@@ -103,6 +103,15 @@ var handlers = map[string]apiHandler{
 	"/statWeightCompute": {msg: func() googleProto.Message { return &proto.StatWeightsCalcRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.StatWeightCompute(msg.(*proto.StatWeightsCalcRequest))
 	}},
+	"/pairedSim": {msg: func() googleProto.Message { return &proto.PairedSimRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunPairedSim(msg.(*proto.PairedSimRequest))
+	}},
+	"/synthesizeAPL": {msg: func() googleProto.Message { return &proto.APLSynthesisRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.SynthesizeAPL(msg.(*proto.APLSynthesisRequest))
+	}},
+	"/lintAPL": {msg: func() googleProto.Message { return &proto.LintAPLRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.LintAPL(msg.(*proto.LintAPLRequest))
+	}},
 	"/computeStats": {msg: func() googleProto.Message { return &proto.ComputeStatsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.ComputeStats(msg.(*proto.ComputeStatsRequest))
 	}},
@@ -123,6 +132,12 @@ var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
 	}},
+	"/pairedSimAsync": {msg: func() googleProto.Message { return &proto.PairedSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunPairedSimAsync(msg.(*proto.PairedSimRequest), reporter, requestId)
+	}},
+	"/gearOptimizerAsync": {msg: func() googleProto.Message { return &proto.GearOptimizerRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RunGearOptimizerAsync(msg.(*proto.GearOptimizerRequest), reporter, requestId)
+	}},
 }
 
 type server struct {
@@ -203,7 +218,7 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil {
+				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalGearOptimizerResult != nil || progMetric.FinalPairedResult != nil {
 					return
 				}
 			}
@@ -261,7 +276,7 @@ func (s *server) setupAsyncServer() {
 		}
 
 		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil {
+		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil || latest.FinalGearOptimizerResult != nil || latest.FinalPairedResult != nil {
 			s.progMut.Lock()
 			delete(s.asyncProgresses, msg.ProgressId)
 			s.progMut.Unlock()
@@ -0,0 +1,741 @@
+package database
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+)
+
+// Reads client tables exported to CSV by common DB2 exporters (e.g. wago.tools or DBC2CSV), with
+// one file per table named after it, e.g. ItemSparse.csv.
+//
+// ItemSparse, ItemEffect, SpellEffect and SpellItemEnchantment are required. Item (weapon and armor
+// types, icon file IDs), ItemSet (set names), ItemRandomProperties (random suffixes) and
+// ManifestInterfaceData (icon names) are optional, but without them the corresponding fields are left
+// empty.
+//
+// The client data doesn't know about content phases, sources, or which random suffixes an item can
+// roll, so those come from the previously generated database, the overrides and the AtlasLoot inputs.
+
+const (
+	db2ItemSparse            = "ItemSparse"
+	db2Item                  = "Item"
+	db2ItemEffect            = "ItemEffect"
+	db2ItemSet               = "ItemSet"
+	db2SpellEffect           = "SpellEffect"
+	db2SpellItemEnchantment  = "SpellItemEnchantment"
+	db2ManifestInterfaceData = "ManifestInterfaceData"
+	db2ItemRandomProperties  = "ItemRandomProperties"
+
+	db2ItemStatCount          = 10
+	db2EnchantEffectCount     = 3
+	db2RandomPropertyEnchants = 5
+	db2ItemEffectOnEquip      = 1
+	db2SpellEffectApplyAura   = 6
+
+	// Every class bit of ClassMask which exists in Classic.
+	db2AllClassesMask = uint16(ClassMaskWarrior | ClassMaskPaladin | ClassMaskHunter | ClassMaskRogue | ClassMaskPriest |
+		ClassMaskShaman | ClassMaskMage | ClassMaskWarlock | ClassMaskDruid)
+)
+
+type DB2Database struct {
+	Items map[int32]*proto.UIItem
+
+	// Keyed by effect ID. The client enchant table doesn't say which slot an enchant applies to, so these
+	// only carry names and stats, and are meant to be merged into the enchants listed in EnchantOverrides.
+	Enchants map[int32]*proto.UIEnchant
+
+	// Keyed by ItemRandomProperties ID, which is what items list as their random suffix options.
+	RandomSuffixes map[int32]*proto.ItemRandomSuffix
+}
+
+type db2Table struct {
+	name    string
+	headers map[string]int
+	rows    [][]string
+}
+
+func readDB2Table(dir string, name string, required bool) *db2Table {
+	path := filepath.Join(dir, name+".csv")
+	file, err := os.Open(path)
+	if err != nil {
+		if !required && os.IsNotExist(err) {
+			fmt.Printf("Optional table %s not found, skipping\n", path)
+			return nil
+		}
+		log.Fatalf("Cannot open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	rawHeaders, err := r.Read()
+	if err != nil {
+		log.Fatalf("Cannot read %s header row: %v", name, err)
+	}
+
+	table := &db2Table{name: name, headers: map[string]int{}}
+	for i, header := range rawHeaders {
+		table.headers[header] = i
+	}
+
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Cannot read %s row: %v", name, err)
+		}
+		table.rows = append(table.rows, row)
+	}
+
+	fmt.Printf("Loaded %d rows from %s\n", len(table.rows), path)
+	return table
+}
+
+func (table *db2Table) requireColumns(columns ...string) {
+	for _, column := range columns {
+		if !table.hasColumn(column) {
+			log.Fatalf("The %s csv does not have a %s header column. All columns: %#v", table.name, column, table.headers)
+		}
+	}
+}
+
+func (table *db2Table) hasColumn(column string) bool {
+	_, ok := table.headers[column]
+	return ok
+}
+
+// Returns the first of the given columns present in the table, for columns which are named
+// differently depending on the client build or exporter.
+func (table *db2Table) firstColumn(columns ...string) string {
+	for _, column := range columns {
+		if table.hasColumn(column) {
+			return column
+		}
+	}
+	return ""
+}
+
+func (table *db2Table) getString(row []string, column string) string {
+	if idx, ok := table.headers[column]; ok && idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
+
+// Missing columns and empty values read as 0. Some exporters write floats for integer columns and
+// signed values for bit masks, so everything is parsed as a float first.
+func (table *db2Table) getFloat(row []string, column string) float64 {
+	str := table.getString(row, column)
+	if str == "" {
+		return 0
+	}
+	val, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		log.Fatalf("Cannot parse %s.%s from row %v: %v", table.name, column, row, err)
+	}
+	return val
+}
+
+func (table *db2Table) getInt(row []string, column string) int {
+	return int(table.getFloat(row, column))
+}
+
+// ReadDB2Database reads items and enchants from the client tables in dir.
+func ReadDB2Database(dir string) *DB2Database {
+	spellEffects := readDB2Table(dir, db2SpellEffect, true)
+	spellEffects.requireColumns("SpellID", "Effect", "EffectAura", "EffectBasePoints", "EffectMiscValue_0")
+	parser := &db2Parser{
+		spellBonuses: parseDB2SpellBonuses(spellEffects),
+		equipSpells:  map[int32][]int32{},
+		itemClasses:  map[int32]db2ItemClass{},
+		setNames:     map[int32]string{},
+	}
+
+	itemEffects := readDB2Table(dir, db2ItemEffect, true)
+	itemEffects.requireColumns("ParentItemID", "TriggerType", "SpellID")
+	for _, row := range itemEffects.rows {
+		if itemEffects.getInt(row, "TriggerType") == db2ItemEffectOnEquip {
+			itemID := int32(itemEffects.getInt(row, "ParentItemID"))
+			parser.equipSpells[itemID] = append(parser.equipSpells[itemID], int32(itemEffects.getInt(row, "SpellID")))
+		}
+	}
+
+	iconNames := map[int]string{}
+	if manifest := readDB2Table(dir, db2ManifestInterfaceData, false); manifest != nil {
+		manifest.requireColumns("ID", "FileName")
+		for _, row := range manifest.rows {
+			// Icons are referred to by their lower case file name, without extension.
+			fileName := strings.ToLower(manifest.getString(row, "FileName"))
+			iconNames[manifest.getInt(row, "ID")] = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		}
+	}
+
+	if items := readDB2Table(dir, db2Item, false); items != nil {
+		items.requireColumns("ID", "ClassID", "SubclassID")
+		for _, row := range items.rows {
+			parser.itemClasses[int32(items.getInt(row, "ID"))] = db2ItemClass{
+				ClassID:    items.getInt(row, "ClassID"),
+				SubclassID: items.getInt(row, "SubclassID"),
+				Icon:       iconNames[items.getInt(row, "IconFileDataID")],
+			}
+		}
+	}
+
+	if itemSets := readDB2Table(dir, db2ItemSet, false); itemSets != nil {
+		itemSets.requireColumns("ID", "Name_lang")
+		for _, row := range itemSets.rows {
+			parser.setNames[int32(itemSets.getInt(row, "ID"))] = itemSets.getString(row, "Name_lang")
+		}
+	}
+
+	db := &DB2Database{
+		Items:          map[int32]*proto.UIItem{},
+		Enchants:       map[int32]*proto.UIEnchant{},
+		RandomSuffixes: map[int32]*proto.ItemRandomSuffix{},
+	}
+
+	itemSparse := readDB2Table(dir, db2ItemSparse, true)
+	itemSparse.requireColumns(itemIDHeader, "Display_lang", "InventoryType", "ItemLevel", "OverallQualityID")
+	for _, row := range itemSparse.rows {
+		if item := parser.itemToProto(itemSparse, row); item != nil {
+			db.Items[item.Id] = item
+		}
+	}
+
+	enchants := readDB2Table(dir, db2SpellItemEnchantment, true)
+	enchants.requireColumns("ID", "Name_lang")
+	for _, row := range enchants.rows {
+		enchant := parser.enchantToProto(enchants, row)
+		db.Enchants[enchant.EffectId] = enchant
+	}
+
+	if randomProperties := readDB2Table(dir, db2ItemRandomProperties, false); randomProperties != nil {
+		randomProperties.requireColumns("ID", "Name_lang")
+		for _, row := range randomProperties.rows {
+			suffix := db.randomSuffixToProto(randomProperties, row)
+			db.RandomSuffixes[suffix.Id] = suffix
+		}
+	}
+
+	fmt.Printf("\n--\nDB2 items loaded: %d\n--\n", len(db.Items))
+	fmt.Printf("\n--\nDB2 enchants loaded: %d\n--\n", len(db.Enchants))
+	fmt.Printf("\n--\nDB2 random suffixes loaded: %d\n--\n", len(db.RandomSuffixes))
+	return db
+}
+
+// Items missing from the existing database are new, so they're assumed to be from the first phase
+// unless the overrides say otherwise.
+const db2DefaultPhase = 1
+
+// ApplyExistingItemData copies what the client tables don't have from items in an existing database,
+// i.e. content phases and random suffix options. It's safe to pass nil when there's no existing database.
+func (db *DB2Database) ApplyExistingItemData(existing *WowDatabase) {
+	for id, item := range db.Items {
+		item.Phase = db2DefaultPhase
+		if existing == nil {
+			continue
+		}
+		if existingItem, ok := existing.Items[id]; ok {
+			if existingItem.Phase != 0 {
+				item.Phase = existingItem.Phase
+			}
+			item.RandomSuffixOptions = existingItem.RandomSuffixOptions
+		}
+	}
+}
+
+// A random suffix is a set of enchants, which have already been parsed from SpellItemEnchantment.
+func (db *DB2Database) randomSuffixToProto(table *db2Table, row []string) *proto.ItemRandomSuffix {
+	suffixStats := Stats{}
+	for i := 0; i < db2RandomPropertyEnchants; i++ {
+		if enchant, ok := db.Enchants[int32(table.getInt(row, fmt.Sprintf("Enchantment_%d", i)))]; ok {
+			for stat, value := range enchant.Stats {
+				suffixStats[stat] += value
+			}
+		}
+	}
+
+	return &proto.ItemRandomSuffix{
+		Id:    int32(table.getInt(row, "ID")),
+		Name:  table.getString(row, "Name_lang"),
+		Stats: toSlice(suffixStats),
+	}
+}
+
+// Lookups from the other tables, needed to build items and enchants.
+type db2Parser struct {
+	spellBonuses map[int32]*db2SpellBonus
+	equipSpells  map[int32][]int32
+	itemClasses  map[int32]db2ItemClass
+	setNames     map[int32]string
+}
+
+type db2ItemClass struct {
+	ClassID    int
+	SubclassID int
+	Icon       string
+}
+
+// Stat bonuses granted by the aura effects of a single spell.
+type db2SpellBonus struct {
+	Stats        Stats
+	WeaponSkills WeaponSkills
+}
+
+func (bonus *db2SpellBonus) addTo(itemStats *Stats, weaponSkills *WeaponSkills) {
+	for i := range itemStats {
+		itemStats[i] += bonus.Stats[i]
+	}
+	for i := range weaponSkills {
+		weaponSkills[i] += bonus.WeaponSkills[i]
+	}
+}
+
+const (
+	db2SchoolMaskHoly      = 2
+	db2SchoolMaskFire      = 4
+	db2SchoolMaskNature    = 8
+	db2SchoolMaskFrost     = 16
+	db2SchoolMaskShadow    = 32
+	db2SchoolMaskArcane    = 64
+	db2SchoolMaskAllMagic  = 126
+	db2ResistanceMaskArmor = 1
+	db2ModPowerRegenMana   = 0
+	db2ModStatAllStats     = -1
+	db2SkillLineDefense    = 95
+	db2AuraModResistance   = 22
+	db2AuraModStat         = 29
+	db2AuraModSkill        = 30
+	db2AuraModDamageDone   = 13
+	db2AuraModParry        = 47
+	db2AuraModDodge        = 49
+	db2AuraModBlock        = 51
+	db2AuraModCrit         = 52
+	db2AuraModHit          = 54
+	db2AuraModSpellHit     = 55
+	db2AuraModSpellCrit    = 57
+	db2AuraModCastingSpeed = 65
+	db2AuraModSchoolCrit   = 71
+	db2AuraModPowerRegen   = 85
+	db2AuraModAttackPower  = 99
+	db2AuraModTargetResist = 123
+	db2AuraModRangedAP     = 124
+	db2AuraModHealingDone  = 135
+	db2AuraModMeleeHaste   = 138
+	db2AuraModBlockValue   = 158
+)
+
+// SpellItemEnchantment effect types.
+const (
+	db2EnchantTypeEquipSpell = 3
+	db2EnchantTypeResistance = 4
+	db2EnchantTypeStat       = 5
+)
+
+var db2SchoolPowerStats = map[int]proto.Stat{
+	db2SchoolMaskHoly:   proto.Stat_StatHolyPower,
+	db2SchoolMaskFire:   proto.Stat_StatFirePower,
+	db2SchoolMaskNature: proto.Stat_StatNaturePower,
+	db2SchoolMaskFrost:  proto.Stat_StatFrostPower,
+	db2SchoolMaskShadow: proto.Stat_StatShadowPower,
+	db2SchoolMaskArcane: proto.Stat_StatArcanePower,
+}
+
+var db2SchoolResistanceStats = map[int]proto.Stat{
+	db2SchoolMaskFire:   proto.Stat_StatFireResistance,
+	db2SchoolMaskNature: proto.Stat_StatNatureResistance,
+	db2SchoolMaskFrost:  proto.Stat_StatFrostResistance,
+	db2SchoolMaskShadow: proto.Stat_StatShadowResistance,
+	db2SchoolMaskArcane: proto.Stat_StatArcaneResistance,
+}
+
+// Indexed by the misc value of SPELL_AURA_MOD_STAT.
+var db2PrimaryStats = []proto.Stat{
+	proto.Stat_StatStrength,
+	proto.Stat_StatAgility,
+	proto.Stat_StatStamina,
+	proto.Stat_StatIntellect,
+	proto.Stat_StatSpirit,
+}
+
+// ItemModType values used by ItemSparse stat columns and stat enchant effects.
+var db2ItemModStats = map[int]proto.Stat{
+	0: proto.Stat_StatMana,
+	1: proto.Stat_StatHealth,
+	3: proto.Stat_StatAgility,
+	4: proto.Stat_StatStrength,
+	5: proto.Stat_StatIntellect,
+	6: proto.Stat_StatSpirit,
+	7: proto.Stat_StatStamina,
+}
+
+// Keyed by SkillLine ID.
+var db2WeaponSkills = map[int]stats.WeaponSkill{
+	44:  stats.WeaponSkillAxes,
+	43:  stats.WeaponSkillSwords,
+	54:  stats.WeaponSkillMaces,
+	173: stats.WeaponSkillDaggers,
+	162: stats.WeaponSkillUnarmed,
+	172: stats.WeaponSkillTwoHandedAxes,
+	55:  stats.WeaponSkillTwoHandedSwords,
+	160: stats.WeaponSkillTwoHandedMaces,
+	229: stats.WeaponSkillPolearms,
+	136: stats.WeaponSkillStaves,
+	176: stats.WeaponSkillThrown,
+	45:  stats.WeaponSkillBows,
+	226: stats.WeaponSkillCrossbows,
+	46:  stats.WeaponSkillGuns,
+}
+
+// Keyed by SkillLine ID.
+var db2Professions = map[int]proto.Profession{
+	171: proto.Profession_Alchemy,
+	164: proto.Profession_Blacksmithing,
+	333: proto.Profession_Enchanting,
+	202: proto.Profession_Engineering,
+	182: proto.Profession_Herbalism,
+	165: proto.Profession_Leatherworking,
+	186: proto.Profession_Mining,
+	393: proto.Profession_Skinning,
+	197: proto.Profession_Tailoring,
+}
+
+// Adds value to every stat whose school bit is set in schoolMask.
+func addSchoolStats(itemStats *Stats, schoolStats map[int]proto.Stat, schoolMask int, value float64) {
+	for mask, stat := range schoolStats {
+		if schoolMask&mask != 0 {
+			itemStats[stat] += value
+		}
+	}
+}
+
+func parseDB2SpellBonuses(spellEffects *db2Table) map[int32]*db2SpellBonus {
+	bonuses := map[int32]*db2SpellBonus{}
+	healingDone := map[int32]float64{}
+
+	for _, row := range spellEffects.rows {
+		if spellEffects.getInt(row, "Effect") != db2SpellEffectApplyAura {
+			continue
+		}
+
+		spellID := int32(spellEffects.getInt(row, "SpellID"))
+		bonus, ok := bonuses[spellID]
+		if !ok {
+			bonus = &db2SpellBonus{}
+			bonuses[spellID] = bonus
+		}
+
+		// Effects roll BasePoints + [1, DieSides], which is a fixed value for every equip effect.
+		value := spellEffects.getFloat(row, "EffectBasePoints")
+		if dieSides := spellEffects.getFloat(row, "EffectDieSides"); dieSides > 0 {
+			value += (1 + dieSides) / 2
+		}
+		misc := spellEffects.getInt(row, "EffectMiscValue_0")
+
+		switch spellEffects.getInt(row, "EffectAura") {
+		case db2AuraModStat:
+			if misc == db2ModStatAllStats {
+				for _, stat := range db2PrimaryStats {
+					bonus.Stats[stat] += value
+				}
+			} else if misc >= 0 && misc < len(db2PrimaryStats) {
+				bonus.Stats[db2PrimaryStats[misc]] += value
+			}
+		case db2AuraModResistance:
+			if misc&db2ResistanceMaskArmor != 0 {
+				bonus.Stats[proto.Stat_StatBonusArmor] += value
+			}
+			addSchoolStats(&bonus.Stats, db2SchoolResistanceStats, misc, value)
+		case db2AuraModDamageDone:
+			if misc&db2SchoolMaskAllMagic == db2SchoolMaskAllMagic {
+				bonus.Stats[proto.Stat_StatSpellPower] += value
+			} else {
+				addSchoolStats(&bonus.Stats, db2SchoolPowerStats, misc, value)
+			}
+		case db2AuraModHealingDone:
+			healingDone[spellID] += value
+		case db2AuraModAttackPower:
+			bonus.Stats[proto.Stat_StatAttackPower] += value
+			bonus.Stats[proto.Stat_StatRangedAttackPower] += value
+		case db2AuraModRangedAP:
+			bonus.Stats[proto.Stat_StatRangedAttackPower] += value
+		case db2AuraModPowerRegen:
+			if misc == db2ModPowerRegenMana {
+				bonus.Stats[proto.Stat_StatMP5] += value
+			}
+		case db2AuraModHit:
+			bonus.Stats[proto.Stat_StatMeleeHit] += value
+		case db2AuraModSpellHit:
+			bonus.Stats[proto.Stat_StatSpellHit] += value
+		case db2AuraModCrit:
+			bonus.Stats[proto.Stat_StatMeleeCrit] += value
+		case db2AuraModSpellCrit:
+			bonus.Stats[proto.Stat_StatSpellCrit] += value
+		case db2AuraModSchoolCrit:
+			if misc&db2SchoolMaskAllMagic == db2SchoolMaskAllMagic {
+				bonus.Stats[proto.Stat_StatSpellCrit] += value
+			}
+		case db2AuraModMeleeHaste:
+			bonus.Stats[proto.Stat_StatMeleeHaste] += value
+		case db2AuraModCastingSpeed:
+			bonus.Stats[proto.Stat_StatSpellHaste] += math.Abs(value)
+		case db2AuraModTargetResist:
+			// Spell penetration is stored as a negative resistance modifier.
+			if misc&db2SchoolMaskAllMagic != 0 {
+				bonus.Stats[proto.Stat_StatSpellPenetration] += math.Abs(value)
+			}
+		case db2AuraModBlock:
+			bonus.Stats[proto.Stat_StatBlock] += value
+		case db2AuraModBlockValue:
+			bonus.Stats[proto.Stat_StatBlockValue] += value
+		case db2AuraModDodge:
+			bonus.Stats[proto.Stat_StatDodge] += value
+		case db2AuraModParry:
+			bonus.Stats[proto.Stat_StatParry] += value
+		case db2AuraModSkill:
+			if misc == db2SkillLineDefense {
+				bonus.Stats[proto.Stat_StatDefense] += value
+			} else if weaponSkill, ok := db2WeaponSkills[misc]; ok {
+				bonus.WeaponSkills[weaponSkill] += value
+			}
+		}
+	}
+
+	// Spells with both healing and damage bonuses, e.g. "Increases healing done by up to 44 and damage done
+	// by up to 15", already get the damage part as spell power, which also applies to healing.
+	for spellID, healing := range healingDone {
+		bonus := bonuses[spellID]
+		bonus.Stats[proto.Stat_StatHealingPower] += max(0, healing-bonus.Stats[proto.Stat_StatSpellPower])
+	}
+
+	return bonuses
+}
+
+type db2InventoryType struct {
+	ItemType proto.ItemType
+	HandType proto.HandType
+}
+
+// Keyed by the InventoryType column. Shirts, tabards, bags, ammo and quivers aren't simmed.
+var db2InventoryTypes = map[int]db2InventoryType{
+	1:  {ItemType: proto.ItemType_ItemTypeHead},
+	2:  {ItemType: proto.ItemType_ItemTypeNeck},
+	3:  {ItemType: proto.ItemType_ItemTypeShoulder},
+	5:  {ItemType: proto.ItemType_ItemTypeChest},
+	6:  {ItemType: proto.ItemType_ItemTypeWaist},
+	7:  {ItemType: proto.ItemType_ItemTypeLegs},
+	8:  {ItemType: proto.ItemType_ItemTypeFeet},
+	9:  {ItemType: proto.ItemType_ItemTypeWrist},
+	10: {ItemType: proto.ItemType_ItemTypeHands},
+	11: {ItemType: proto.ItemType_ItemTypeFinger},
+	12: {ItemType: proto.ItemType_ItemTypeTrinket},
+	13: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOneHand},
+	14: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOffHand},
+	15: {ItemType: proto.ItemType_ItemTypeRanged},
+	16: {ItemType: proto.ItemType_ItemTypeBack},
+	17: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeTwoHand},
+	20: {ItemType: proto.ItemType_ItemTypeChest},
+	21: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeMainHand},
+	22: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOffHand},
+	23: {ItemType: proto.ItemType_ItemTypeWeapon, HandType: proto.HandType_HandTypeOffHand},
+	25: {ItemType: proto.ItemType_ItemTypeRanged},
+	26: {ItemType: proto.ItemType_ItemTypeRanged},
+	28: {ItemType: proto.ItemType_ItemTypeRanged},
+}
+
+const (
+	db2ItemClassWeapon = 2
+	db2ItemClassArmor  = 4
+
+	db2InventoryTypeHoldable = 23
+)
+
+// Keyed by weapon subclass.
+var db2WeaponTypes = map[int]proto.WeaponType{
+	0:  proto.WeaponType_WeaponTypeAxe,
+	1:  proto.WeaponType_WeaponTypeAxe,
+	4:  proto.WeaponType_WeaponTypeMace,
+	5:  proto.WeaponType_WeaponTypeMace,
+	6:  proto.WeaponType_WeaponTypePolearm,
+	7:  proto.WeaponType_WeaponTypeSword,
+	8:  proto.WeaponType_WeaponTypeSword,
+	10: proto.WeaponType_WeaponTypeStaff,
+	13: proto.WeaponType_WeaponTypeFist,
+	15: proto.WeaponType_WeaponTypeDagger,
+}
+
+// Keyed by weapon subclass.
+var db2RangedWeaponTypes = map[int]proto.RangedWeaponType{
+	2:  proto.RangedWeaponType_RangedWeaponTypeBow,
+	3:  proto.RangedWeaponType_RangedWeaponTypeGun,
+	16: proto.RangedWeaponType_RangedWeaponTypeThrown,
+	18: proto.RangedWeaponType_RangedWeaponTypeCrossbow,
+	19: proto.RangedWeaponType_RangedWeaponTypeWand,
+}
+
+// Keyed by armor subclass.
+var db2ArmorTypes = map[int]proto.ArmorType{
+	1: proto.ArmorType_ArmorTypeCloth,
+	2: proto.ArmorType_ArmorTypeLeather,
+	3: proto.ArmorType_ArmorTypeMail,
+	4: proto.ArmorType_ArmorTypePlate,
+}
+
+// Keyed by armor subclass.
+var db2RelicTypes = map[int]proto.RangedWeaponType{
+	7: proto.RangedWeaponType_RangedWeaponTypeLibram,
+	8: proto.RangedWeaponType_RangedWeaponTypeIdol,
+	9: proto.RangedWeaponType_RangedWeaponTypeTotem,
+}
+
+const db2ArmorSubclassShield = 6
+
+// Returns nil for items which can't be equipped in a sim slot.
+func (parser *db2Parser) itemToProto(table *db2Table, row []string) *proto.UIItem {
+	inventoryType := table.getInt(row, "InventoryType")
+	slot, ok := db2InventoryTypes[inventoryType]
+	if !ok {
+		return nil
+	}
+
+	id := int32(table.getInt(row, itemIDHeader))
+	itemStats := Stats{}
+	weaponSkills := WeaponSkills{}
+
+	// Unused stat slots have an amount of 0, so they can be added like the others.
+	amountPrefix := strings.TrimSuffix(table.firstColumn("StatModifier_bonusAmount_0", "ItemStatValue_0"), "0")
+	for i := 0; i < db2ItemStatCount && amountPrefix != ""; i++ {
+		if stat, ok := db2ItemModStats[table.getInt(row, fmt.Sprintf("StatModifier_bonusStat_%d", i))]; ok {
+			itemStats[stat] += table.getFloat(row, fmt.Sprintf("%s%d", amountPrefix, i))
+		}
+	}
+
+	// Resistances_0 is armor, followed by holy, fire, nature, frost, shadow and arcane.
+	itemStats[proto.Stat_StatArmor] = table.getFloat(row, "Resistances_0")
+	itemStats[proto.Stat_StatFireResistance] += table.getFloat(row, "Resistances_2")
+	itemStats[proto.Stat_StatNatureResistance] += table.getFloat(row, "Resistances_3")
+	itemStats[proto.Stat_StatFrostResistance] += table.getFloat(row, "Resistances_4")
+	itemStats[proto.Stat_StatShadowResistance] += table.getFloat(row, "Resistances_5")
+	itemStats[proto.Stat_StatArcaneResistance] += table.getFloat(row, "Resistances_6")
+
+	for _, spellID := range parser.equipSpells[id] {
+		if bonus, ok := parser.spellBonuses[spellID]; ok {
+			bonus.addTo(&itemStats, &weaponSkills)
+		}
+	}
+
+	item := &proto.UIItem{
+		Id:       id,
+		Name:     table.getString(row, "Display_lang"),
+		Type:     slot.ItemType,
+		HandType: slot.HandType,
+
+		Stats:        toSlice(itemStats),
+		WeaponSkills: weaponSkillsToSlice(weaponSkills),
+
+		Ilvl:          int32(table.getInt(row, "ItemLevel")),
+		RequiresLevel: int32(table.getInt(row, "RequiredLevel")),
+		Quality:       proto.ItemQuality(table.getInt(row, "OverallQualityID")),
+		Unique:        table.getInt(row, "MaxCount") == 1,
+
+		ClassAllowlist:     db2ClassAllowlist(table.getInt(row, "AllowableClass")),
+		RequiredProfession: db2Professions[table.getInt(row, "RequiredSkill")],
+
+		SetId:              int32(table.getInt(row, itemSetHeader)),
+		SetName:            parser.setNames[int32(table.getInt(row, itemSetHeader))],
+		Expansion:          proto.Expansion_ExpansionVanilla,
+		FactionRestriction: flags1ToFactionRestriction(table.getInt(row, flags1Header)),
+	}
+
+	if delay := table.getFloat(row, "ItemDelay"); delay > 0 && (slot.ItemType == proto.ItemType_ItemTypeWeapon || slot.ItemType == proto.ItemType_ItemTypeRanged) {
+		item.WeaponSpeed = delay / 1000
+		item.WeaponDamageMin = table.getFloat(row, "MinDamage_0")
+		item.WeaponDamageMax = table.getFloat(row, "MaxDamage_0")
+	}
+
+	if class, ok := parser.itemClasses[id]; ok {
+		item.Icon = class.Icon
+		switch class.ClassID {
+		case db2ItemClassWeapon:
+			item.WeaponType = db2WeaponTypes[class.SubclassID]
+			item.RangedWeaponType = db2RangedWeaponTypes[class.SubclassID]
+		case db2ItemClassArmor:
+			if class.SubclassID == db2ArmorSubclassShield {
+				item.WeaponType = proto.WeaponType_WeaponTypeShield
+			} else if relicType, ok := db2RelicTypes[class.SubclassID]; ok {
+				item.RangedWeaponType = relicType
+			} else if slot.ItemType != proto.ItemType_ItemTypeWeapon {
+				item.ArmorType = db2ArmorTypes[class.SubclassID]
+			}
+		}
+	}
+	if inventoryType == db2InventoryTypeHoldable {
+		item.WeaponType = proto.WeaponType_WeaponTypeOffHand
+	}
+
+	if item.RequiredProfession != proto.Profession_ProfessionUnknown {
+		item.Sources = append(item.Sources, &proto.UIItemSource{
+			Source: &proto.UIItemSource_Crafted{
+				Crafted: &proto.CraftedSource{
+					Profession: item.RequiredProfession,
+				},
+			},
+		})
+	}
+
+	return item
+}
+
+func (parser *db2Parser) enchantToProto(table *db2Table, row []string) *proto.UIEnchant {
+	enchantStats := Stats{}
+	weaponSkills := WeaponSkills{}
+
+	for i := 0; i < db2EnchantEffectCount; i++ {
+		amount := table.getFloat(row, fmt.Sprintf("EffectPointsMin_%d", i))
+		arg := table.getInt(row, fmt.Sprintf("EffectArg_%d", i))
+
+		switch table.getInt(row, fmt.Sprintf("Effect_%d", i)) {
+		case db2EnchantTypeEquipSpell:
+			if bonus, ok := parser.spellBonuses[int32(arg)]; ok {
+				bonus.addTo(&enchantStats, &weaponSkills)
+			}
+		case db2EnchantTypeResistance:
+			// The argument is a school index here, rather than a mask.
+			if arg == 0 {
+				enchantStats[proto.Stat_StatBonusArmor] += amount
+			} else {
+				addSchoolStats(&enchantStats, db2SchoolResistanceStats, 1<<arg, amount)
+			}
+		case db2EnchantTypeStat:
+			if stat, ok := db2ItemModStats[arg]; ok {
+				enchantStats[stat] += amount
+			}
+		}
+	}
+
+	return &proto.UIEnchant{
+		EffectId: int32(table.getInt(row, "ID")),
+		Name:     table.getString(row, "Name_lang"),
+		Stats:    toSlice(enchantStats),
+	}
+}
+
+// AllowableClass is -1 or has every class bit set for items without class restrictions.
+func db2ClassAllowlist(allowableClass int) []proto.Class {
+	classMask := uint16(allowableClass)
+	if allowableClass <= 0 || classMask&db2AllClassesMask == db2AllClassesMask {
+		return nil
+	}
+	return classMaskToAllowlist(classMask)
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wowsims/sod/sim/core/proto"
+)
+
+func writeDB2Tables(t *testing.T, tables map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range tables {
+		if err := os.WriteFile(filepath.Join(dir, name+".csv"), []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadDB2Database(t *testing.T) {
+	dir := writeDB2Tables(t, map[string]string{
+		db2ItemSparse: "ID,Display_lang,InventoryType,ItemLevel,OverallQualityID,StatModifier_bonusStat_0,StatModifier_bonusAmount_0\n" +
+			"100,Test Helm,1,60,4,7,20\n" +
+			"101,Test Cloak of the Bear,16,55,2,0,0\n" +
+			"102,Test Shirt,4,1,1,0,0\n",
+		db2ItemEffect: "ParentItemID,TriggerType,SpellID\n" +
+			"100,1,500\n",
+		db2SpellEffect: "SpellID,Effect,EffectAura,EffectBasePoints,EffectMiscValue_0\n" +
+			"500,6,99,40,0\n",
+		db2SpellItemEnchantment: "ID,Name_lang,Effect_0,EffectArg_0,EffectPointsMin_0\n" +
+			"1000,+5 Strength,5,4,5\n" +
+			"1001,+5 Stamina,5,7,5\n",
+		db2ItemRandomProperties: "ID,Name_lang,Enchantment_0,Enchantment_1\n" +
+			"7,of the Bear,1000,1001\n",
+	})
+
+	db := ReadDB2Database(dir)
+
+	if _, ok := db.Items[102]; ok {
+		t.Errorf("Shirts can't be simmed, so they should be skipped")
+	}
+
+	helm := db.Items[100]
+	if helm == nil {
+		t.Fatalf("Missing item 100")
+	}
+	if helm.Stats[proto.Stat_StatStamina] != 20 || helm.Stats[proto.Stat_StatAttackPower] != 40 {
+		t.Errorf("Expected 20 stamina and 40 attack power, got %v", helm.Stats)
+	}
+	if helm.Phase != 0 {
+		t.Errorf("The client tables have no phases, got phase %d", helm.Phase)
+	}
+
+	suffix := db.RandomSuffixes[7]
+	if suffix == nil {
+		t.Fatalf("Missing random suffix 7")
+	}
+	if suffix.Name != "of the Bear" || suffix.Stats[proto.Stat_StatStrength] != 5 || suffix.Stats[proto.Stat_StatStamina] != 5 {
+		t.Errorf("Expected 'of the Bear' with 5 strength and 5 stamina, got '%s' with %v", suffix.Name, suffix.Stats)
+	}
+
+	existing := NewWowDatabase()
+	existing.Items[100] = &proto.UIItem{Id: 100, Phase: 4}
+	existing.Items[101] = &proto.UIItem{Id: 101, RandomSuffixOptions: []int32{7}}
+	db.ApplyExistingItemData(existing)
+
+	if helm.Phase != 4 {
+		t.Errorf("Expected the phase of the existing item, got %d", helm.Phase)
+	}
+	if cloak := db.Items[101]; cloak.Phase != db2DefaultPhase || len(cloak.RandomSuffixOptions) != 1 || cloak.RandomSuffixOptions[0] != 7 {
+		t.Errorf("Expected the default phase and the existing random suffix options, got phase %d and options %v", cloak.Phase, cloak.RandomSuffixOptions)
+	}
+}
+
+func TestApplyExistingItemDataWithoutExistingDatabase(t *testing.T) {
+	db := &DB2Database{Items: map[int32]*proto.UIItem{100: {Id: 100}}}
+	db.ApplyExistingItemData(nil)
+	if db.Items[100].Phase != db2DefaultPhase {
+		t.Errorf("Expected the default phase, got %d", db.Items[100].Phase)
+	}
+}
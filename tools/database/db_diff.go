@@ -0,0 +1,281 @@
+package database
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/sim/core/stats"
+	"golang.org/x/exp/maps"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DiffDatabases returns a human-readable report of the items, enchants, runes and item sets which
+// were added, removed or changed between two versions of the database.
+func DiffDatabases(before *WowDatabase, after *WowDatabase) []string {
+	var report []string
+	report = append(report, diffEntities("Items", before.Items, after.Items, cmp.Compare[int32], func(item *proto.UIItem) string {
+		return fmt.Sprintf("%d %s", item.Id, item.Name)
+	})...)
+	report = append(report, diffEntities("Enchants", before.Enchants, after.Enchants, compareEnchantDBKeys, func(enchant *proto.UIEnchant) string {
+		return fmt.Sprintf("%d %s (item %d, spell %d)", enchant.EffectId, enchant.Name, enchant.ItemId, enchant.SpellId)
+	})...)
+	report = append(report, diffEntities("Runes", before.Runes, after.Runes, cmp.Compare[int32], func(rune *proto.UIRune) string {
+		return fmt.Sprintf("%d %s", rune.Id, rune.Name)
+	})...)
+	report = append(report, diffItemSets(before, after)...)
+	return report
+}
+
+func compareEnchantDBKeys(a, b EnchantDBKey) int {
+	if a.EffectID != b.EffectID {
+		return cmp.Compare(a.EffectID, b.EffectID)
+	}
+	if a.ItemID != b.ItemID {
+		return cmp.Compare(a.ItemID, b.ItemID)
+	}
+	return cmp.Compare(a.SpellID, b.SpellID)
+}
+
+func diffEntities[K comparable, V googleProto.Message](section string, before map[K]V, after map[K]V, compareKeys func(K, K) int, describe func(V) string) []string {
+	var added, removed, changed []string
+
+	keys := maps.Keys(before)
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, compareKeys)
+
+	for _, key := range keys {
+		oldValue, hadOld := before[key]
+		newValue, hasNew := after[key]
+		switch {
+		case !hadOld:
+			added = append(added, "  + "+describe(newValue))
+		case !hasNew:
+			removed = append(removed, "  - "+describe(oldValue))
+		case !googleProto.Equal(oldValue, newValue):
+			changes := describeFieldChanges(oldValue.ProtoReflect(), newValue.ProtoReflect())
+			changed = append(changed, fmt.Sprintf("  ~ %s: %s", describe(newValue), strings.Join(changes, ", ")))
+		}
+	}
+
+	report := []string{fmt.Sprintf("%s: %d added, %d removed, %d changed", section, len(added), len(removed), len(changed))}
+	report = append(report, added...)
+	report = append(report, removed...)
+	return append(report, changed...)
+}
+
+// Lists the top level fields which differ between two messages of the same type, e.g.
+// "ilvl: 60 -> 63" or "stats: Strength 10 -> 12".
+func describeFieldChanges(before protoreflect.Message, after protoreflect.Message) []string {
+	var changes []string
+
+	fields := before.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		oldValue, newValue := before.Get(field), after.Get(field)
+		if oldValue.Equal(newValue) {
+			continue
+		}
+
+		switch field.Name() {
+		case "stats":
+			changes = append(changes, fmt.Sprintf("stats: %s", describeListChanges(oldValue.List(), newValue.List(), func(i int) string {
+				return stats.Stat(i).StatName()
+			})))
+		case "weapon_skills":
+			changes = append(changes, fmt.Sprintf("weapon_skills: %s", describeListChanges(oldValue.List(), newValue.List(), func(i int) string {
+				return proto.WeaponSkill(i).String()
+			})))
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field.Name(), formatFieldValue(field, oldValue), formatFieldValue(field, newValue)))
+		}
+	}
+
+	return changes
+}
+
+func describeListChanges(before protoreflect.List, after protoreflect.List, name func(int) string) string {
+	var changes []string
+	for i := 0; i < max(before.Len(), after.Len()); i++ {
+		var oldValue, newValue float64
+		if i < before.Len() {
+			oldValue = before.Get(i).Float()
+		}
+		if i < after.Len() {
+			newValue = after.Get(i).Float()
+		}
+		if oldValue != newValue {
+			changes = append(changes, fmt.Sprintf("%s %g -> %g", name(i), oldValue, newValue))
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+func formatFieldValue(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if field.IsList() || field.IsMap() || field.Message() != nil {
+		// Sources and allowlists are too noisy to print in full.
+		if field.IsList() {
+			return fmt.Sprintf("[%d entries]", value.List().Len())
+		}
+		return "{...}"
+	}
+	if field.Enum() != nil {
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+	}
+	if field.Kind() == protoreflect.StringKind {
+		return fmt.Sprintf("%q", value.String())
+	}
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+type itemSetSummary struct {
+	Name    string
+	ItemIDs []int32
+}
+
+func summarizeItemSets(db *WowDatabase) map[int32]*itemSetSummary {
+	itemSets := map[int32]*itemSetSummary{}
+	for _, item := range db.Items {
+		if item.SetId == 0 {
+			continue
+		}
+		summary, ok := itemSets[item.SetId]
+		if !ok {
+			summary = &itemSetSummary{Name: item.SetName}
+			itemSets[item.SetId] = summary
+		}
+		summary.ItemIDs = append(summary.ItemIDs, item.Id)
+	}
+	for _, summary := range itemSets {
+		slices.Sort(summary.ItemIDs)
+	}
+	return itemSets
+}
+
+func diffItemSets(before *WowDatabase, after *WowDatabase) []string {
+	var added, removed, changed []string
+
+	oldSets, newSets := summarizeItemSets(before), summarizeItemSets(after)
+	ids := maps.Keys(oldSets)
+	for id := range newSets {
+		if _, ok := oldSets[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		oldSet, hadOld := oldSets[id]
+		newSet, hasNew := newSets[id]
+		switch {
+		case !hadOld:
+			added = append(added, fmt.Sprintf("  + %d %s %v", id, newSet.Name, newSet.ItemIDs))
+		case !hasNew:
+			removed = append(removed, fmt.Sprintf("  - %d %s %v", id, oldSet.Name, oldSet.ItemIDs))
+		default:
+			var changes []string
+			if oldSet.Name != newSet.Name {
+				changes = append(changes, fmt.Sprintf("name: %q -> %q", oldSet.Name, newSet.Name))
+			}
+			if !slices.Equal(oldSet.ItemIDs, newSet.ItemIDs) {
+				changes = append(changes, fmt.Sprintf("items: %v -> %v", oldSet.ItemIDs, newSet.ItemIDs))
+			}
+			if len(changes) > 0 {
+				changed = append(changed, fmt.Sprintf("  ~ %d %s: %s", id, newSet.Name, strings.Join(changes, ", ")))
+			}
+		}
+	}
+
+	report := []string{fmt.Sprintf("Item sets: %d added, %d removed, %d changed", len(added), len(removed), len(changed))}
+	report = append(report, added...)
+	report = append(report, removed...)
+	return append(report, changed...)
+}
+
+// ValidateDatabase returns a description of every entity in the database which is likely to be broken,
+// including item sets implemented in the sim which no item belongs to anymore.
+func ValidateDatabase(db *WowDatabase, implementedSets []*core.ItemSet) []string {
+	var problems []string
+
+	items := mapToSlice(db.Items)
+	setIDsByName := map[string]int32{}
+	for _, item := range items {
+		if item.SetId != 0 && item.SetName == "" {
+			problems = append(problems, fmt.Sprintf("Item %d %s references set ID %d, which has no name", item.Id, item.Name, item.SetId))
+		}
+		if item.SetName != "" {
+			if item.SetId == 0 {
+				problems = append(problems, fmt.Sprintf("Item %d %s belongs to set %q, which has no ID", item.Id, item.Name, item.SetName))
+			} else if otherID, ok := setIDsByName[item.SetName]; ok && otherID != item.SetId {
+				problems = append(problems, fmt.Sprintf("Item %d %s references set ID %d for set %q, but other items use ID %d", item.Id, item.Name, item.SetId, item.SetName, otherID))
+			} else {
+				setIDsByName[item.SetName] = item.SetId
+			}
+		}
+
+		if isWeaponWithSpeed(item) && item.WeaponSpeed == 0 {
+			problems = append(problems, fmt.Sprintf("Weapon %d %s has a speed of 0", item.Id, item.Name))
+		}
+	}
+
+	setIDs := summarizeItemSets(db)
+	checkedSets := map[string]bool{}
+	for _, set := range implementedSets {
+		// Some sets are registered by more than one class package.
+		if checkedSets[set.Name] {
+			continue
+		}
+		checkedSets[set.Name] = true
+
+		if _, ok := setIDsByName[set.Name]; !ok {
+			problems = append(problems, fmt.Sprintf("Set %q is implemented in the sim, but no item belongs to it", set.Name))
+		}
+		if _, ok := setIDs[set.ID]; set.ID != 0 && !ok {
+			problems = append(problems, fmt.Sprintf("Set %q is implemented in the sim with ID %d, but no item references that ID", set.Name, set.ID))
+		}
+	}
+
+	for _, rune := range mapToSlice(db.Runes) {
+		if !slices.ContainsFunc(rune.ClassAllowlist, func(class proto.Class) bool { return class != proto.Class_ClassUnknown }) {
+			problems = append(problems, fmt.Sprintf("Rune %d %s has no class", rune.Id, rune.Name))
+		}
+	}
+
+	enchants := maps.Values(db.Enchants)
+	slices.SortFunc(enchants, func(a, b *proto.UIEnchant) int {
+		return compareEnchantDBKeys(EnchantToDBKey(a), EnchantToDBKey(b))
+	})
+	for _, enchant := range enchants {
+		if enchant.Type == proto.ItemType_ItemTypeUnknown && len(enchant.ExtraTypes) == 0 {
+			problems = append(problems, fmt.Sprintf("Enchant %d %s has no slot", enchant.EffectId, enchant.Name))
+		}
+	}
+
+	return problems
+}
+
+// Off hands, shields and relics legitimately have no weapon speed.
+func isWeaponWithSpeed(item *proto.UIItem) bool {
+	switch item.Type {
+	case proto.ItemType_ItemTypeWeapon:
+		return item.WeaponType != proto.WeaponType_WeaponTypeOffHand && item.WeaponType != proto.WeaponType_WeaponTypeShield
+	case proto.ItemType_ItemTypeRanged:
+		switch item.RangedWeaponType {
+		case proto.RangedWeaponType_RangedWeaponTypeIdol, proto.RangedWeaponType_RangedWeaponTypeLibram,
+			proto.RangedWeaponType_RangedWeaponTypeTotem, proto.RangedWeaponType_RangedWeaponTypeSigil:
+			return false
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/sim/core/proto"
+	"github.com/wowsims/sod/tools"
+	"github.com/wowsims/sod/tools/database"
+)
+
+// Builds the database from exported client tables instead of scraped tooltips. Only inputs which are
+// checked into db_inputs and the previously generated database in dbDir are used on top of them, so this
+// doesn't make any network requests. Without a previous database every item is treated as new.
+//
+// Item and spell icons aren't written to the database, the UI fetches any missing ones on demand.
+func GenerateDB2Database(db2Dir string, dbDir string, inputsDir string) {
+	db2DB := database.ReadDB2Database(db2Dir)
+	existingDBPath := fmt.Sprintf("%s/db.json", dbDir)
+	if _, err := os.Stat(existingDBPath); err == nil {
+		db2DB.ApplyExistingItemData(database.ReadDatabaseFromJson(tools.ReadFile(existingDBPath)))
+	} else {
+		fmt.Printf("No existing database at %s, so items won't have phases or random suffixes from it\n", existingDBPath)
+		db2DB.ApplyExistingItemData(nil)
+	}
+	runeTooltips := database.NewWowheadSpellTooltipManager(fmt.Sprintf("%s/wowhead_rune_tooltips.csv", inputsDir)).Read()
+	atlaslootDB := database.ReadDatabaseFromJson(tools.ReadFile(fmt.Sprintf("%s/atlasloot_db.json", inputsDir)))
+
+	db := database.NewWowDatabase()
+	db.Encounters = core.PresetEncounters
+
+	for _, item := range db2DB.Items {
+		db.MergeItem(item)
+	}
+	for _, item := range atlaslootDB.Items {
+		if _, ok := db.Items[item.Id]; ok {
+			db.MergeItem(item)
+		}
+	}
+
+	for id, rune := range runeTooltips {
+		if !slices.Contains(database.UnimplementedRuneOverrides, id) {
+			db.AddRune(id, rune)
+		}
+	}
+
+	// The client tables don't say which slot an enchant goes in, so only enchants listed in the overrides
+	// are included, with the overrides taking precedence.
+	for _, enchant := range database.EnchantOverrides {
+		if db2Enchant, ok := db2DB.Enchants[enchant.EffectId]; ok {
+			db.MergeEnchant(&proto.UIEnchant{
+				EffectId: enchant.EffectId,
+				ItemId:   enchant.ItemId,
+				SpellId:  enchant.SpellId,
+				Name:     db2Enchant.Name,
+				Stats:    db2Enchant.Stats,
+			})
+		}
+	}
+
+	db.MergeItems(database.ItemOverrides)
+	db.MergeEnchants(database.EnchantOverrides)
+	db.MergeRunes(database.RuneOverrides)
+	ApplyGlobalFilters(db)
+
+	leftovers := db.Clone()
+	ApplyNonSimmableFilters(leftovers)
+	leftovers.WriteBinaryAndJson(fmt.Sprintf("%s/leftover_db.bin", dbDir), fmt.Sprintf("%s/leftover_db.json", dbDir))
+
+	ApplySimmableFilters(db)
+	for _, item := range db.Items {
+		for _, randomSuffixID := range item.RandomSuffixOptions {
+			if suffix, ok := db2DB.RandomSuffixes[randomSuffixID]; ok {
+				db.RandomSuffixes[randomSuffixID] = suffix
+			}
+		}
+	}
+	db.MergeSpellIcons(database.SpellIconoverrides)
+
+	atlasDBProto := atlaslootDB.ToUIProto()
+	db.MergeZones(atlasDBProto.Zones)
+	db.MergeNpcs(atlasDBProto.Npcs)
+	db.MergeFactions(atlasDBProto.Factions)
+
+	db.WriteBinaryAndJson(fmt.Sprintf("%s/db.bin", dbDir), fmt.Sprintf("%s/db.json", dbDir))
+}
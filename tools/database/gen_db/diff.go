@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wowsims/sod/sim/core"
+	"github.com/wowsims/sod/tools"
+	"github.com/wowsims/sod/tools/database"
+)
+
+// Prints what changed between two generated db.json files, followed by validation problems found in
+// the new one.
+func DiffDatabaseFiles(oldDbPath string, newDbPath string) {
+	before := database.ReadDatabaseFromJson(tools.ReadFile(oldDbPath))
+	after := database.ReadDatabaseFromJson(tools.ReadFile(newDbPath))
+
+	fmt.Printf("Comparing %s to %s\n\n", oldDbPath, newDbPath)
+	fmt.Println(strings.Join(database.DiffDatabases(before, after), "\n"))
+
+	problems := database.ValidateDatabase(after, core.GetItemSets())
+	fmt.Printf("\nValidation: %d problems\n", len(problems))
+	for _, problem := range problems {
+		fmt.Printf("  ! %s\n", problem)
+	}
+}
@@ -31,11 +31,20 @@ import (
 // Note: This does not make network requests, only regenerates core db binary and json files from existing inputs
 // go run ./tools/database/gen_db -outDir=assets -gen=db
 
+// Alternatively, to build the database offline from client tables exported to CSV (see tools/database/db2.go):
+// go run ./tools/database/gen_db -outDir=assets -gen=db2 -db2Dir=path/to/csvs
+
+// To review a regenerated database, copy db.json aside before regenerating and then run:
+// go run ./tools/database/gen_db -outDir=assets -gen=diff -oldDb=path/to/old/db.json
+
 var exactId = flag.Int("id", 0, "ID to scan for")
 var minId = flag.Int("minid", 1, "Minimum ID to scan for")
 var maxId = flag.Int("maxid", 31000, "Maximum ID to scan for")
 var outDir = flag.String("outDir", "assets", "Path to output directory for writing generated .go files.")
-var genAsset = flag.String("gen", "", "Asset to generate. Valid values are 'db', 'atlasloot', 'wowhead-items', 'wowhead-spells', 'wowhead-itemdb', 'wotlk-items', and 'wago-db2-items'")
+var genAsset = flag.String("gen", "", "Asset to generate. Valid values are 'db', 'db2', 'diff', 'atlasloot', 'wowhead-items', 'wowhead-spells', 'wowhead-itemdb', 'wotlk-items', and 'wago-db2-items'")
+var db2Dir = flag.String("db2Dir", "", "Path to the directory of exported client table CSVs used by -gen=db2. Defaults to <outDir>/db_inputs/db2.")
+var oldDb = flag.String("oldDb", "", "Path to the previous db.json, compared by -gen=diff.")
+var newDb = flag.String("newDb", "", "Path to the new db.json, compared by -gen=diff. Defaults to <outDir>/database/db.json.")
 
 func main() {
 	flag.Parse()
@@ -68,6 +77,21 @@ func main() {
 	} else if *genAsset == "wago-db2-items" {
 		tools.WriteFile(fmt.Sprintf("%s/wago_db2_items.csv", inputsDir), tools.ReadWebRequired("https://wago.tools/db2/ItemSparse/csv?build=1.15.3.55646"))
 		return
+	} else if *genAsset == "db2" {
+		if *db2Dir == "" {
+			*db2Dir = fmt.Sprintf("%s/db2", inputsDir)
+		}
+		GenerateDB2Database(*db2Dir, dbDir, inputsDir)
+		return
+	} else if *genAsset == "diff" {
+		if *oldDb == "" {
+			panic("oldDb flag is required for -gen=diff!")
+		}
+		if *newDb == "" {
+			*newDb = fmt.Sprintf("%s/db.json", dbDir)
+		}
+		DiffDatabaseFiles(*oldDb, *newDb)
+		return
 	} else if *genAsset != "db" {
 		panic("Invalid gen value")
 	}
@@ -288,32 +288,36 @@ const (
 )
 
 func (wi WowheadItem) getClassRestriction() []proto.Class {
+	return classMaskToAllowlist(wi.ClassMask)
+}
+
+func classMaskToAllowlist(classMask uint16) []proto.Class {
 	classAllowlist := []proto.Class{}
-	if wi.ClassMask&uint16(ClassMaskWarrior) != 0 {
+	if classMask&uint16(ClassMaskWarrior) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassWarrior)
 	}
-	if wi.ClassMask&uint16(ClassMaskPaladin) != 0 {
+	if classMask&uint16(ClassMaskPaladin) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassPaladin)
 	}
-	if wi.ClassMask&uint16(ClassMaskHunter) != 0 {
+	if classMask&uint16(ClassMaskHunter) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassHunter)
 	}
-	if wi.ClassMask&uint16(ClassMaskRogue) != 0 {
+	if classMask&uint16(ClassMaskRogue) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassRogue)
 	}
-	if wi.ClassMask&uint16(ClassMaskPriest) != 0 {
+	if classMask&uint16(ClassMaskPriest) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassPriest)
 	}
-	if wi.ClassMask&uint16(ClassMaskDruid) != 0 {
+	if classMask&uint16(ClassMaskDruid) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassDruid)
 	}
-	if wi.ClassMask&uint16(ClassMaskShaman) != 0 {
+	if classMask&uint16(ClassMaskShaman) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassShaman)
 	}
-	if wi.ClassMask&uint16(ClassMaskMage) != 0 {
+	if classMask&uint16(ClassMaskMage) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassMage)
 	}
-	if wi.ClassMask&uint16(ClassMaskWarlock) != 0 {
+	if classMask&uint16(ClassMaskWarlock) != 0 {
 		classAllowlist = append(classAllowlist, proto.Class_ClassWarlock)
 	}
 
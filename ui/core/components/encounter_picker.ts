@@ -238,6 +238,9 @@ class TargetPicker extends Input<Encounter, TargetProto> {
 	private readonly parryHastePicker: Input<null, boolean>;
 	private readonly spellSchoolPicker: Input<null, number>;
 	private readonly damageSpreadPicker: Input<null, number>;
+	private readonly spawnTimePicker: Input<null, number>;
+	private readonly despawnTimePicker: Input<null, number>;
+	private readonly diesAtZeroHealthPicker: Input<null, boolean>;
 	private readonly targetInputPickers: ListPicker<Encounter, TargetInput>;
 
 	private getTarget(): TargetProto {
@@ -426,6 +429,30 @@ class TargetPicker extends Input<Encounter, TargetProto> {
 				encounter.targetsChangeEmitter.emit(eventID);
 			},
 		});
+		this.spawnTimePicker = new NumberPicker(section3, null, {
+			id: 'target-picker-spawn-time',
+			label: 'Spawn Time',
+			labelTooltip: 'Time in seconds into the fight at which this enemy appears. Set to 0 for enemies present from the start.',
+			float: true,
+			changedEvent: () => encounter.targetsChangeEmitter,
+			getValue: () => this.getTarget().spawnTime,
+			setValue: (eventID: EventID, _: null, newValue: number) => {
+				this.getTarget().spawnTime = newValue;
+				encounter.targetsChangeEmitter.emit(eventID);
+			},
+		});
+		this.despawnTimePicker = new NumberPicker(section3, null, {
+			id: 'target-picker-despawn-time',
+			label: 'Despawn Time',
+			labelTooltip: 'Time in seconds into the fight at which this enemy leaves. Set to 0 for enemies that stay for the whole fight.',
+			float: true,
+			changedEvent: () => encounter.targetsChangeEmitter,
+			getValue: () => this.getTarget().despawnTime,
+			setValue: (eventID: EventID, _: null, newValue: number) => {
+				this.getTarget().despawnTime = newValue;
+				encounter.targetsChangeEmitter.emit(eventID);
+			},
+		});
 		this.dualWieldPicker = new BooleanPicker(section3, null, {
 			id: 'target-picker-dual-wield',
 			label: 'Dual Wield',
@@ -467,6 +494,19 @@ class TargetPicker extends Input<Encounter, TargetProto> {
 				encounter.targetsChangeEmitter.emit(eventID);
 			},
 		});
+		this.diesAtZeroHealthPicker = new BooleanPicker(section3, null, {
+			id: 'target-picker-dies-at-zero-health',
+			label: 'Dies at 0 Health',
+			labelTooltip: 'Whether this enemy dies once it has taken damage equal to its Health stat.',
+			inline: true,
+			reverse: true,
+			changedEvent: () => encounter.targetsChangeEmitter,
+			getValue: () => this.getTarget().diesAtZeroHealth,
+			setValue: (eventID: EventID, _: null, newValue: boolean) => {
+				this.getTarget().diesAtZeroHealth = newValue;
+				encounter.targetsChangeEmitter.emit(eventID);
+			},
+		});
 		this.spellSchoolPicker = new EnumPicker<null>(section3, null, {
 			id: 'target-picker-spell-school',
 			label: 'Spell School',
@@ -507,6 +547,9 @@ class TargetPicker extends Input<Encounter, TargetProto> {
 			parryHaste: this.parryHastePicker.getInputValue(),
 			spellSchool: this.spellSchoolPicker.getInputValue(),
 			damageSpread: this.damageSpreadPicker.getInputValue(),
+			spawnTime: this.spawnTimePicker.getInputValue(),
+			despawnTime: this.despawnTimePicker.getInputValue(),
+			diesAtZeroHealth: this.diesAtZeroHealthPicker.getInputValue(),
 			stats: this.statPickers
 				.map(picker => picker.getInputValue())
 				.map((statValue, i) => new Stats().withStat(ALL_TARGET_STATS[i].stat, statValue))
@@ -530,6 +573,9 @@ class TargetPicker extends Input<Encounter, TargetProto> {
 		this.parryHastePicker.setInputValue(newValue.parryHaste);
 		this.spellSchoolPicker.setInputValue(newValue.spellSchool);
 		this.damageSpreadPicker.setInputValue(newValue.damageSpread);
+		this.spawnTimePicker.setInputValue(newValue.spawnTime);
+		this.despawnTimePicker.setInputValue(newValue.despawnTime);
+		this.diesAtZeroHealthPicker.setInputValue(newValue.diesAtZeroHealth);
 		ALL_TARGET_STATS.forEach((statData, i) => this.statPickers[i].setInputValue(newValue.stats[statData.stat]));
 		this.targetInputPickers.setInputValue(newValue.targetInputs);
 	}
@@ -21,6 +21,7 @@ import {
 	APLActionResetSequence,
 	APLActionSchedule,
 	APLActionSequence,
+	APLActionSetVariable,
 	APLActionStrictSequence,
 	APLActionTriggerICD,
 	APLActionWait,
@@ -563,6 +564,17 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 			}),
 		],
 	}),
+	['setVariable']: inputBuilder({
+		label: 'Set Variable',
+		submenu: ['Misc'],
+		shortDescription: 'Sets a variable to the given value.',
+		fullDescription: `
+			<p>Use the <b>name</b> field to refer to a variable defined in the <b>Variables</b> list. Boolean variables are set to whether the value is true.</p>
+			<p>This action is only used when it would change the variable, so later actions are still considered afterwards.</p>
+		`,
+		newValue: APLActionSetVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name'), AplValues.valueFieldConfig('value')],
+	}),
 	['addComboPoints']: inputBuilder({
 		label: 'Add Combo Points',
 		submenu: ['Misc'],
@@ -1,8 +1,9 @@
 import tippy, { Instance as TippyInstance } from 'tippy.js';
 
 import { Player } from '../../player';
-import { APLAction, APLListItem, APLPrepullAction, APLValue } from '../../proto/apl';
+import { APLAction, APLListItem, APLNamedExpression, APLPrepullAction, APLValue, APLVariable } from '../../proto/apl';
 import { ActionId } from '../../proto_utils/action_id';
+import { SimResult } from '../../proto_utils/sim_result';
 import { SimUI } from '../../sim_ui';
 import { EventID, TypedEvent } from '../../typed_event';
 import { existsInDOM, randomUUID } from '../../utils';
@@ -11,6 +12,8 @@ import { Input, InputConfig } from '../input';
 import { AdaptiveStringPicker } from '../inputs/string_picker';
 import { ListItemPickerConfig, ListPicker } from '../list_picker';
 import { APLActionPicker } from './apl_actions';
+import * as AplHelpers from './apl_helpers';
+import * as AplValues from './apl_values';
 import { APLValueImplStruct } from './apl_values';
 
 export class APLRotationPicker extends Component {
@@ -70,6 +73,75 @@ export class APLRotationPicker extends Component {
 			inlineMenuBar: true,
 		});
 
+		new ListPicker<Player<any>, APLVariable>(this.rootElem, modPlayer, {
+			extraCssClasses: ['apl-variable-picker'],
+			title: 'Variables',
+			titleTooltip: 'Values which Set Variable actions can change, and Variable values can read.',
+			itemLabel: 'Variable',
+			changedEvent: (player: Player<any>) => player.rotationChangeEmitter,
+			getValue: (player: Player<any>) => player.aplRotation.variables,
+			setValue: (eventID: EventID, player: Player<any>, newValue: Array<APLVariable>) => {
+				player.aplRotation.variables = newValue;
+				player.rotationChangeEmitter.emit(eventID);
+			},
+			newItem: () => APLVariable.create(),
+			copyItem: (oldItem: APLVariable) => APLVariable.clone(oldItem),
+			newItemPicker: (
+				parent: HTMLElement,
+				listPicker: ListPicker<Player<any>, APLVariable>,
+				index: number,
+				config: ListItemPickerConfig<Player<any>, APLVariable>,
+			) =>
+				new APLDefinitionPicker(
+					parent,
+					modPlayer,
+					config,
+					APLVariable.create,
+					player => player.getCurrentStats().rotationStats?.variables[index]?.warnings || [],
+					[
+						AplHelpers.stringFieldConfig('name'),
+						AplHelpers.booleanFieldConfig('isBool', 'Boolean', {
+							labelTooltip: 'Holds true/false instead of a number.',
+						}),
+						AplHelpers.numberFieldConfig('initialValue', true, {
+							label: 'Initial Value',
+							labelTooltip: 'Value at the start of each iteration. For booleans, any non-zero value is true.',
+						}),
+					],
+				),
+			inlineMenuBar: true,
+		});
+
+		new ListPicker<Player<any>, APLNamedExpression>(this.rootElem, modPlayer, {
+			extraCssClasses: ['apl-named-expression-picker'],
+			title: 'Named Expressions',
+			titleTooltip: 'Values which can be referenced by name with the Named Expression value, instead of repeating them in several actions.',
+			itemLabel: 'Named Expression',
+			changedEvent: (player: Player<any>) => player.rotationChangeEmitter,
+			getValue: (player: Player<any>) => player.aplRotation.namedExpressions,
+			setValue: (eventID: EventID, player: Player<any>, newValue: Array<APLNamedExpression>) => {
+				player.aplRotation.namedExpressions = newValue;
+				player.rotationChangeEmitter.emit(eventID);
+			},
+			newItem: () => APLNamedExpression.create(),
+			copyItem: (oldItem: APLNamedExpression) => APLNamedExpression.clone(oldItem),
+			newItemPicker: (
+				parent: HTMLElement,
+				listPicker: ListPicker<Player<any>, APLNamedExpression>,
+				index: number,
+				config: ListItemPickerConfig<Player<any>, APLNamedExpression>,
+			) =>
+				new APLDefinitionPicker(
+					parent,
+					modPlayer,
+					config,
+					APLNamedExpression.create,
+					player => player.getCurrentStats().rotationStats?.namedExpressions[index]?.warnings || [],
+					[AplHelpers.stringFieldConfig('name'), AplValues.valueFieldConfig('value')],
+				),
+			inlineMenuBar: true,
+		});
+
 		//modPlayer.rotationChangeEmitter.on(() => console.log('APL: ' + APLRotation.toJsonString(modPlayer.aplRotation)))
 	}
 }
@@ -197,6 +269,7 @@ class APLListItemPicker extends Input<Player<any>, APLListItem> {
 
 		const itemHeaderElem = ListPicker.getItemHeaderElem(this);
 		makeListItemWarnings(itemHeaderElem, player, player => player.getCurrentStats().rotationStats?.priorityList[index]?.warnings || []);
+		makeListItemHitCount(itemHeaderElem, player, index);
 
 		this.hidePicker = new HidePicker(itemHeaderElem, player, {
 			changedEvent: () => this.player.rotationChangeEmitter,
@@ -239,6 +312,52 @@ class APLListItemPicker extends Input<Player<any>, APLListItem> {
 	}
 }
 
+// Picker for rotation-level definitions, which are referenced by name from actions and values.
+class APLDefinitionPicker<T extends object> extends Input<Player<any>, T> {
+	private readonly builder: AplHelpers.APLPickerBuilder<T>;
+
+	constructor(
+		parent: HTMLElement,
+		player: Player<any>,
+		config: ListItemPickerConfig<Player<any>, T>,
+		newItem: () => T,
+		getWarnings: (player: Player<any>) => Array<string>,
+		fields: Array<AplHelpers.APLPickerBuilderFieldConfig<T, any>>,
+	) {
+		super(parent, 'apl-list-item-picker-root', player, config);
+
+		const itemHeaderElem = ListPicker.getItemHeaderElem(this);
+		makeListItemWarnings(itemHeaderElem, player, getWarnings);
+
+		this.builder = new AplHelpers.APLPickerBuilder<T>(this.rootElem, player, {
+			changedEvent: () => player.rotationChangeEmitter,
+			getValue: () => this.getSourceValue() || newItem(),
+			setValue: (eventID: EventID, player: Player<any>, newValue: T) => {
+				Object.assign(this.getSourceValue(), newValue);
+				player.rotationChangeEmitter.emit(eventID);
+			},
+			newValue: newItem,
+			fields: fields,
+		});
+		this.init();
+	}
+
+	getInputElem(): HTMLElement | null {
+		return this.rootElem;
+	}
+
+	getInputValue(): T {
+		return this.builder.getInputValue();
+	}
+
+	setInputValue(newValue: T) {
+		if (!newValue) {
+			return;
+		}
+		this.builder.setInputValue(newValue);
+	}
+}
+
 function makeListItemWarnings(itemHeaderElem: HTMLElement, player: Player<any>, getWarnings: (player: Player<any>) => Array<string>) {
 	const warningsElem = ListPicker.makeActionElem('apl-warnings', 'fa-exclamation-triangle');
 	warningsElem.classList.add('warning', 'link-warning');
@@ -277,6 +396,42 @@ function makeListItemWarnings(itemHeaderElem: HTMLElement, player: Player<any>,
 	player.currentStatsEmitter.on(updateWarnings);
 }
 
+// Shows how often the item was executed in the last sim, with the rest of its execution stats in a tooltip.
+function makeListItemHitCount(itemHeaderElem: HTMLElement, player: Player<any>, index: number) {
+	const hitCountElem = document.createElement('span');
+	hitCountElem.classList.add('apl-hit-count');
+	const hitCountTooltip = tippy(hitCountElem, {
+		theme: 'dropdown-tooltip',
+		content: '',
+	});
+	itemHeaderElem.appendChild(hitCountElem);
+
+	const updateHitCount = (_eventID: EventID, simResult: SimResult) => {
+		if (!existsInDOM(hitCountElem)) {
+			hitCountTooltip?.destroy();
+			hitCountElem?.remove();
+			player.sim.simResultEmitter.off(updateHitCount);
+			return;
+		}
+		const stats = simResult.getPlayerWithRaidIndex(player.getRaidIndex())?.rotationStats?.priorityList[index];
+		if (!stats?.aggregatorData) {
+			hitCountElem.textContent = '';
+			return;
+		}
+		hitCountElem.textContent = stats.executionsAvg.toFixed(1);
+		hitCountTooltip.setContent(`
+			<p>Per iteration of the last sim:</p>
+			<ul>
+				<li>Evaluated: ${stats.evaluationsAvg.toFixed(1)}</li>
+				<li>Ready: ${stats.readyAvg.toFixed(1)}</li>
+				<li>Executed: ${stats.executionsAvg.toFixed(1)}</li>
+				<li>Time between executions: ${stats.secondsBetweenExecutionsAvg.toFixed(2)}s</li>
+			</ul>
+		`);
+	};
+	player.sim.simResultEmitter.on(updateHitCount);
+}
+
 class HidePicker extends Input<Player<any>, boolean> {
 	private readonly inputElem: HTMLElement;
 	private readonly iconElem: HTMLElement;
@@ -32,16 +32,19 @@ import {
 	APLValueCurrentTimePercent,
 	APLValueDotIsActive,
 	APLValueDotRemainingTime,
+	APLValueDowntimeRemaining,
 	APLValueEnergyThreshold,
 	APLValueFrontOfTarget,
 	APLValueGCDIsReady,
 	APLValueGCDTimeToReady,
 	APLValueIsExecutePhase,
 	APLValueIsExecutePhase_ExecutePhaseThreshold as ExecutePhaseThreshold,
+	APLValueIsTanking,
 	APLValueMath,
 	APLValueMath_MathOperator as MathOperator,
 	APLValueMax,
 	APLValueMin,
+	APLValueNamedExpression,
 	APLValueNot,
 	APLValueNumberTargets,
 	APLValueOr,
@@ -61,8 +64,13 @@ import {
 	APLValueSpellIsReady,
 	APLValueSpellTimeToReady,
 	APLValueSpellTravelTime,
+	APLValueTargetTimeToDie,
+	APLValueTargetTimeToPercent,
+	APLValueThreatPercentOfTank,
 	APLValueTimeToEnergyTick,
+	APLValueTimeUntilNextDowntime,
 	APLValueTotemRemainingTime,
+	APLValueVariable,
 	APLValueWarlockCurrentPetMana,
 	APLValueWarlockCurrentPetManaPercent,
 	APLValueWarlockPetIsActive,
@@ -589,6 +597,47 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		newValue: APLValueNumberTargets.create,
 		fields: [],
 	}),
+	timeUntilNextDowntime: inputBuilder({
+		label: 'Time Until Next Downtime',
+		submenu: ['Encounter'],
+		shortDescription: 'Time until the encounter next stops you from acting normally, or <b>0</b> during downtime.',
+		fullDescription: `
+			<p>If there is no more downtime, this is the remaining fight duration.</p>
+		`,
+		newValue: APLValueTimeUntilNextDowntime.create,
+		fields: [],
+	}),
+	downtimeRemaining: inputBuilder({
+		label: 'Downtime Remaining',
+		submenu: ['Encounter'],
+		shortDescription: 'Time until the current downtime ends, or <b>0</b> outside of downtime.',
+		newValue: APLValueDowntimeRemaining.create,
+		fields: [],
+	}),
+	targetTimeToDie: inputBuilder({
+		label: 'Target Time To Die',
+		submenu: ['Encounter'],
+		shortDescription: 'Estimated time until the target dies or leaves the fight.',
+		fullDescription: `
+		<p>Targets which die at zero health, and all targets in health-based fights, are estimated from the damage they have taken so far. In time-based fights this is the remaining fight duration.</p>
+		<p>Never longer than the remaining fight duration or the time until the target despawns.</p>
+		`,
+		newValue: APLValueTargetTimeToDie.create,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets')],
+	}),
+	targetTimeToPercent: inputBuilder({
+		label: 'Target Time To Health %',
+		submenu: ['Encounter'],
+		shortDescription: 'Estimated time until the target drops to the given health percent, or <b>0</b> if it already has.',
+		newValue: APLValueTargetTimeToPercent.create,
+		fields: [
+			AplHelpers.unitFieldConfig('targetUnit', 'targets'),
+			AplHelpers.numberFieldConfig('percent', true, {
+				label: 'Health %',
+				labelTooltip: 'Target health percent, from 0 to 100.',
+			}),
+		],
+	}),
 	frontOfTarget: inputBuilder({
 		label: 'Front of Target',
 		submenu: ['Encounter'],
@@ -597,6 +646,25 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		fields: [],
 	}),
 
+	// Threat
+	isTanking: inputBuilder({
+		label: 'Is Tanking',
+		submenu: ['Threat'],
+		shortDescription: '<b>True</b> if any target is currently attacking you.',
+		newValue: APLValueIsTanking.create,
+		fields: [],
+	}),
+	threatPercentOfTank: inputBuilder({
+		label: 'Threat (% of Tank)',
+		submenu: ['Threat'],
+		shortDescription: 'Your threat on your current target, as a percentage of the threat of whoever it is attacking.',
+		fullDescription: `
+			<p>Targets switch to you once this goes above 110% in melee range, or 130% at range.</p>
+		`,
+		newValue: APLValueThreatPercentOfTank.create,
+		fields: [],
+	}),
+
 	// Resources
 	currentHealth: inputBuilder({
 		label: 'Health',
@@ -900,6 +968,20 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		newValue: APLValueDotRemainingTime.create,
 		fields: [AplHelpers.unitFieldConfig('targetUnit', 'targets'), AplHelpers.actionIdFieldConfig('spellId', 'dot_spells', '')],
 	}),
+	variable: inputBuilder({
+		label: 'Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Current value of a variable from the <b>Variables</b> list.',
+		newValue: APLValueVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
+	namedExpression: inputBuilder({
+		label: 'Named Expression',
+		submenu: ['Variables'],
+		shortDescription: 'Value of an expression from the <b>Named Expressions</b> list.',
+		newValue: APLValueNamedExpression.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
 	sequenceIsComplete: inputBuilder({
 		label: 'Sequence Is Complete',
 		submenu: ['Sequence'],
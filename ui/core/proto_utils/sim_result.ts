@@ -1,5 +1,6 @@
 import {
 	ActionMetrics as ActionMetricsProto,
+	APLStats,
 	AuraMetrics as AuraMetricsProto,
 	DistributionMetrics as DistributionMetricsProto,
 	EncounterMetrics as EncounterMetricsProto,
@@ -314,6 +315,7 @@ export class UnitMetrics {
 	readonly auras: Array<AuraMetrics>;
 	readonly resources: Array<ResourceMetrics>;
 	readonly pets: Array<UnitMetrics>;
+	readonly rotationStats: APLStats | undefined;
 	private readonly iterations: number;
 	private readonly duration: number;
 
@@ -365,6 +367,7 @@ export class UnitMetrics {
 		this.auras = auras;
 		this.resources = resources;
 		this.pets = pets;
+		this.rotationStats = metrics.rotationStats;
 		this.logs = logs;
 		this.iterations = resultData.iterations;
 		this.duration = resultData.duration;
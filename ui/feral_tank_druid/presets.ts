@@ -14,9 +14,11 @@ import {
 
 import * as PresetUtils from '../core/preset_utils.js';
 
-import BlankGear from './gear_sets/blank.gear.json';
+import Phase1Gear from './gear_sets/phase_1.gear.json';
+import Phase4Gear from './gear_sets/phase_4.gear.json';
 
-import DefaultApl from './apls/default.apl.json';
+import Phase1APL from './apls/phase_1.apl.json';
+import Phase4APL from './apls/phase_4.apl.json';
 
 // Preset options for this spec.
 // Eventually we will import these values for the raid sim too, so its good to
@@ -26,18 +28,25 @@ import DefaultApl from './apls/default.apl.json';
 //                                 Gear Presets
 ///////////////////////////////////////////////////////////////////////////
 
-export const GearBlank = PresetUtils.makePresetGear('Blank', BlankGear);
+export const GearPhase1 = PresetUtils.makePresetGear('Phase 1', Phase1Gear, {
+	customCondition: player => player.getLevel() === 25,
+});
+export const GearPhase4 = PresetUtils.makePresetGear('Phase 4', Phase4Gear, {
+	customCondition: player => player.getLevel() === 60,
+});
 
 export const GearPresets = {
   [Phase.Phase1]: [
-    GearBlank,
+    GearPhase1,
   ],
   [Phase.Phase2]: [
-  ]
+  ],
+  [Phase.Phase4]: [
+    GearPhase4,
+  ],
 };
 
-// TODO: Add Phase 2 preset and pull from map
-export const DefaultGear = GearPresets[Phase.Phase1][0];
+export const DefaultGear = GearPresets[Phase.Phase4][0];
 
 ///////////////////////////////////////////////////////////////////////////
 //                                 APL Presets
@@ -49,20 +58,29 @@ export const DefaultRotation = DruidRotation.create({
 	lacerateTime: 8.0,
 });
 
-export const DefaultAPL = PresetUtils.makePresetAPLRotation('Default', DefaultApl);
+export const APLPhase1 = PresetUtils.makePresetAPLRotation('Phase 1', Phase1APL, {
+	customCondition: player => player.getLevel() === 25,
+});
+export const APLPhase4 = PresetUtils.makePresetAPLRotation('Phase 4', Phase4APL, {
+	customCondition: player => player.getLevel() === 60,
+});
 
 export const APLPresets = {
   [Phase.Phase1]: [
-    DefaultAPL,
+    APLPhase1,
   ],
   [Phase.Phase2]: [
-  ]
+  ],
+  [Phase.Phase4]: [
+    APLPhase4,
+  ],
 };
 
-// TODO: Add Phase 2 preset and pull from map
 export const DefaultAPLs: Record<number, PresetUtils.PresetRotation> = {
   25: APLPresets[Phase.Phase1][0],
   40: APLPresets[Phase.Phase1][0],
+  50: APLPresets[Phase.Phase4][0],
+  60: APLPresets[Phase.Phase4][0],
 };
 
 ///////////////////////////////////////////////////////////////////////////
@@ -72,23 +90,25 @@ export const DefaultAPLs: Record<number, PresetUtils.PresetRotation> = {
 // Default talents. Uses the wowhead calculator format, make the talents on
 // https://wowhead.com/classic/talent-calc and copy the numbers in the url.
 
-export const StandardTalents = {
-	name: 'Standard',
-	data: SavedTalents.create({
-		talentsString: '-503232132322010353120300313511-20350001',
-	}),
-};
+export const TalentsPhase1 = PresetUtils.makePresetTalents('Level 25', SavedTalents.create({ talentsString: '-50505001' }), {
+	customCondition: player => player.getLevel() === 25,
+});
+export const TalentsPhase4 = PresetUtils.makePresetTalents('Level 60', SavedTalents.create({ talentsString: '-5052501303022151-550123' }), {
+	customCondition: player => player.getLevel() === 60,
+});
 
 export const TalentPresets = {
   [Phase.Phase1]: [
-    StandardTalents,
+    TalentsPhase1,
   ],
   [Phase.Phase2]: [
-  ]
+  ],
+  [Phase.Phase4]: [
+    TalentsPhase4,
+  ],
 };
 
-// TODO: Add Phase 2 preset and pull from map
-export const DefaultTalents = TalentPresets[Phase.Phase1][0];
+export const DefaultTalents = TalentsPhase4;
 
 ///////////////////////////////////////////////////////////////////////////
 //                                 Options
@@ -13,39 +13,35 @@ import {
 	HealingPriest_Options as Options,
 } from '../core/proto/priest.js';
 import { SavedTalents } from '../core/proto/ui.js';
-import DiscApl from './apls/disc.apl.json';
-import HolyApl from './apls/holy.apl.json';
-import BlankGear from './gear_sets/blank.gear.json';
+import Phase1APL from './apls/phase_1.apl.json';
+import Phase4APL from './apls/phase_4.apl.json';
+import Phase1Gear from './gear_sets/phase_1.gear.json';
+import Phase4Gear from './gear_sets/phase_4.gear.json';
 
 // Preset options for this spec.
 // Eventually we will import these values for the raid sim too, so its good to
 // keep them in a separate file.
 
-export const DiscDefaultGear = PresetUtils.makePresetGear('Blank', BlankGear, { talentTree: 0 });
-export const HolyDefaultGear = PresetUtils.makePresetGear('Blank', BlankGear, { talentTree: 1 });
+export const GearPhase1 = PresetUtils.makePresetGear('Phase 1', Phase1Gear, { talentTree: 1, customCondition: player => player.getLevel() === 25 });
+export const GearPhase4 = PresetUtils.makePresetGear('Phase 4', Phase4Gear, { talentTree: 1, customCondition: player => player.getLevel() === 60 });
 
-export const ROTATION_PRESET_DISC = PresetUtils.makePresetAPLRotation('Disc', DiscApl);
-export const ROTATION_PRESET_HOLY = PresetUtils.makePresetAPLRotation('Holy', HolyApl);
+export const HolyDefaultGear = GearPhase4;
+
+export const APLPhase1 = PresetUtils.makePresetAPLRotation('Phase 1', Phase1APL, { customCondition: player => player.getLevel() === 25 });
+export const APLPhase4 = PresetUtils.makePresetAPLRotation('Phase 4', Phase4APL, { customCondition: player => player.getLevel() === 60 });
 
 // Default talents. Uses the wowhead calculator format, make the talents on
 // https://wowhead.com/classic/talent-calc and copy the numbers in the url.
-export const DiscTalents = {
-	name: 'Disc',
-	data: SavedTalents.create({
-		talentsString: '0503203130300512301313231251-2351010303',
-	}),
-};
-export const HolyTalents = {
-	name: 'Holy',
-	data: SavedTalents.create({
-		talentsString: '05032031103-234051032002152530004311051',
-	}),
-};
+export const TalentsPhase1 = PresetUtils.makePresetTalents('Level 25', SavedTalents.create({ talentsString: '-03505003' }), {
+	customCondition: player => player.getLevel() === 25,
+});
+export const TalentsPhase4 = PresetUtils.makePresetTalents('Level 60', SavedTalents.create({ talentsString: '0551001305-035050031300155' }), {
+	customCondition: player => player.getLevel() === 60,
+});
 
 export const DefaultOptions = Options.create({
 	useInnerFire: true,
 	useShadowfiend: true,
-	rapturesPerMinute: 5,
 
 	powerInfusionTarget: UnitReference.create(),
 });
@@ -51,7 +51,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecHealingPriest, {
 
 	defaults: {
 		// Default equipped gear.
-		gear: Presets.DiscDefaultGear.gear,
+		gear: Presets.HolyDefaultGear.gear,
 		// Default EP weights for sorting gear in the gear picker.
 		epWeights: Stats.fromMap({
 			[Stat.StatIntellect]: 2.73,
@@ -64,7 +64,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecHealingPriest, {
 		// Default consumes settings.
 		consumes: Presets.DefaultConsumes,
 		// Default talents.
-		talents: Presets.DiscTalents.data,
+		talents: Presets.TalentsPhase4.data,
 		// Default spec-specific settings.
 		specOptions: Presets.DefaultOptions,
 		// Default raid/party buffs settings.
@@ -97,62 +97,37 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecHealingPriest, {
 	presets: {
 		// Preset talents that the user can quickly select.
 		talents: [
-			Presets.DiscTalents,
-			Presets.HolyTalents,
+			Presets.TalentsPhase1,
+			Presets.TalentsPhase4,
 		],
 		// Preset rotations that the user can quickly select.
 		rotations: [
-			Presets.ROTATION_PRESET_DISC,
-			Presets.ROTATION_PRESET_HOLY,
+			Presets.APLPhase1,
+			Presets.APLPhase4,
 		],
 		// Preset gear configurations that the user can quickly select.
 		gear: [
-			Presets.DiscDefaultGear,
-			Presets.HolyDefaultGear,
+			Presets.GearPhase1,
+			Presets.GearPhase4,
 		],
 	},
 
 	autoRotation: (player: Player<Spec.SpecHealingPriest>): APLRotation => {
-		const talentTree = player.getTalentTree();
-		if (talentTree == 0) {
-			return Presets.ROTATION_PRESET_DISC.rotation.rotation!;
+		if (player.getLevel() < 60) {
+			return Presets.APLPhase1.rotation.rotation!;
 		} else {
-			return Presets.ROTATION_PRESET_HOLY.rotation.rotation!;
+			return Presets.APLPhase4.rotation.rotation!;
 		}
 	},
 
 	raidSimPresets: [
-		{
-			spec: Spec.SpecHealingPriest,
-			tooltip: 'Discipline Priest',
-			defaultName: 'Discipline',
-			iconUrl: getSpecIcon(Class.ClassPriest, 0),
-
-			talents: Presets.DiscTalents.data,
-			specOptions: Presets.DefaultOptions,
-			consumes: Presets.DefaultConsumes,
-			defaultFactionRaces: {
-				[Faction.Unknown]: Race.RaceUnknown,
-				[Faction.Alliance]: Race.RaceDwarf,
-				[Faction.Horde]: Race.RaceUndead,
-			},
-			defaultGear: {
-				[Faction.Unknown]: {},
-				[Faction.Alliance]: {
-					1: Presets.DiscDefaultGear.gear,
-				},
-				[Faction.Horde]: {
-					1: Presets.DiscDefaultGear.gear,
-				},
-			},
-		},
 		{
 			spec: Spec.SpecHealingPriest,
 			tooltip: 'Holy Priest',
 			defaultName: 'Holy',
 			iconUrl: getSpecIcon(Class.ClassPriest, 1),
 
-			talents: Presets.HolyTalents.data,
+			talents: Presets.TalentsPhase4.data,
 			specOptions: Presets.DefaultOptions,
 			consumes: Presets.DefaultConsumes,
 			defaultFactionRaces: {
@@ -2,28 +2,39 @@ import * as PresetUtils from '../core/preset_utils.js';
 import { Consumes, Debuffs, Flask, Food, IndividualBuffs, PartyBuffs, RaidBuffs, TristateEffect, UnitReference } from '../core/proto/common.js';
 import { RestorationDruid_Options as RestorationDruidOptions } from '../core/proto/druid.js';
 import { SavedTalents } from '../core/proto/ui.js';
-import BlankGear from './gear_sets/blank.gear.json';
+import Phase1APL from './apls/phase_1.apl.json';
+import Phase4APL from './apls/phase_4.apl.json';
+import Phase1Gear from './gear_sets/phase_1.gear.json';
+import Phase4Gear from './gear_sets/phase_4.gear.json';
 
 // Preset options for this spec.
 // Eventually we will import these values for the raid sim too, so its good to
 // keep them in a separate file.
 
-export const DefaultGear = PresetUtils.makePresetGear('Blank', BlankGear);
+export const GearPhase1 = PresetUtils.makePresetGear('Phase 1', Phase1Gear, {
+	customCondition: player => player.getLevel() === 25,
+});
+export const GearPhase4 = PresetUtils.makePresetGear('Phase 4', Phase4Gear, {
+	customCondition: player => player.getLevel() === 60,
+});
+
+export const DefaultGear = GearPhase4;
+
+export const APLPhase1 = PresetUtils.makePresetAPLRotation('Phase 1', Phase1APL, {
+	customCondition: player => player.getLevel() === 25,
+});
+export const APLPhase4 = PresetUtils.makePresetAPLRotation('Phase 4', Phase4APL, {
+	customCondition: player => player.getLevel() === 60,
+});
 
 // Default talents. Uses the wowhead calculator format, make the talents on
 // https://wowhead.com/classic/talent-calc and copy the numbers in the url.
-export const CelestialFocusTalents = {
-	name: 'Celestial Focus',
-	data: SavedTalents.create({
-		talentsString: '05320031103--230023312131502331050313051',
-	}),
-};
-export const ThiccRestoTalents = {
-	name: 'Thicc Resto',
-	data: SavedTalents.create({
-		talentsString: '05320001--230023312331502531053313051',
-	}),
-};
+export const TalentsPhase1 = PresetUtils.makePresetTalents('Level 25', SavedTalents.create({ talentsString: '--0555001' }), {
+	customCondition: player => player.getLevel() === 25,
+});
+export const TalentsPhase4 = PresetUtils.makePresetTalents('Level 60', SavedTalents.create({ talentsString: '511--055503155315051' }), {
+	customCondition: player => player.getLevel() === 60,
+});
 
 export const DefaultOptions = RestorationDruidOptions.create({
 	innervateTarget: UnitReference.create(),
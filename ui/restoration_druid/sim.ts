@@ -62,7 +62,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationDruid, {
 		// Default consumes settings.
 		consumes: Presets.DefaultConsumes,
 		// Default talents.
-		talents: Presets.CelestialFocusTalents.data,
+		talents: Presets.TalentsPhase4.data,
 		// Default spec-specific settings.
 		specOptions: Presets.DefaultOptions,
 		// Default raid/party buffs settings.
@@ -100,19 +100,26 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationDruid, {
 	presets: {
 		// Preset talents that the user can quickly select.
 		talents: [
-			Presets.CelestialFocusTalents,
-			Presets.ThiccRestoTalents,
+			Presets.TalentsPhase1,
+			Presets.TalentsPhase4,
 		],
 		rotations: [
+			Presets.APLPhase1,
+			Presets.APLPhase4,
 		],
 		// Preset gear configurations that the user can quickly select.
 		gear: [
-			Presets.DefaultGear,
+			Presets.GearPhase1,
+			Presets.GearPhase4,
 		],
 	},
 
-	autoRotation: (_player: Player<Spec.SpecRestorationDruid>): APLRotation => {
-		return APLRotation.create();
+	autoRotation: (player: Player<Spec.SpecRestorationDruid>): APLRotation => {
+		if (player.getLevel() < 60) {
+			return Presets.APLPhase1.rotation.rotation!;
+		} else {
+			return Presets.APLPhase4.rotation.rotation!;
+		}
 	},
 
 	raidSimPresets: [
@@ -122,7 +129,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationDruid, {
 			defaultName: 'Restoration',
 			iconUrl: getSpecIcon(Class.ClassDruid, 2),
 
-			talents: Presets.CelestialFocusTalents.data,
+			talents: Presets.TalentsPhase4.data,
 			specOptions: Presets.DefaultOptions,
 			consumes: Presets.DefaultConsumes,
 			defaultFactionRaces: {
@@ -48,7 +48,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationShaman, {
 		// Default consumes settings.
 		consumes: Presets.DefaultConsumes,
 		// Default talents.
-		talents: Presets.RaidHealingTalents.data,
+		talents: Presets.TalentsPhase4.data,
 		// Default spec-specific settings.
 		specOptions: Presets.DefaultOptions,
 		// Default raid/party buffs settings.
@@ -86,14 +86,18 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationShaman, {
 
 	presets: {
 		// Preset talents that the user can quickly select.
-		talents: [Presets.RaidHealingTalents, Presets.TankHealingTalents],
-		rotations: [],
+		talents: [Presets.TalentsPhase1, Presets.TalentsPhase4],
+		rotations: [Presets.APLPhase1, Presets.APLPhase4],
 		// Preset gear configurations that the user can quickly select.
-		gear: [Presets.DefaultGear],
+		gear: [Presets.GearPhase1, Presets.GearPhase4],
 	},
 
-	autoRotation: (_player: Player<Spec.SpecRestorationShaman>): APLRotation => {
-		return APLRotation.create();
+	autoRotation: (player: Player<Spec.SpecRestorationShaman>): APLRotation => {
+		if (player.getLevel() < 60) {
+			return Presets.APLPhase1.rotation.rotation!;
+		} else {
+			return Presets.APLPhase4.rotation.rotation!;
+		}
 	},
 
 	raidSimPresets: [
@@ -103,7 +107,7 @@ const SPEC_CONFIG = registerSpecConfig(Spec.SpecRestorationShaman, {
 			defaultName: 'Restoration',
 			iconUrl: getSpecIcon(Class.ClassShaman, 2),
 
-			talents: Presets.RaidHealingTalents.data,
+			talents: Presets.TalentsPhase4.data,
 			specOptions: Presets.DefaultOptions,
 			consumes: Presets.DefaultConsumes,
 			defaultFactionRaces: {